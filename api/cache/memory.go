@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"mini_pos/domain"
+)
+
+type memoryItem struct {
+	value     string
+	expiresAt time.Time
+}
+
+type memoryCache struct {
+	mu    sync.Mutex
+	items map[string]memoryItem
+}
+
+// NewMemoryCache will create an object that represent the domain.Cache interface in the process memory,
+// it is meant for tests and single instance setups
+func NewMemoryCache() domain.Cache {
+	return &memoryCache{items: make(map[string]memoryItem)}
+}
+
+func (m *memoryCache) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item := memoryItem{value: value}
+	if ttl > 0 {
+		item.expiresAt = time.Now().Add(ttl)
+	}
+	m.items[key] = item
+	return nil
+}
+
+func (m *memoryCache) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.items[key]
+	if !ok {
+		return "", domain.ErrNotFound
+	}
+	if !item.expiresAt.IsZero() && !time.Now().Before(item.expiresAt) {
+		delete(m.items, key)
+		return "", domain.ErrNotFound
+	}
+	return item.value, nil
+}
+
+func (m *memoryCache) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.items, key)
+	return nil
+}
+
+func (m *memoryCache) Take(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.items[key]
+	if !ok {
+		return "", domain.ErrNotFound
+	}
+	delete(m.items, key)
+	if !item.expiresAt.IsZero() && !time.Now().Before(item.expiresAt) {
+		return "", domain.ErrNotFound
+	}
+	return item.value, nil
+}
+
+func (m *memoryCache) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.items[key]
+	if ok && !item.expiresAt.IsZero() && !time.Now().Before(item.expiresAt) {
+		ok = false
+	}
+	n := int64(0)
+	if ok {
+		var err error
+		n, err = strconv.ParseInt(item.value, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		item = memoryItem{}
+	}
+	n++
+	item.value = strconv.FormatInt(n, 10)
+	if expiresAt := time.Now().Add(ttl); ttl > 0 && item.expiresAt.Before(expiresAt) {
+		item.expiresAt = expiresAt
+	}
+	m.items[key] = item
+	return n, nil
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/domain"
+)
+
+type redisCache struct {
+	Pool *redis.Pool
+}
+
+// NewRedisCache will create an object that represent the domain.Cache interface on a redis server
+func NewRedisCache(pool *redis.Pool) domain.Cache {
+	return &redisCache{pool}
+}
+
+func (m *redisCache) do(ctx context.Context, cmd string, args ...interface{}) (reply interface{}, err error) {
+	conn, err := m.Pool.GetContext(ctx)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errConn := conn.Close()
+		if errConn != nil {
+			logrus.Error(errConn)
+		}
+	}()
+
+	return conn.Do(cmd, args...)
+}
+
+func (m *redisCache) Set(ctx context.Context, key string, value string, ttl time.Duration) (err error) {
+	if ttl > 0 {
+		_, err = m.do(ctx, "SET", key, value, "PX", ttl.Milliseconds())
+		return
+	}
+	_, err = m.do(ctx, "SET", key, value)
+	return
+}
+
+func (m *redisCache) Get(ctx context.Context, key string) (res string, err error) {
+	res, err = redis.String(m.do(ctx, "GET", key))
+	if err == redis.ErrNil {
+		return "", domain.ErrNotFound
+	}
+	return
+}
+
+// takeScript get and delete a key in one step, GETDEL is not available before redis 6.2
+const takeScript = `local v = redis.call('GET', KEYS[1])
+if v then redis.call('DEL', KEYS[1]) end
+return v`
+
+func (m *redisCache) Take(ctx context.Context, key string) (res string, err error) {
+	res, err = redis.String(m.do(ctx, "EVAL", takeScript, 1, key))
+	if err == redis.ErrNil {
+		return "", domain.ErrNotFound
+	}
+	return
+}
+
+// incrScript add one to a counter and extend its expiry to the ttl, never shorten it
+const incrScript = `local n = redis.call('INCR', KEYS[1])
+if redis.call('PTTL', KEYS[1]) < tonumber(ARGV[1]) then redis.call('PEXPIRE', KEYS[1], ARGV[1]) end
+return n`
+
+func (m *redisCache) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return redis.Int64(m.do(ctx, "EVAL", incrScript, 1, key, ttl.Milliseconds()))
+}
+
+func (m *redisCache) Delete(ctx context.Context, key string) (err error) {
+	_, err = m.do(ctx, "DEL", key)
+	return
+}
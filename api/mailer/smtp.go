@@ -0,0 +1,135 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"html/template"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"mini_pos/config"
+	"mini_pos/constants"
+	"mini_pos/domain"
+)
+
+type smtpMailer struct {
+	cfg config.Mailer
+}
+
+// NewSMTPMailer will create an object that represent the domain.Mailer interface over the SMTP server of the config,
+// the authentication is skipped when no username is set so a local fake server can be used
+func NewSMTPMailer(cfg config.Mailer) domain.Mailer {
+	return &smtpMailer{cfg}
+}
+
+// Render execute the page template inside the main mail layout
+func Render(page string, data interface{}) (string, error) {
+	tmpl, err := template.ParseFiles(constants.MailMainTemplate, constants.MailLogoTemplate, constants.MailFooterTemplate, page)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = tmpl.ExecuteTemplate(&buf, "main", data)
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (m *smtpMailer) Send(ctx context.Context, to string, subject string, page string, data interface{}) (err error) {
+	body, err := Render(page, data)
+	if err != nil {
+		return
+	}
+
+	msg := strings.Join([]string{
+		"From: " + m.cfg.Sender,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/html; charset=\"UTF-8\"",
+		"",
+		body,
+	}, "\r\n")
+
+	client, err := m.dial(ctx)
+	if err != nil {
+		return
+	}
+	defer client.Close()
+
+	if m.cfg.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Server))
+		if err != nil {
+			return
+		}
+	}
+	err = client.Mail(senderAddress(m.cfg.Sender, m.cfg.Username))
+	if err != nil {
+		return
+	}
+	err = client.Rcpt(to)
+	if err != nil {
+		return
+	}
+	w, err := client.Data()
+	if err != nil {
+		return
+	}
+	_, err = w.Write([]byte(msg))
+	if err != nil {
+		return
+	}
+	err = w.Close()
+	if err != nil {
+		return
+	}
+	return client.Quit()
+}
+
+// dial open the SMTP session, over TLS from the start when UseTls is set, or upgraded with STARTTLS when offered
+func (m *smtpMailer) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(m.cfg.Server, strconv.Itoa(m.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: m.cfg.Server}
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if m.cfg.UseTls {
+		conn = tls.Client(conn, tlsConfig)
+	}
+	client, err := smtp.NewClient(conn, m.cfg.Server)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok && !m.cfg.UseTls {
+		err = client.StartTLS(tlsConfig)
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// senderAddress return the address of a sender written as "Name <address>", or the username when it holds none
+func senderAddress(sender string, username string) string {
+	start := strings.LastIndex(sender, "<")
+	end := strings.LastIndex(sender, ">")
+	if start >= 0 && end > start {
+		address := strings.TrimSpace(sender[start+1 : end])
+		if strings.Contains(address, "@") {
+			return address
+		}
+	}
+	if strings.Contains(sender, "@") {
+		return strings.TrimSpace(sender)
+	}
+	return username
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+	echo "github.com/labstack/echo/v4"
+
+	"mini_pos/api/common"
+	"mini_pos/constants"
+	"mini_pos/domain"
+)
+
+// HeaderApiKey carry the API key of a machine integration, a key may also be sent as the bearer token
+const HeaderApiKey = "X-API-Key"
+
+// ApiKeyAuthenticator return the active key of a request
+type ApiKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string, ip string) (domain.ApiKey, error)
+}
+
+var apiKeys ApiKeyAuthenticator
+
+// SetApiKeys set the authenticator of JWTMiddleware, no API key is accepted when it is not set
+func SetApiKeys(a ApiKeyAuthenticator) {
+	apiKeys = a
+}
+
+// apiKeyOf return the API key sent with the request, empty when the request carries none
+func apiKeyOf(c echo.Context) string {
+	if apiKeys == nil {
+		return ""
+	}
+	if key := c.Request().Header.Get(HeaderApiKey); key != "" {
+		return key
+	}
+	auth := c.Request().Header.Get(echo.HeaderAuthorization)
+	if strings.HasPrefix(auth, "Bearer "+domain.ApiKeyPrefix) {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return ""
+}
+
+// authenticateApiKey put the claims of the key in the context, as a token would, so Permission and the
+// outlet checks apply to the key unchanged
+func authenticateApiKey(c echo.Context, key string, next echo.HandlerFunc) error {
+	res, err := apiKeys.Authenticate(c.Request().Context(), key, c.RealIP())
+	if err == constants.ErrApiKeyInvalid {
+		return c.JSON(http.StatusUnauthorized, common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: err.Error(),
+			Data:    map[string]interface{}{},
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, common.ControllerResponse{
+			Code:    http.StatusInternalServerError,
+			Message: domain.ErrInternalServerError.Error(),
+			Data:    map[string]interface{}{},
+		})
+	}
+
+	claims := &JwtCustomClaims{
+		Name:        res.Name,
+		ID:          int(res.CreatedBy),
+		Permissions: res.Permissions,
+		Outlets:     res.Outlets,
+		ApiKeyID:    res.ID,
+	}
+	c.Set("user", &jwt.Token{Claims: claims, Valid: true})
+	return next(c)
+}
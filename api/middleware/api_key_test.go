@@ -0,0 +1,58 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	middlwr "mini_pos/api/middleware"
+	"mini_pos/config"
+	"mini_pos/constants"
+	"mini_pos/domain"
+)
+
+type stubApiKeys struct{}
+
+func (stubApiKeys) Authenticate(ctx context.Context, key string, ip string) (domain.ApiKey, error) {
+	if key != "mpk_sync" {
+		return domain.ApiKey{}, constants.ErrApiKeyInvalid
+	}
+	return domain.ApiKey{ID: 3, Name: "sync", CreatedBy: 7, Permissions: []string{domain.PermProductRead}, Outlets: []int64{2}}, nil
+}
+
+func TestApiKey(t *testing.T) {
+	middlwr.SetApiKeys(stubApiKeys{})
+	defer middlwr.SetApiKeys(nil)
+
+	e := echo.New()
+	cfg := config.Config{JWTConfig: config.JWTConfig{Secret: "secret"}}
+	ok := func(c echo.Context) error {
+		claims := middlwr.GetTokenFromContext(c)
+		assert.Equal(t, int64(3), claims.ApiKeyID)
+		assert.Equal(t, 7, claims.ID)
+		assert.True(t, claims.HasOutlet(2))
+		return c.NoContent(http.StatusOK)
+	}
+	e.GET("/products", ok, middlwr.JWTMiddleware(cfg), middlwr.Permission(domain.PermProductRead))
+	e.GET("/purchases", ok, middlwr.JWTMiddleware(cfg), middlwr.Permission(domain.PermPurchaseRead))
+	e.GET("/me", ok, middlwr.UserMiddleware(cfg))
+
+	serve := func(path string, header string, value string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(header, value)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve("/products", middlwr.HeaderApiKey, "mpk_sync"))
+	assert.Equal(t, http.StatusOK, serve("/products", echo.HeaderAuthorization, "Bearer mpk_sync"))
+	assert.Equal(t, http.StatusForbidden, serve("/purchases", middlwr.HeaderApiKey, "mpk_sync"))
+	assert.Equal(t, http.StatusUnauthorized, serve("/products", middlwr.HeaderApiKey, "mpk_gone"))
+	// the endpoints of the account of the caller take no API key
+	assert.Equal(t, http.StatusUnauthorized, serve("/me", echo.HeaderAuthorization, "Bearer mpk_sync"))
+}
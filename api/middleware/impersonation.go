@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/api/common"
+	"mini_pos/constants"
+	"mini_pos/domain"
+)
+
+// Auditor keep the trail of what is done with the impersonation tokens
+type Auditor interface {
+	Record(ctx context.Context, m *domain.AuditLog) error
+	SetStatus(ctx context.Context, id int64, status int) error
+}
+
+var auditor Auditor
+
+// SetAuditor set the auditor of the writes made with an impersonation token, they are not recorded when it is not set
+func SetAuditor(a Auditor) {
+	auditor = a
+}
+
+// Impersonating return true on a token issued to a support staff acting as the user of the token
+func (c *JwtCustomClaims) Impersonating() bool {
+	return c.ActorID != 0
+}
+
+// auditImpersonation record every write made with an impersonation token against the real user behind it,
+// whatever the usecase the request ends in. The log is written before the handler runs and the write is
+// refused when it cannot be, the status of the answer is filled in afterwards
+func auditImpersonation(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := GetTokenFromContext(c)
+		if auditor == nil || claims == nil || !claims.Impersonating() {
+			return next(c)
+		}
+		switch c.Request().Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return next(c)
+		}
+
+		path := c.Request().URL.Path
+		if len(path) > 255 {
+			path = path[:255]
+		}
+		log := &domain.AuditLog{
+			ActorID: claims.ActorID,
+			UserID:  claims.ID,
+			Action:  domain.AuditWrite,
+			Method:  c.Request().Method,
+			Path:    path,
+			IP:      c.RealIP(),
+		}
+		if err := auditor.Record(c.Request().Context(), log); err != nil {
+			logrus.Error(err)
+			return c.JSON(http.StatusInternalServerError, common.ControllerResponse{
+				Code:    http.StatusInternalServerError,
+				Message: constants.ErrImpersonationAudit.Error(),
+				Data:    map[string]interface{}{},
+			})
+		}
+
+		err := next(c)
+		status := c.Response().Status
+		if he, ok := err.(*echo.HTTPError); ok && !c.Response().Committed {
+			status = he.Code
+		}
+		// the write is already logged, a missing status is left to the application log
+		if errStatus := auditor.SetStatus(c.Request().Context(), log.ID, status); errStatus != nil {
+			logrus.Error(errStatus)
+		}
+		return err
+	}
+}
+
+// NotImpersonating refuse the route to an impersonation token, for the credentials of the account
+// and for starting another impersonation. It must run after UserMiddleware
+func NotImpersonating(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := GetTokenFromContext(c)
+		if claims != nil && claims.Impersonating() {
+			return forbidden(c, constants.ErrImpersonating)
+		}
+		return next(c)
+	}
+}
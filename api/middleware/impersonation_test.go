@@ -0,0 +1,86 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	middlwr "mini_pos/api/middleware"
+	"mini_pos/config"
+	"mini_pos/domain"
+)
+
+type stubAuditor struct {
+	logs []domain.AuditLog
+	down bool
+}
+
+func (m *stubAuditor) Record(ctx context.Context, a *domain.AuditLog) error {
+	if m.down {
+		return errors.New("audit_logs is down")
+	}
+	m.logs = append(m.logs, *a)
+	a.ID = int64(len(m.logs))
+	return nil
+}
+
+func (m *stubAuditor) SetStatus(ctx context.Context, id int64, status int) error {
+	m.logs[id-1].Status = status
+	return nil
+}
+
+func TestImpersonation(t *testing.T) {
+	auditor := &stubAuditor{}
+	middlwr.SetAuditor(auditor)
+	defer middlwr.SetAuditor(nil)
+
+	e := echo.New()
+	cfg := config.Config{JWTConfig: config.JWTConfig{Secret: "secret"}}
+	ok := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+	e.GET("/products", ok, middlwr.UserMiddleware(cfg))
+	e.POST("/products", ok, middlwr.UserMiddleware(cfg))
+	e.PUT("/me/password", ok, middlwr.UserMiddleware(cfg), middlwr.NotImpersonating)
+
+	serve := func(method string, path string, claims *middlwr.JwtCustomClaims) int {
+		token, err := middlwr.Keys(cfg).Sign(claims)
+		assert.NoError(t, err)
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// the writes of an impersonation token are recorded against the support staff, the reads are not
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/products", &middlwr.JwtCustomClaims{ID: 7, ActorID: 1}))
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/products", &middlwr.JwtCustomClaims{ID: 7, ActorID: 1}))
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/products", &middlwr.JwtCustomClaims{ID: 7}))
+	if assert.Len(t, auditor.logs, 1) {
+		assert.Equal(t, 1, auditor.logs[0].ActorID)
+		assert.Equal(t, 7, auditor.logs[0].UserID)
+		assert.Equal(t, "/products", auditor.logs[0].Path)
+		assert.Equal(t, http.StatusOK, auditor.logs[0].Status)
+	}
+
+	// the credentials of the account stay out of reach of the support staff
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPut, "/me/password", &middlwr.JwtCustomClaims{ID: 7, ActorID: 1}))
+	assert.Equal(t, http.StatusOK, serve(http.MethodPut, "/me/password", &middlwr.JwtCustomClaims{ID: 7}))
+	assert.Len(t, auditor.logs, 2)
+
+	// a write that cannot be audited is not made
+	written := false
+	e.DELETE("/products", func(c echo.Context) error {
+		written = true
+		return c.NoContent(http.StatusOK)
+	}, middlwr.UserMiddleware(cfg))
+	auditor.down = true
+	assert.Equal(t, http.StatusInternalServerError, serve(http.MethodDelete, "/products", &middlwr.JwtCustomClaims{ID: 7, ActorID: 1}))
+	assert.False(t, written)
+}
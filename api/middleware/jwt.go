@@ -1,8 +1,13 @@
 package middleware
 
 import (
+	"context"
+	"net/http"
+
 	"github.com/golang-jwt/jwt"
+	"mini_pos/api/common"
 	"mini_pos/config"
+	"mini_pos/constants"
 
 	echo "github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -14,9 +19,23 @@ type JwtCustomClaims struct {
 	ID       int    `json:"id"`
 	RoleID int    `json:"role_id"`
 	UserName string `json:"username"`
+	Permissions []string `json:"permissions"`
+	Outlets []int64 `json:"outlets"`
+	ApiKeyID int64 `json:"api_key_id,omitempty"` // set when the caller is a machine integration, ID is then the creator of the key
+	TerminalID int64 `json:"terminal_id,omitempty"` // set on the tokens of a cashier switched in on a terminal
+	ActorID int `json:"actor_id,omitempty"` // set on an impersonation token, the support staff acting as the user ID
+	IssuedAtMs int64 `json:"iat_ms,omitempty"` // issue time in milliseconds, compared with the revocation of the sessions
 	jwt.StandardClaims
 }
 
+// IssuedAtMillis return the issue time of the token in milliseconds, from the whole seconds of iat on older tokens
+func (c *JwtCustomClaims) IssuedAtMillis() int64 {
+	if c.IssuedAtMs != 0 {
+		return c.IssuedAtMs
+	}
+	return c.IssuedAt * 1000
+}
+
 var tokenCtxKey = &contextKey{"token"}
 
 type contextKey struct {
@@ -24,17 +43,89 @@ type contextKey struct {
 }
 
 
+// RevocationList tell whether a token that is still valid was revoked before it expired, issuedAt is in milliseconds
+type RevocationList interface {
+	IsRevoked(ctx context.Context, userId int, tokenId string, issuedAt int64) (bool, error)
+	IsTerminalRevoked(ctx context.Context, terminalId int64) (bool, error)
+}
+
+var revocationList RevocationList
+
+// SetRevocationList set the list checked by JWTMiddleware, no token is ever revoked when it is not set
+func SetRevocationList(list RevocationList) {
+	revocationList = list
+}
+
+// JWTMiddleware verify the bearer token with the key set, see Keys. The API key of a machine integration
+// is accepted instead of a token, see SetApiKeys
 func JWTMiddleware(cfg config.Config ) echo.MiddlewareFunc {
-	return middleware.JWTWithConfig(middleware.JWTConfig{
-		SigningMethod: "HS256",
-		Claims: &JwtCustomClaims{},
-		SigningKey:    []byte(cfg.JWTConfig.Secret),
+	userMiddleware := UserMiddleware(cfg)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		withToken := userMiddleware(next)
+		return func(c echo.Context) error {
+			if key := apiKeyOf(c); key != "" {
+				return authenticateApiKey(c, key, next)
+			}
+			return withToken(c)
+		}
+	}
+}
+
+// UserMiddleware accept only the tokens of users, for the endpoints acting on the account of the caller.
+// The writes made with an impersonation token are audited, see SetAuditor
+func UserMiddleware(cfg config.Config ) echo.MiddlewareFunc {
+	keys := Keys(cfg)
+	jwtMiddleware := middleware.JWTWithConfig(middleware.JWTConfig{
+		ParseTokenFunc: func(auth string, c echo.Context) (interface{}, error) {
+			return keys.Parse(auth)
+		},
 	})
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return jwtMiddleware(checkRevoked(auditImpersonation(next)))
+	}
+}
+
+// checkRevoked reject a logged out token, or a token of a user whose sessions were revoked.
+// An impersonation token is rejected as well when the sessions of the support staff were revoked,
+// and a PIN token when its terminal was revoked
+func checkRevoked(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims := GetTokenFromContext(c)
+		if revocationList == nil || claims == nil {
+			return next(c)
+		}
+		revoked, err := revocationList.IsRevoked(c.Request().Context(), claims.ID, claims.Id, claims.IssuedAtMillis())
+		if err == nil && !revoked && claims.Impersonating() {
+			revoked, err = revocationList.IsRevoked(c.Request().Context(), claims.ActorID, "", claims.IssuedAtMillis())
+		}
+		if err == nil && !revoked && claims.TerminalID != 0 {
+			revoked, err = revocationList.IsTerminalRevoked(c.Request().Context(), claims.TerminalID)
+		}
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, common.ControllerResponse{
+				Code:    http.StatusInternalServerError,
+				Message: constants.ErrGetTokenToRedis.Error(),
+				Data:    map[string]interface{}{},
+			})
+		}
+		if revoked {
+			return c.JSON(http.StatusUnauthorized, common.ControllerResponse{
+				Code:    http.StatusUnauthorized,
+				Message: constants.ErrTokenRevoked.Error(),
+				Data:    map[string]interface{}{},
+			})
+		}
+		return next(c)
+	}
 }
 
 // GetTokenFromContext return Token Object inside context data
 func GetTokenFromContext(c echo.Context) *JwtCustomClaims {
 	raw, _ := c.Get("user").(*jwt.Token)
-	return raw.Claims.(*JwtCustomClaims)
+	if raw == nil {
+		return nil
+	}
+	claims, _ := raw.Claims.(*JwtCustomClaims)
+	return claims
 }
 
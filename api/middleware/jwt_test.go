@@ -0,0 +1,51 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	middlwr "mini_pos/api/middleware"
+	"mini_pos/config"
+)
+
+// stubRevocationList revoke only the terminal 3
+type stubRevocationList struct{}
+
+func (stubRevocationList) IsRevoked(ctx context.Context, userId int, tokenId string, issuedAt int64) (bool, error) {
+	return false, nil
+}
+
+func (stubRevocationList) IsTerminalRevoked(ctx context.Context, terminalId int64) (bool, error) {
+	return terminalId == 3, nil
+}
+
+func TestRevokedTerminal(t *testing.T) {
+	middlwr.SetRevocationList(stubRevocationList{})
+	defer middlwr.SetRevocationList(nil)
+
+	e := echo.New()
+	cfg := config.Config{JWTConfig: config.JWTConfig{Secret: "secret"}}
+	e.GET("/products", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, middlwr.JWTMiddleware(cfg))
+
+	serve := func(claims *middlwr.JwtCustomClaims) int {
+		token, err := middlwr.Keys(cfg).Sign(claims)
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodGet, "/products", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// the PIN tokens of a revoked terminal are rejected, the other tokens of the cashier are not
+	assert.Equal(t, http.StatusUnauthorized, serve(&middlwr.JwtCustomClaims{ID: 7, TerminalID: 3}))
+	assert.Equal(t, http.StatusOK, serve(&middlwr.JwtCustomClaims{ID: 7, TerminalID: 4}))
+	assert.Equal(t, http.StatusOK, serve(&middlwr.JwtCustomClaims{ID: 7}))
+}
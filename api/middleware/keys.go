@@ -0,0 +1,264 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+
+	"mini_pos/config"
+	"mini_pos/utils"
+)
+
+// The errors of a token that is well signed but not meant for this service
+var (
+	ErrTokenKeyUnknown = errors.New("token is signed with an unknown key")
+	ErrTokenIssuer     = errors.New("token is issued by an unknown issuer")
+	ErrTokenAudience   = errors.New("token is not meant for this audience")
+	ErrTokenExpiry     = errors.New("token has no expiry")
+)
+
+// JWK is a public key of the key set as published on /.well-known/jwks.json (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is the public part of the key set, other services verify the tokens with it
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type verifyKey struct {
+	method jwt.SigningMethod
+	key    interface{}
+}
+
+// KeySet sign the tokens with the active key and verify them with every key still trusted, a key being rotated
+// out is kept verify only until the tokens signed with it expire.
+// Without any configured key it falls back to HS256 with JWTConfig.Secret, the tokens then carry no kid
+type KeySet struct {
+	signingID string
+	signing   interface{}
+	method    jwt.SigningMethod
+	verify    map[string]verifyKey
+	issuer    string
+	audience  string
+	lifeTime  time.Duration
+	jwks      JWKS
+}
+
+// NewKeySet load the keys of the config from their PEM files
+func NewKeySet(cfg config.JWTConfig) (*KeySet, error) {
+	k := &KeySet{
+		verify:   make(map[string]verifyKey),
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		lifeTime: time.Hour * time.Duration(cfg.TokenLifeTimeHour),
+		jwks:     JWKS{Keys: make([]JWK, 0)},
+	}
+	if cfg.TokenLifeTimeHour == 0 {
+		k.lifeTime = time.Hour
+	}
+
+	if len(cfg.Keys) == 0 {
+		k.method = jwt.SigningMethodHS256
+		k.signing = []byte(cfg.Secret)
+		k.verify[""] = verifyKey{method: jwt.SigningMethodHS256, key: []byte(cfg.Secret)}
+		return k, nil
+	}
+
+	for _, c := range cfg.Keys {
+		if c.ID == "" {
+			return nil, errors.New("jwt key without id")
+		}
+		if _, ok := k.verify[c.ID]; ok {
+			return nil, fmt.Errorf("jwt key %s is configured twice", c.ID)
+		}
+		method, private, public, err := loadKey(c)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %s: %v", c.ID, err)
+		}
+		k.verify[c.ID] = verifyKey{method: method, key: public}
+		k.jwks.Keys = append(k.jwks.Keys, toJWK(c.ID, method, public))
+
+		if c.ID == cfg.SigningKey {
+			if private == nil {
+				return nil, fmt.Errorf("jwt key %s signs the tokens but has no private key", c.ID)
+			}
+			k.signingID = c.ID
+			k.signing = private
+			k.method = method
+		}
+	}
+	if k.signing == nil {
+		return nil, fmt.Errorf("jwt signing key %q is not configured", cfg.SigningKey)
+	}
+	return k, nil
+}
+
+// loadKey read the private key of a key, or only its public key for a key that is verify only
+func loadKey(c config.JWTKey) (method jwt.SigningMethod, private crypto.Signer, public crypto.PublicKey, err error) {
+	var privatePEM, publicPEM []byte
+	if c.PrivateKeyFile != "" {
+		privatePEM, err = ioutil.ReadFile(c.PrivateKeyFile)
+		if err != nil {
+			return
+		}
+	}
+	if c.PublicKeyFile != "" {
+		publicPEM, err = ioutil.ReadFile(c.PublicKeyFile)
+		if err != nil {
+			return
+		}
+	}
+	if privatePEM == nil && publicPEM == nil {
+		return nil, nil, nil, errors.New("no key file")
+	}
+
+	switch c.Algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		method = jwt.SigningMethodRS256
+		if privatePEM != nil {
+			var key *rsa.PrivateKey
+			key, err = jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return
+			}
+			return method, key, &key.PublicKey, nil
+		}
+		public, err = jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+		return
+	case jwt.SigningMethodEdDSA.Alg():
+		method = jwt.SigningMethodEdDSA
+		if privatePEM != nil {
+			var key crypto.PrivateKey
+			key, err = jwt.ParseEdPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return
+			}
+			signer := key.(ed25519.PrivateKey)
+			return method, signer, signer.Public(), nil
+		}
+		public, err = jwt.ParseEdPublicKeyFromPEM(publicPEM)
+		return
+	default:
+		return nil, nil, nil, fmt.Errorf("algorithm %q is not one of RS256 or EdDSA", c.Algorithm)
+	}
+}
+
+func toJWK(id string, method jwt.SigningMethod, public crypto.PublicKey) JWK {
+	enc := base64.RawURLEncoding
+	res := JWK{Kid: id, Use: "sig", Alg: method.Alg()}
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		res.Kty = "RSA"
+		res.N = enc.EncodeToString(key.N.Bytes())
+		res.E = enc.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case ed25519.PublicKey:
+		res.Kty = "OKP"
+		res.Crv = "Ed25519"
+		res.X = enc.EncodeToString(key)
+	}
+	return res
+}
+
+// LifeTime return how long the access tokens are valid
+func (k *KeySet) LifeTime() time.Duration {
+	return k.lifeTime
+}
+
+// Sign set the issuer, audience, issue and expiry times of the claims and sign them with the active key.
+// The id of the token is left to the caller
+func (k *KeySet) Sign(claims *JwtCustomClaims) (string, error) {
+	now := time.Now()
+	claims.Issuer = k.issuer
+	claims.Audience = k.audience
+	claims.IssuedAt = now.Unix()
+	claims.IssuedAtMs = utils.UnixMilli(now)
+	claims.ExpiresAt = now.Add(k.lifeTime).Unix()
+
+	token := jwt.NewWithClaims(k.method, claims)
+	if k.signingID != "" {
+		token.Header["kid"] = k.signingID
+	}
+	return token.SignedString(k.signing)
+}
+
+// Parse verify the signature of a token with the key of its kid, then its expiry, issuer and audience
+func (k *KeySet) Parse(raw string) (*jwt.Token, error) {
+	token, err := jwt.ParseWithClaims(raw, &JwtCustomClaims{}, k.keyFunc)
+	if err != nil {
+		return nil, err
+	}
+	claims := token.Claims.(*JwtCustomClaims)
+	if claims.ExpiresAt == 0 {
+		return nil, ErrTokenExpiry
+	}
+	if k.issuer != "" && !claims.VerifyIssuer(k.issuer, true) {
+		return nil, ErrTokenIssuer
+	}
+	if k.audience != "" && !claims.VerifyAudience(k.audience, true) {
+		return nil, ErrTokenAudience
+	}
+	return token, nil
+}
+
+func (k *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	id, _ := token.Header["kid"].(string)
+	key, ok := k.verify[id]
+	if !ok {
+		return nil, ErrTokenKeyUnknown
+	}
+	// the algorithm of the key is trusted, never the one of the header
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, ErrTokenKeyUnknown
+	}
+	return key.key, nil
+}
+
+// JWKS return the public keys of the set
+func (k *KeySet) JWKS() JWKS {
+	return k.jwks
+}
+
+var (
+	keySet     *KeySet
+	keySetLock sync.Mutex
+)
+
+// SetKeySet set the key set the tokens are signed and verified with
+func SetKeySet(k *KeySet) {
+	keySetLock.Lock()
+	defer keySetLock.Unlock()
+	keySet = k
+}
+
+// Keys return the key set set by SetKeySet, or the HS256 key set of the config when none was set
+func Keys(cfg config.Config) *KeySet {
+	keySetLock.Lock()
+	defer keySetLock.Unlock()
+	if keySet == nil {
+		keySet, _ = NewKeySet(config.JWTConfig{
+			Issuer:            cfg.JWTConfig.Issuer,
+			Audience:          cfg.JWTConfig.Audience,
+			Secret:            cfg.JWTConfig.Secret,
+			TokenLifeTimeHour: cfg.JWTConfig.TokenLifeTimeHour,
+		})
+	}
+	return keySet
+}
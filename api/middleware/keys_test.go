@@ -0,0 +1,105 @@
+package middleware_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+
+	middlwr "mini_pos/api/middleware"
+	"mini_pos/config"
+)
+
+func writePEM(t *testing.T, name string, kind string, der []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}), 0600)
+	assert.NoError(t, err)
+	return path
+}
+
+func TestKeySetRotation(t *testing.T) {
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	edDER, _ := x509.MarshalPKCS8PrivateKey(edKey)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rsaDER, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	edFile := writePEM(t, "new.pem", "PRIVATE KEY", edDER)
+	rsaFile := writePEM(t, "old.pub.pem", "PUBLIC KEY", rsaDER)
+
+	cfg := config.JWTConfig{
+		Issuer:            "pos",
+		Audience:          "mini-pos",
+		TokenLifeTimeHour: 2,
+		SigningKey:        "new",
+		Keys: []config.JWTKey{
+			{ID: "new", Algorithm: "EdDSA", PrivateKeyFile: edFile},
+			{ID: "old", Algorithm: "RS256", PublicKeyFile: rsaFile},
+		},
+	}
+	keys, err := middlwr.NewKeySet(cfg)
+	assert.NoError(t, err)
+
+	claims := &middlwr.JwtCustomClaims{ID: 7}
+	raw, err := keys.Sign(claims)
+	assert.NoError(t, err)
+	assert.Equal(t, "mini-pos", claims.Audience)
+	assert.Equal(t, claims.IssuedAt+int64(2*time.Hour/time.Second), claims.ExpiresAt)
+
+	token, err := keys.Parse(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, "new", token.Header["kid"])
+	assert.Equal(t, 7, token.Claims.(*middlwr.JwtCustomClaims).ID)
+
+	// a token signed before the rotation still verifies with the public key kept
+	old := jwt.NewWithClaims(jwt.SigningMethodRS256, &middlwr.JwtCustomClaims{ID: 8, StandardClaims: jwt.StandardClaims{
+		Issuer: "pos", Audience: "mini-pos", ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}})
+	old.Header["kid"] = "old"
+	raw, _ = old.SignedString(rsaKey)
+	_, err = keys.Parse(raw)
+	assert.NoError(t, err)
+
+	// nor an unknown kid, another issuer or audience, an expired token or an HS256 token pass
+	for _, c := range []struct {
+		kid    string
+		claims jwt.StandardClaims
+	}{
+		{"gone", jwt.StandardClaims{Issuer: "pos", Audience: "mini-pos", ExpiresAt: time.Now().Add(time.Hour).Unix()}},
+		{"old", jwt.StandardClaims{Issuer: "other", Audience: "mini-pos", ExpiresAt: time.Now().Add(time.Hour).Unix()}},
+		{"old", jwt.StandardClaims{Issuer: "pos", Audience: "billing", ExpiresAt: time.Now().Add(time.Hour).Unix()}},
+		{"old", jwt.StandardClaims{Issuer: "pos", Audience: "mini-pos", ExpiresAt: time.Now().Add(-time.Minute).Unix()}},
+		{"old", jwt.StandardClaims{Issuer: "pos", Audience: "mini-pos"}},
+	} {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, &middlwr.JwtCustomClaims{StandardClaims: c.claims})
+		token.Header["kid"] = c.kid
+		raw, _ = token.SignedString(rsaKey)
+		_, err = keys.Parse(raw)
+		assert.Error(t, err)
+	}
+	hs := jwt.NewWithClaims(jwt.SigningMethodHS256, &middlwr.JwtCustomClaims{StandardClaims: jwt.StandardClaims{
+		Issuer: "pos", Audience: "mini-pos", ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}})
+	hs.Header["kid"] = "old"
+	raw, _ = hs.SignedString(rsaDER)
+	_, err = keys.Parse(raw)
+	assert.Error(t, err)
+
+	jwks := keys.JWKS()
+	assert.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Crv)
+	assert.Equal(t, "RSA", jwks.Keys[1].Kty)
+	assert.Equal(t, "AQAB", jwks.Keys[1].E)
+
+	// the signing key must hold a private key
+	cfg.SigningKey = "old"
+	_, err = middlwr.NewKeySet(cfg)
+	assert.Error(t, err)
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	echo "github.com/labstack/echo/v4"
+
+	"mini_pos/constants"
+	"mini_pos/domain"
+)
+
+// HasOutlet return true if the token holder is assigned to the outlet in user_outlets.
+// A holder of PermOutletAll works in every outlet and is the only one allowed outlet 0, which means all outlets
+func (c *JwtCustomClaims) HasOutlet(id int64) bool {
+	if c.Can(domain.PermOutletAll) {
+		return true
+	}
+	for _, o := range c.Outlets {
+		if id != 0 && o == id {
+			return true
+		}
+	}
+	return false
+}
+
+// OutletForbidden answer a request on an outlet the token holder is not assigned to
+func OutletForbidden(c echo.Context) error {
+	return forbidden(c, constants.ErrOutletForbidden)
+}
@@ -0,0 +1,21 @@
+package middleware_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	middlwr "mini_pos/api/middleware"
+	"mini_pos/domain"
+)
+
+func TestHasOutlet(t *testing.T) {
+	cashier := &middlwr.JwtCustomClaims{Outlets: []int64{1, 2}}
+	assert.True(t, cashier.HasOutlet(2))
+	assert.False(t, cashier.HasOutlet(3))
+	assert.False(t, cashier.HasOutlet(0))
+
+	admin := &middlwr.JwtCustomClaims{Permissions: []string{domain.PermOutletAll}}
+	assert.True(t, admin.HasOutlet(3))
+	assert.True(t, admin.HasOutlet(0))
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+
+	echo "github.com/labstack/echo/v4"
+
+	"mini_pos/api/common"
+	"mini_pos/constants"
+)
+
+// Can return true if the role of the token holds the permission
+func (c *JwtCustomClaims) Can(permission string) bool {
+	for _, p := range c.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Permission allow the route only to a token holding one of the permissions, it must run after JWTMiddleware.
+// The permissions are read from the token, a role change ends the sessions of the user so it logs in again
+func Permission(permissions ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims := GetTokenFromContext(c)
+			if claims == nil {
+				return c.JSON(http.StatusUnauthorized, common.ControllerResponse{
+					Code:    http.StatusUnauthorized,
+					Message: constants.ErrTokenAlreadyExpired.Error(),
+					Data:    map[string]interface{}{},
+				})
+			}
+			for _, p := range permissions {
+				if claims.Can(p) {
+					return next(c)
+				}
+			}
+			return forbidden(c, constants.ErrForbidden)
+		}
+	}
+}
+
+// forbidden answer a denied request with a 403 status
+func forbidden(c echo.Context, err error) error {
+	return c.JSON(http.StatusForbidden, common.ControllerResponse{
+		Code:    http.StatusForbidden,
+		Message: err.Error(),
+		Data:    map[string]interface{}{},
+	})
+}
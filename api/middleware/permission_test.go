@@ -0,0 +1,38 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	middlwr "mini_pos/api/middleware"
+	"mini_pos/domain"
+)
+
+func serve(claims *middlwr.JwtCustomClaims, permissions ...string) *httptest.ResponseRecorder {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if claims != nil {
+		c.Set("user", &jwt.Token{Claims: claims})
+	}
+	h := middlwr.Permission(permissions...)(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	_ = h(c)
+	return rec
+}
+
+func TestPermission(t *testing.T) {
+	merchant := &middlwr.JwtCustomClaims{RoleID: 2, Permissions: []string{domain.PermProductRead, domain.PermSell}}
+
+	assert.Equal(t, http.StatusOK, serve(merchant, domain.PermProductRead).Code)
+	assert.Equal(t, http.StatusOK, serve(merchant, domain.PermShiftManage, domain.PermSell).Code)
+	assert.Equal(t, http.StatusForbidden, serve(merchant, domain.PermProductWrite).Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(nil, domain.PermProductRead).Code)
+}
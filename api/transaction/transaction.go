@@ -0,0 +1,74 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/domain"
+)
+
+// DBTX is implemented by both *sql.DB and *sql.Tx
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+var txCtxKey = &contextKey{"tx"}
+
+type contextKey struct {
+	name string
+}
+
+type sqlTransactor struct {
+	Conn *sql.DB
+}
+
+// NewSqlTransactor will create an object that represent the domain.Transactor interface
+func NewSqlTransactor(Conn *sql.DB) domain.Transactor {
+	return &sqlTransactor{Conn}
+}
+
+// WithinTransaction run fn inside a transaction, it is committed when fn return nil and rolled back otherwise
+func (t *sqlTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txCtxKey).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			rollback(tx)
+			panic(p)
+		}
+		if err != nil {
+			rollback(tx)
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	err = fn(context.WithValue(ctx, txCtxKey, tx))
+	return
+}
+
+func rollback(tx *sql.Tx) {
+	if errRollback := tx.Rollback(); errRollback != nil {
+		logrus.Error(errRollback)
+	}
+}
+
+// Conn return the transaction carried by ctx, or conn when the call is not part of a transaction
+func Conn(ctx context.Context, conn *sql.DB) DBTX {
+	if tx, ok := ctx.Value(txCtxKey).(*sql.Tx); ok {
+		return tx
+	}
+	return conn
+}
@@ -0,0 +1,49 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"mini_pos/api/transaction"
+)
+
+func TestWithinTransactionCommit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE orders").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx := transaction.NewSqlTransactor(db)
+	err = tx.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+		_, err := transaction.Conn(ctx, db).ExecContext(ctx, "UPDATE orders SET isCheckout=1")
+		return err
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithinTransactionRollback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	errShort := errors.New("short")
+	tx := transaction.NewSqlTransactor(db)
+	err = tx.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+		return errShort
+	})
+	assert.Equal(t, errShort, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
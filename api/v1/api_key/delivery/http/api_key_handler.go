@@ -0,0 +1,183 @@
+package http
+
+import (
+	"mini_pos/api/common"
+	middlwr "mini_pos/api/middleware"
+	"mini_pos/config"
+	"mini_pos/constants"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"mini_pos/domain"
+)
+
+// ApiKeyHandler  represent the httphandler for ApiKey
+type ApiKeyHandler struct {
+	AUsecase domain.ApiKeyUsecase
+}
+
+// NewApiKeyHandler will initialize the api-keys/ resources endpoint
+func NewApiKeyHandler(e *echo.Echo, us domain.ApiKeyUsecase, cfg config.Config) {
+	handler := &ApiKeyHandler{
+		AUsecase: us,
+	}
+	ApiKeyV1:= e.Group("")
+
+	ApiKeyV1.Use(middlwr.UserMiddleware(cfg), middlwr.Permission(domain.PermApiKeyManage))
+	ApiKeyV1.GET("/api-keys", handler.FetchApiKey)
+	ApiKeyV1.POST("/api-keys", handler.Create)
+	ApiKeyV1.GET("/api-keys/:id", handler.GetByID)
+	ApiKeyV1.POST("/api-keys/:id/revoke", handler.Revoke)
+}
+
+func isRequestValid(m *domain.ApiKey) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(m)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FetchApiKey will fetch the API keys, the keys themselves are never answered again
+func (a *ApiKeyHandler) FetchApiKey(c echo.Context) error {
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	cursor := c.QueryParam("cursor")
+	ctx := c.Request().Context()
+
+	listAr, nextCursor, err := a.AUsecase.Fetch(ctx, cursor, int64(num))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(common.NewSuccessResponse(listAr))
+}
+
+// GetByID will get the API key by given id
+func (a *ApiKeyHandler) GetByID(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	apiKey, err := a.AUsecase.GetByID(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(apiKey))
+}
+
+// Create will issue a new API key, the answer holds the key the integration must keep
+func (a *ApiKeyHandler) Create(c echo.Context) (err error) {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	var apiKey domain.ApiKey
+	err = c.Bind(&apiKey)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&apiKey); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	// a key reaches no outlet its creator cannot
+	for _, outlet := range apiKey.Outlets {
+		if !claims.HasOutlet(outlet) {
+			return middlwr.OutletForbidden(c)
+		}
+	}
+
+	ctx := c.Request().Context()
+	apiKey.CreatedBy = int64(claims.ID)
+	err = a.AUsecase.Create(ctx, &apiKey)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(apiKey))
+}
+
+// Revoke will stop accepting the API key
+func (a *ApiKeyHandler) Revoke(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	apiKey, err := a.AUsecase.Revoke(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(apiKey))
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound, constants.ErrDataNotFound:
+		return http.StatusNotFound
+	case domain.ErrConflict:
+		return http.StatusConflict
+	case domain.ErrBadParamInput:
+		return http.StatusBadRequest
+	case constants.ErrApiKeyScope:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
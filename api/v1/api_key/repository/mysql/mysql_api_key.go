@@ -0,0 +1,259 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/api/transaction"
+	"mini_pos/api/v1/api_key/repository"
+	"mini_pos/constants"
+	"mini_pos/domain"
+)
+
+type mysqlApiKeyRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlApiKeyRepository will create an object that represent the domain.ApiKeyRepository interface
+func NewMysqlApiKeyRepository(Conn *sql.DB) domain.ApiKeyRepository {
+	return &mysqlApiKeyRepository{Conn}
+}
+
+func (m *mysqlApiKeyRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.ApiKey, err error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.ApiKey, 0)
+	for rows.Next() {
+		t := domain.ApiKey{}
+		expiresAt := sql.NullTime{}
+		lastUsedAt := sql.NullTime{}
+		lastUsedIP := sql.NullString{}
+		err = rows.Scan(
+			&t.ID,
+			&t.Name,
+			&t.Prefix,
+			&t.Status,
+			&t.CreatedBy,
+			&expiresAt,
+			&lastUsedAt,
+			&lastUsedIP,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		if expiresAt.Valid {
+			t.ExpiresAt = &expiresAt.Time
+		}
+		if lastUsedAt.Valid {
+			t.LastUsedAt = &lastUsedAt.Time
+		}
+		t.LastUsedIP = lastUsedIP.String
+		result = append(result, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		err = m.fetchScopes(ctx, &result[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// fetchScopes load the permission names and the outlets granted to the key
+func (m *mysqlApiKeyRepository) fetchScopes(ctx context.Context, a *domain.ApiKey) (err error) {
+	query := `SELECT permissions.name FROM api_key_permissions
+				join permissions on permissions.id = api_key_permissions.permission_id
+				WHERE api_key_permissions.api_key_id = ? ORDER BY permissions.name`
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, a.ID)
+	if err != nil {
+		return
+	}
+	a.Permissions = make([]string, 0)
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			rows.Close()
+			return
+		}
+		a.Permissions = append(a.Permissions, name)
+	}
+	rows.Close()
+
+	query = `SELECT outlet_id FROM api_key_outlets WHERE api_key_id = ? ORDER BY outlet_id`
+	rows, err = transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, a.ID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	a.Outlets = make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		err = rows.Scan(&id)
+		if err != nil {
+			return
+		}
+		a.Outlets = append(a.Outlets, id)
+	}
+	return rows.Err()
+}
+
+func (m *mysqlApiKeyRepository) Fetch(ctx context.Context, cursor string, num int64) (res []domain.ApiKey, nextCursor string, err error) {
+	query := `SELECT id, name, key_prefix, status, created_by, expires_at, last_used_at, last_used_ip, update_at, create_at
+  						FROM api_keys WHERE create_at > ? ORDER BY create_at LIMIT ? `
+
+	decodedCursor, err := repository.DecodeCursor(cursor)
+	if err != nil && cursor != "" {
+		return nil, "", domain.ErrBadParamInput
+	}
+
+	res, err = m.fetch(ctx, query, decodedCursor, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(res) == int(num) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	}
+
+	return
+}
+
+func (m *mysqlApiKeyRepository) getOne(ctx context.Context, query string, args ...interface{}) (res domain.ApiKey, err error) {
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return domain.ApiKey{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+
+	return
+}
+
+func (m *mysqlApiKeyRepository) GetByID(ctx context.Context, id int64) (res domain.ApiKey, err error) {
+	query := `SELECT id, name, key_prefix, status, created_by, expires_at, last_used_at, last_used_ip, update_at, create_at
+  						FROM api_keys WHERE ID = ? `
+	return m.getOne(ctx, query, id)
+}
+
+// GetByHash return the key of a sha256, only the hash of a key is stored
+func (m *mysqlApiKeyRepository) GetByHash(ctx context.Context, keyHash string) (res domain.ApiKey, err error) {
+	query := `SELECT id, name, key_prefix, status, created_by, expires_at, last_used_at, last_used_ip, update_at, create_at
+  						FROM api_keys WHERE key_hash = ? `
+	return m.getOne(ctx, query, keyHash)
+}
+
+// Store insert the key with its permissions and outlets, call it within a transaction.
+// A permission name that is not in the permissions table answers constants.ErrApiKeyScope
+func (m *mysqlApiKeyRepository) Store(ctx context.Context, a *domain.ApiKey, keyHash string) (err error) {
+	query := `INSERT  api_keys
+			  SET name=?, key_prefix=?, key_hash=?, status=?, created_by=?, expires_at=?, update_at=? , create_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, a.Name, a.Prefix, keyHash, a.Status, a.CreatedBy, a.ExpiresAt, now, now)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	a.UpdatedAt = now
+	a.CreatedAt = now
+
+	query = `INSERT INTO api_key_permissions (api_key_id, permission_id) SELECT ?, id FROM permissions WHERE name = ?`
+	stmt, err = transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	for _, name := range a.Permissions {
+		res, err = stmt.ExecContext(ctx, a.ID, name)
+		if err != nil {
+			return
+		}
+		affect, errAffect := res.RowsAffected()
+		if errAffect != nil {
+			return errAffect
+		}
+		if affect != 1 {
+			return constants.ErrApiKeyScope
+		}
+	}
+
+	query = `INSERT INTO api_key_outlets (api_key_id, outlet_id) VALUES (?, ?)`
+	stmt, err = transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	for _, outletId := range a.Outlets {
+		_, err = stmt.ExecContext(ctx, a.ID, outletId)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (m *mysqlApiKeyRepository) SetStatus(ctx context.Context, id int64, status string) (err error) {
+	query := `UPDATE api_keys SET status=?, update_at=? WHERE ID = ?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, status, time.Now(), id)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+	}
+	return
+}
+
+// Touch record the last time and the IP the key was used from
+func (m *mysqlApiKeyRepository) Touch(ctx context.Context, id int64, ip string, at time.Time) (err error) {
+	query := `UPDATE api_keys SET last_used_at=?, last_used_ip=? WHERE ID = ?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	_, err = stmt.ExecContext(ctx, at, ip, id)
+	return
+}
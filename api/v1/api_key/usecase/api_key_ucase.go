@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/constants"
+	"mini_pos/domain"
+	"mini_pos/utils"
+)
+
+// touchInterval is how stale the last use of a key may get before it is written again,
+// so a busy integration does not write on every request
+const touchInterval = time.Minute
+
+// reservedScopes cannot be granted to a key, a key must not manage the identities of the POS
+var reservedScopes = map[string]bool{
+	domain.PermUserManage:     true,
+	domain.PermTerminalManage: true,
+	domain.PermApiKeyManage:   true,
+}
+
+type ApiKeyUsecase struct {
+	ApiKeyRepo     domain.ApiKeyRepository
+	OutletRepo     domain.OutletRepository
+	UserRepo       domain.UserRepository
+	Transactor     domain.Transactor
+	contextTimeout time.Duration
+}
+
+// NewApiKeyUsecase will create new an ApiKeyUsecase object representation of domain.ApiKeyUsecase interface
+func NewApiKeyUsecase(a domain.ApiKeyRepository, outlet domain.OutletRepository, user domain.UserRepository, tx domain.Transactor, timeout time.Duration) domain.ApiKeyUsecase {
+	return &ApiKeyUsecase{
+		ApiKeyRepo:     a,
+		OutletRepo:     outlet,
+		UserRepo:       user,
+		Transactor:     tx,
+		contextTimeout: timeout,
+	}
+}
+
+func (a *ApiKeyUsecase) Fetch(c context.Context, cursor string, num int64) (res []domain.ApiKey, nextCursor string, err error) {
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, nextCursor, err = a.ApiKeyRepo.Fetch(ctx, cursor, num)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(res) <= 0 {
+		return nil, "", constants.ErrDataNotFound
+	}
+	return
+}
+
+func (a *ApiKeyUsecase) GetByID(c context.Context, id int64) (res domain.ApiKey, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.ApiKeyRepo.GetByID(ctx, id)
+}
+
+// Create issue a key with its permissions and outlets, the key is answered once and only its hash is kept
+func (a *ApiKeyUsecase) Create(c context.Context, m *domain.ApiKey) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	for _, name := range m.Permissions {
+		if reservedScopes[name] {
+			return constants.ErrApiKeyScope
+		}
+	}
+	if m.ExpiresAt != nil && !m.ExpiresAt.After(time.Now()) {
+		return domain.ErrBadParamInput
+	}
+	for _, id := range m.Outlets {
+		_, err = a.OutletRepo.GetByID(ctx, id)
+		if err != nil {
+			return
+		}
+	}
+
+	key, err := utils.RandomToken(24)
+	if err != nil {
+		return
+	}
+	key = domain.ApiKeyPrefix + key
+	m.Prefix = key[:12]
+	m.Status = domain.ApiKeyActive
+	err = a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return a.ApiKeyRepo.Store(ctx, m, utils.HashToken(key))
+	})
+	if err != nil {
+		return
+	}
+	m.Key = key
+	return
+}
+
+// Revoke stop accepting the key at once
+func (a *ApiKeyUsecase) Revoke(c context.Context, id int64) (res domain.ApiKey, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err = a.ApiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	if res.Status == domain.ApiKeyRevoked {
+		return
+	}
+	err = a.ApiKeyRepo.SetStatus(ctx, id, domain.ApiKeyRevoked)
+	if err != nil {
+		return domain.ApiKey{}, err
+	}
+	res.Status = domain.ApiKeyRevoked
+	return
+}
+
+// Authenticate return the active key of a request and record its use, an unknown, revoked or expired key
+// answers constants.ErrApiKeyInvalid. The key acts as its creator, so it stops working once the creator
+// is suspended or deleted
+func (a *ApiKeyUsecase) Authenticate(c context.Context, key string, ip string) (res domain.ApiKey, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err = a.ApiKeyRepo.GetByHash(ctx, utils.HashToken(key))
+	if err == domain.ErrNotFound {
+		return domain.ApiKey{}, constants.ErrApiKeyInvalid
+	}
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	if res.Status != domain.ApiKeyActive || (res.ExpiresAt != nil && !now.Before(*res.ExpiresAt)) {
+		return domain.ApiKey{}, constants.ErrApiKeyInvalid
+	}
+	creator, err := a.UserRepo.GetByID(ctx, int(res.CreatedBy))
+	if err == domain.ErrNotFound {
+		return domain.ApiKey{}, constants.ErrApiKeyInvalid
+	}
+	if err != nil {
+		return domain.ApiKey{}, err
+	}
+	if creator.Status != domain.UserActive {
+		return domain.ApiKey{}, constants.ErrApiKeyInvalid
+	}
+
+	if res.LastUsedAt == nil || now.Sub(*res.LastUsedAt) >= touchInterval || res.LastUsedIP != ip {
+		// the tracking must not fail the request
+		errTouch := a.ApiKeyRepo.Touch(ctx, res.ID, ip, now)
+		if errTouch != nil {
+			logrus.Error(errTouch)
+		}
+		res.LastUsedAt = &now
+		res.LastUsedIP = ip
+	}
+	return
+}
@@ -0,0 +1,37 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"mini_pos/api/v1/api_key/usecase"
+	"mini_pos/constants"
+	"mini_pos/domain"
+	"mini_pos/domain/fakes"
+	"mini_pos/utils"
+)
+
+func TestAuthenticateCreator(t *testing.T) {
+	ctx := context.Background()
+	key := domain.ApiKeyPrefix + "secret"
+	keys := &fakes.ApiKeyRepository{Keys: map[string]domain.ApiKey{
+		utils.HashToken(key): {ID: 1, Status: domain.ApiKeyActive, CreatedBy: 7},
+	}}
+	users := &fakes.UserRepository{Users: map[int]domain.UserAccount{7: {ID: 7, Status: domain.UserActive}}}
+	u := usecase.NewApiKeyUsecase(keys, &fakes.OutletRepository{}, users, fakes.Transactor{}, time.Second)
+
+	res, err := u.Authenticate(ctx, key, "10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), res.ID)
+
+	// the key stops working with its creator
+	users.Users[7] = domain.UserAccount{ID: 7, Status: domain.UserSuspended}
+	_, err = u.Authenticate(ctx, key, "10.0.0.1")
+	assert.Equal(t, constants.ErrApiKeyInvalid, err)
+	delete(users.Users, 7)
+	_, err = u.Authenticate(ctx, key, "10.0.0.1")
+	assert.Equal(t, constants.ErrApiKeyInvalid, err)
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"mini_pos/api/common"
+	middlwr "mini_pos/api/middleware"
+	"mini_pos/config"
+	"mini_pos/constants"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/domain"
+)
+
+// AuditHandler  represent the httphandler for AuditLog
+type AuditHandler struct {
+	AUsecase domain.AuditUsecase
+}
+
+// NewAuditHandler will initialize the audit-logs/ resources endpoint
+func NewAuditHandler(e *echo.Echo, us domain.AuditUsecase, cfg config.Config) {
+	handler := &AuditHandler{
+		AUsecase: us,
+	}
+	AuditV1 := e.Group("")
+
+	AuditV1.Use(middlwr.UserMiddleware(cfg), middlwr.Permission(domain.PermUserManage))
+	AuditV1.GET("/audit-logs", handler.FetchAuditLog)
+}
+
+// FetchAuditLog will fetch the audit logs, filtered by the real user with actor and by the impersonated user with user
+func (a *AuditHandler) FetchAuditLog(c echo.Context) error {
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	actor, _ := strconv.Atoi(c.QueryParam("actor"))
+	user, _ := strconv.Atoi(c.QueryParam("user"))
+	cursor := c.QueryParam("cursor")
+	ctx := c.Request().Context()
+
+	listAr, nextCursor, err := a.AUsecase.Fetch(ctx, cursor, int64(num), actor, user)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(common.NewSuccessResponse(listAr))
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound, constants.ErrDataNotFound:
+		return http.StatusNotFound
+	case domain.ErrBadParamInput:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,124 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/api/transaction"
+	"mini_pos/api/v1/audit/repository"
+	"mini_pos/domain"
+)
+
+type mysqlAuditRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlAuditRepository will create an object that represent the domain.AuditRepository interface
+func NewMysqlAuditRepository(Conn *sql.DB) domain.AuditRepository {
+	return &mysqlAuditRepository{Conn}
+}
+
+func (m *mysqlAuditRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.AuditLog, err error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.AuditLog, 0)
+	for rows.Next() {
+		t := domain.AuditLog{}
+		method := sql.NullString{}
+		path := sql.NullString{}
+		status := sql.NullInt64{}
+		ip := sql.NullString{}
+		detail := sql.NullString{}
+		err = rows.Scan(
+			&t.ID,
+			&t.ActorID,
+			&t.UserID,
+			&t.Action,
+			&method,
+			&path,
+			&status,
+			&ip,
+			&detail,
+			&t.CreatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		t.Method = method.String
+		t.Path = path.String
+		t.Status = int(status.Int64)
+		t.IP = ip.String
+		t.Detail = detail.String
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+func (m *mysqlAuditRepository) Fetch(ctx context.Context, cursor string, num int64, actorId int, userId int) (res []domain.AuditLog, nextCursor string, err error) {
+	query := `SELECT id, actor_id, user_id, action, method, path, status, ip, detail, create_at
+  						FROM audit_logs WHERE create_at > ? and (? = 0 or actor_id = ?) and (? = 0 or user_id = ?)
+  						ORDER BY create_at LIMIT ? `
+
+	decodedCursor, err := repository.DecodeCursor(cursor)
+	if err != nil && cursor != "" {
+		return nil, "", domain.ErrBadParamInput
+	}
+
+	res, err = m.fetch(ctx, query, decodedCursor, actorId, actorId, userId, userId, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(res) == int(num) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	}
+
+	return
+}
+
+// Store append a log, the logs are never deleted and only the status of a write is filled in later, see SetStatus
+func (m *mysqlAuditRepository) Store(ctx context.Context, a *domain.AuditLog) (err error) {
+	query := `INSERT  audit_logs
+			  SET actor_id=?, user_id=?, action=?, method=?, path=?, status=?, ip=?, detail=?, create_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, a.ActorID, a.UserID, a.Action, a.Method, a.Path, a.Status, a.IP, a.Detail, now)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	a.CreatedAt = now
+	return
+}
+
+// SetStatus fill in the status of a write logged before it was made, a status already set is kept
+func (m *mysqlAuditRepository) SetStatus(ctx context.Context, id int64, status int) (err error) {
+	query := `UPDATE audit_logs SET status=? WHERE id = ? and status = 0`
+	_, err = transaction.Conn(ctx, m.Conn).ExecContext(ctx, query, status, id)
+	return
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/constants"
+	"mini_pos/domain"
+)
+
+type AuditUsecase struct {
+	AuditRepo      domain.AuditRepository
+	contextTimeout time.Duration
+}
+
+// NewAuditUsecase will create new an AuditUsecase object representation of domain.AuditUsecase interface
+func NewAuditUsecase(a domain.AuditRepository, timeout time.Duration) domain.AuditUsecase {
+	return &AuditUsecase{
+		AuditRepo:      a,
+		contextTimeout: timeout,
+	}
+}
+
+func (a *AuditUsecase) Fetch(c context.Context, cursor string, num int64, actorId int, userId int) (res []domain.AuditLog, nextCursor string, err error) {
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, nextCursor, err = a.AuditRepo.Fetch(ctx, cursor, num, actorId, userId)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(res) <= 0 {
+		return nil, "", constants.ErrDataNotFound
+	}
+	return
+}
+
+// Record keep the log in the audit_logs table, it is written to the application log as well
+// so the trail survives a failing database
+func (a *AuditUsecase) Record(c context.Context, m *domain.AuditLog) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	logrus.WithFields(logrus.Fields{
+		"actor_id": m.ActorID,
+		"user_id":  m.UserID,
+		"method":   m.Method,
+		"path":     m.Path,
+		"status":   m.Status,
+		"ip":       m.IP,
+	}).Warn(m.Action)
+	return a.AuditRepo.Store(ctx, m)
+}
+
+// SetStatus fill in the status of the answer to a write logged before it was made
+func (a *AuditUsecase) SetStatus(c context.Context, id int64, status int) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.AuditRepo.SetStatus(ctx, id, status)
+}
@@ -1,8 +1,8 @@
 package http
 
 import (
+	"context"
 	"fmt"
-	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
 	"mini_pos/api/common"
@@ -12,7 +12,10 @@ import (
 	"mini_pos/domain"
 	"mini_pos/utils"
 	"net/http"
+	"strconv"
 	"time"
+
+	validator "gopkg.in/go-playground/validator.v9"
 )
 
 type AuthHandler struct {
@@ -26,8 +29,96 @@ func NewAuthHandler(e *echo.Echo, us domain.AuthUseCase, cfg config.Config)  {
 		Cfg: cfg,
 	}
 
+	e.GET("/.well-known/jwks.json", handler.JWKS)
 	e.POST("/auth/login", handler.Login)
 	e.POST("/auth/register", handler.Register)
+	e.POST("/auth/refresh", handler.Refresh)
+	e.POST("/auth/pin", handler.PinLogin)
+	e.POST("/auth/2fa", handler.VerifyTwoFactor)
+	e.POST("/auth/2fa/setup", handler.SetupTwoFactorChallenge)
+	e.POST("/auth/password/forgot", handler.ForgotPassword)
+	e.POST("/auth/password/verify-otp", handler.VerifyOTP)
+	e.POST("/auth/password/reset", handler.ResetPassword)
+
+	authV1 := e.Group("")
+	authV1.Use(middleware.UserMiddleware(cfg))
+	authV1.POST("/auth/logout", handler.Logout)
+	authV1.GET("/me", handler.GetProfile)
+	authV1.PUT("/me", handler.UpdateProfile)
+	authV1.PUT("/me/password", handler.ChangePassword, middleware.NotImpersonating)
+	authV1.PUT("/me/pin", handler.SetPin, middleware.NotImpersonating)
+	authV1.POST("/me/2fa/setup", handler.SetupTwoFactor, middleware.NotImpersonating)
+	authV1.POST("/me/2fa/confirm", handler.ConfirmTwoFactor, middleware.NotImpersonating)
+	authV1.POST("/me/2fa/disable", handler.DisableTwoFactor, middleware.NotImpersonating)
+	authV1.POST("/me/2fa/recovery-codes", handler.RegenerateRecoveryCodes, middleware.NotImpersonating)
+	authV1.POST("/auth/revoke/:id", handler.Revoke, middleware.Permission(domain.PermUserManage))
+	authV1.POST("/auth/unlock", handler.Unlock, middleware.Permission(domain.PermUserManage))
+	authV1.POST("/auth/impersonate", handler.Impersonate, middleware.NotImpersonating, middleware.Permission(domain.PermUserImpersonate))
+}
+
+func isRequestValid(m interface{}) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(m)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// refreshTokenLifeTime return the life time of a refresh token, one week when it is not configured
+func (a *AuthHandler) refreshTokenLifeTime() time.Duration {
+	if a.Cfg.JWTConfig.RefreshTokenLifeTimeHour == 0 {
+		return time.Hour * 168
+	}
+	return time.Hour * time.Duration(a.Cfg.JWTConfig.RefreshTokenLifeTimeHour)
+}
+
+// signToken give the claims an id and sign them into an access token with the key set
+func (a *AuthHandler) signToken(claims *middleware.JwtCustomClaims) (res string, err error) {
+	claims.Id, err = utils.RandomToken(16)
+	if err != nil {
+		return
+	}
+	return middleware.Keys(a.Cfg).Sign(claims)
+}
+
+// newSession sign an access token carrying the permissions and outlets of the user, with the refresh token to renew it
+func (a *AuthHandler) newSession(ctx context.Context, user domain.User) (res domain.UserResponse, err error) {
+	permissions, err := a.AuUseCase.GetPermissions(ctx, user.RoleID)
+	if err != nil {
+		return
+	}
+
+	outlets, err := a.AuUseCase.GetOutlets(ctx, user.ID)
+	if err != nil {
+		return
+	}
+
+	claims := &middleware.JwtCustomClaims{
+		Name:     user.Name,
+		ID:       user.ID,
+		RoleID: user.RoleID,
+		UserName: user.UserName,
+		Permissions: permissions,
+		Outlets: outlets,
+	}
+	generate, err := a.signToken(claims)
+	if err != nil {
+		return
+	}
+
+	refreshToken, err := a.AuUseCase.CreateRefreshToken(ctx, user.ID, a.refreshTokenLifeTime())
+	if err != nil {
+		return
+	}
+
+	res = domain.UserResponse{
+		ID:           user.ID,
+		Token:        generate,
+		RefreshToken: refreshToken,
+		ExpiresAt:    claims.ExpiresAt,
+	}
+	return
 }
 func (a *AuthHandler) Login(c echo.Context) (err error) {
 	var auth domain.UserLogin
@@ -46,19 +137,132 @@ func (a *AuthHandler) Login(c echo.Context) (err error) {
 		}))
 	}
 
+	ctx := c.Request().Context()
+
+	user, err := a.AuUseCase.Login(ctx, auth, c.RealIP())
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	// with 2FA the session is only opened by POST /auth/2fa with the TOTP code
+	challenge, err := a.AuUseCase.StartTwoFactor(ctx, user)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	if challenge.TwoFactorRequired {
+		return c.JSON(common.NewSuccessResponse(challenge))
+	}
+
+	res, err := a.newSession(ctx, user)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(res))
+}
+
+// Register sign up an account waiting for the approval of a super admin, it is refused when the registration is closed
+func (a *AuthHandler) Register(c echo.Context) (err error) {
+	if !a.Cfg.Server.Registration {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusForbidden,
+			Message: constants.ErrRegistrationClosed.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var reg domain.RegisterUser
+	err = c.Bind(&reg)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	//validasi email
+	status := utils.ValidateEmail(reg.Email)
+	if !status{ fmt.Println(status)
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "Email not Valid!",
+			Data:    nil,
+		}))
+	}
+
 	// validasi password
-	status=utils.ValidatePassword(auth.Password)
+	status=utils.ValidatePassword(reg.Password)
 	if !status{ fmt.Println(status)
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    getStatusCode(err),
+			Code:    http.StatusUnprocessableEntity,
 			Message: constants.ErrInvalidPassword.Error(),
 			Data:    nil,
 		}))
 	}
+	ctx := c.Request().Context()
+
+	user, err := a.AuUseCase.Register(ctx, reg)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	return c.JSON(common.NewSuccessResponse(domain.Profile{
+		ID:       user.ID,
+		Name:     user.Name,
+		UserName: user.UserName,
+		Email:    user.Email,
+		Status:   user.Status,
+	}))
+}
+
+// Refresh rotate the refresh token and issue a new access token, the permissions and outlets are reloaded
+func (a *AuthHandler) Refresh(c echo.Context) (err error) {
+	var req domain.RequestRefreshToken
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&req); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
 
 	ctx := c.Request().Context()
+	user, err := a.AuUseCase.Refresh(ctx, req.RefreshToken)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
 
-	user, err := a.AuUseCase.GetEmailUser(ctx, auth.Email)
+	res, err := a.newSession(ctx, user)
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
 			Code:    getStatusCode(err),
@@ -67,53 +271,105 @@ func (a *AuthHandler) Login(c echo.Context) (err error) {
 		}))
 	}
 
-	//fmt.Println(auth.Password, user.Password)
-	if !utils.CheckPasswordHash(auth.Password, user.Password){
+	return c.JSON(common.NewSuccessResponse(res))
+}
+
+// Logout revoke the access token of the request and drop the refresh token sent in the body
+func (a *AuthHandler) Logout(c echo.Context) (err error) {
+	claims := middleware.GetTokenFromContext(c)
+	if claims == nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
 			Code:    http.StatusUnauthorized,
-			Message: "error: invalid Password user!",
+			Message: constants.ErrTokenAlreadyExpired.Error(),
 			Data:    nil,
 		}))
 	}
 
-	if auth.Username != user.UserName{
+	var req domain.RequestRefreshToken
+	err = c.Bind(&req)
+	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: "error: Username Not Match!",
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
 			Data:    nil,
 		}))
 	}
 
-	claims := &middleware.JwtCustomClaims{
-		Name:     user.Name,
-		ID:       user.ID,
-		RoleID: user.RoleID,
-		UserName: user.UserName,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 1).Unix() ,
-		},
+	ctx := c.Request().Context()
+	err = a.AuUseCase.Logout(ctx, claims.ID, claims.Id, claims.ExpiresAt, req.RefreshToken)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	generate, err := token.SignedString([]byte(a.Cfg.JWTConfig.Secret))
+
+	return c.JSON(common.NewSuccessResponseWithoutData())
+}
+
+// Revoke end every session of a user at once, e.g. when a cashier leaves, super admin only
+func (a *AuthHandler) Revoke(c echo.Context) (err error) {
+	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
+			Code:    http.StatusNotFound,
+			Message: domain.ErrNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	err = a.AuUseCase.RevokeUser(ctx, idP)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
 			Message: err.Error(),
 			Data:    nil,
 		}))
 	}
 
-	res :=  domain.UserResponse{
-			ID:    user.ID,
-			Token: generate,
-		}
+	return c.JSON(common.NewSuccessResponseWithoutData())
+}
 
-	return c.JSON(common.NewSuccessResponse(res))
+// ForgotPassword mail an OTP to reset the password, the answer is the same whether the email is registered or not
+func (a *AuthHandler) ForgotPassword(c echo.Context) (err error) {
+	var req domain.RequestForgotPassword
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&req); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	err = a.AuUseCase.ForgotPassword(ctx, req.Email, c.RealIP())
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponseWithoutData())
 }
 
-func (a *AuthHandler) Register(c echo.Context) (err error) {
-	var reg domain.RegisterUser
-	err = c.Bind(&reg)
+// VerifyOTP check the mailed OTP and answer with the reset token to set the new password with
+func (a *AuthHandler) VerifyOTP(c echo.Context) (err error) {
+	var req domain.RequestVerifyOTP
+	err = c.Bind(&req)
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
 			Code:    http.StatusUnprocessableEntity,
@@ -122,28 +378,97 @@ func (a *AuthHandler) Register(c echo.Context) (err error) {
 		}))
 	}
 
-	//validasi email
-	status := utils.ValidateEmail(reg.Email)
-	if !status{ fmt.Println(status)
+	if req.OTP == "" {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: constants.ErrOTPIsRequired.Error(),
+			Data:    nil,
+		}))
+	}
+	var ok bool
+	if ok, err = isRequestValid(&req); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	res, err := a.AuUseCase.VerifyOTP(ctx, req.Email, req.OTP)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(res))
+}
+
+// ResetPassword set the new password with the reset token, every session of the user is ended
+func (a *AuthHandler) ResetPassword(c echo.Context) (err error) {
+	var req domain.RequestResetPassword
+	err = c.Bind(&req)
+	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
 			Code:    http.StatusUnprocessableEntity,
-			Message: "Email not Valid!",
+			Message: err.Error(),
 			Data:    nil,
 		}))
 	}
 
-	// validasi password
-	status=utils.ValidatePassword(reg.Password)
-	if !status{ fmt.Println(status)
+	var ok bool
+	if ok, err = isRequestValid(&req); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	if !utils.ValidatePassword(req.Password) {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
 			Code:    http.StatusUnprocessableEntity,
 			Message: constants.ErrInvalidPassword.Error(),
 			Data:    nil,
 		}))
 	}
+	if req.Password != req.RePassword {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: constants.ErrPasswordNotMatch.Error(),
+			Data:    nil,
+		}))
+	}
+
 	ctx := c.Request().Context()
+	err = a.AuUseCase.ResetPassword(ctx, req.ResetToken, req.Password)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponseWithoutData())
+}
+
+// GetProfile return the account of the token holder
+func (a *AuthHandler) GetProfile(c echo.Context) (err error) {
+	claims := middleware.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
 
-	_, err = a.AuUseCase.Register(ctx, reg)
+	ctx := c.Request().Context()
+	res, err := a.AuUseCase.GetProfile(ctx, claims.ID)
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
 			Code:    getStatusCode(err),
@@ -151,7 +476,623 @@ func (a *AuthHandler) Register(c echo.Context) (err error) {
 			Data:    nil,
 		}))
 	}
-	return c.JSON(common.NewSuccessResponse(reg))
+
+	return c.JSON(common.NewSuccessResponse(res))
+}
+
+// UpdateProfile change the name of the token holder, it shows in the tokens issued from now on
+func (a *AuthHandler) UpdateProfile(c echo.Context) (err error) {
+	claims := middleware.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var req domain.RequestUpdateProfile
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	if req.Name == "" {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: constants.ErrNameIsRequired.Error(),
+			Data:    nil,
+		}))
+	}
+	var ok bool
+	if ok, err = isRequestValid(&req); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	res, err := a.AuUseCase.UpdateProfile(ctx, claims.ID, &req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(res))
+}
+
+// ChangePassword set the new password of the token holder, the other sessions are ended
+// and the caller receives a new session in the answer
+func (a *AuthHandler) ChangePassword(c echo.Context) (err error) {
+	claims := middleware.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var req domain.RequestChangePassword
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	if req.OldPassword == "" {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: constants.ErrOldPasswordIsRequired.Error(),
+			Data:    nil,
+		}))
+	}
+	if req.Password == "" {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: constants.ErrNewPasswordIsRequired.Error(),
+			Data:    nil,
+		}))
+	}
+	if !utils.ValidatePassword(req.Password) {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: constants.ErrInvalidPassword.Error(),
+			Data:    nil,
+		}))
+	}
+	if req.Password != req.RePassword {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: constants.ErrPasswordNotMatch.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	user, err := a.AuUseCase.ChangePassword(ctx, claims.ID, &req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	res, err := a.newSession(ctx, user)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(res))
+}
+
+// Unlock clear the failed logins of an account or of an IP before their lockout ends, super admin only
+func (a *AuthHandler) Unlock(c echo.Context) (err error) {
+	var req domain.RequestUnlock
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	if req.Email == "" && req.IP == "" {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: constants.ErrEmailIsRequired.Error(),
+			Data:    nil,
+		}))
+	}
+	var ok bool
+	if ok, err = isRequestValid(&req); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	err = a.AuUseCase.Unlock(ctx, req.Email, req.IP)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponseWithoutData())
+}
+
+// PinLogin switch the active cashier of a trusted terminal, the token is scoped to the outlet of the terminal
+// and comes without a refresh token, the cashier switches in again with the PIN once it expires
+func (a *AuthHandler) PinLogin(c echo.Context) (err error) {
+	var req domain.RequestPinLogin
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&req); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	user, terminal, err := a.AuUseCase.PinLogin(ctx, req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	permissions, err := a.AuUseCase.GetPermissions(ctx, user.RoleID)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	scoped := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		if p != domain.PermOutletAll {
+			scoped = append(scoped, p)
+		}
+	}
+
+	claims := &middleware.JwtCustomClaims{
+		Name:        user.Name,
+		ID:          user.ID,
+		RoleID:      user.RoleID,
+		UserName:    user.UserName,
+		Permissions: scoped,
+		Outlets:     []int64{terminal.OutletID},
+		TerminalID:  terminal.ID,
+	}
+	generate, err := a.signToken(claims)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(domain.UserResponse{
+		ID:        user.ID,
+		Token:     generate,
+		ExpiresAt: claims.ExpiresAt,
+	}))
+}
+
+// SetPin set the PIN the token holder switches on the terminals with
+func (a *AuthHandler) SetPin(c echo.Context) (err error) {
+	claims := middleware.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var req domain.RequestSetPin
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&req); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	if !utils.ValidatePin(req.Pin) {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: constants.ErrPinFormat.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	err = a.AuUseCase.SetPin(ctx, claims.ID, &req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponseWithoutData())
+}
+
+// VerifyTwoFactor finish a login waiting for the TOTP code, or for the first code of the enrollment of a user
+// who must use 2FA, in which case the recovery codes come with the session
+func (a *AuthHandler) VerifyTwoFactor(c echo.Context) (err error) {
+	var req domain.RequestTwoFactorLogin
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&req); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	if req.Code == "" && req.RecoveryCode == "" {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: constants.ErrTwoFactorCodeRequired.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	user, codes, err := a.AuUseCase.VerifyTwoFactor(ctx, req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	res, err := a.newSession(ctx, user)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	res.RecoveryCodes = codes
+
+	return c.JSON(common.NewSuccessResponse(res))
+}
+
+// SetupTwoFactorChallenge answer the secret to enroll with for a user who cannot login before enrolling
+func (a *AuthHandler) SetupTwoFactorChallenge(c echo.Context) (err error) {
+	var req domain.RequestTwoFactorSetup
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&req); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	res, err := a.AuUseCase.SetupTwoFactorChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(res))
+}
+
+// SetupTwoFactor answer the secret and the provisioning URI the token holder enrolls its authenticator app with
+func (a *AuthHandler) SetupTwoFactor(c echo.Context) (err error) {
+	claims := middleware.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	res, err := a.AuUseCase.SetupTwoFactor(ctx, claims.ID)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(res))
+}
+
+// ConfirmTwoFactor turn 2FA on with the first code of the authenticator app, the recovery codes are answered once
+func (a *AuthHandler) ConfirmTwoFactor(c echo.Context) (err error) {
+	claims := middleware.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var req domain.RequestTwoFactorCode
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&req); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	codes, err := a.AuUseCase.ConfirmTwoFactor(ctx, claims.ID, req.Code)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(domain.RecoveryCodes{Codes: codes}))
+}
+
+// DisableTwoFactor turn 2FA off for the token holder
+func (a *AuthHandler) DisableTwoFactor(c echo.Context) (err error) {
+	claims := middleware.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var req domain.RequestDisableTwoFactor
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&req); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	err = a.AuUseCase.DisableTwoFactor(ctx, claims.ID, &req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponseWithoutData())
+}
+
+// RegenerateRecoveryCodes replace the recovery codes of the token holder, the new ones are answered once
+func (a *AuthHandler) RegenerateRecoveryCodes(c echo.Context) (err error) {
+	claims := middleware.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var req domain.RequestTwoFactorCode
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&req); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	codes, err := a.AuUseCase.RegenerateRecoveryCodes(ctx, claims.ID, req.Code)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(domain.RecoveryCodes{Codes: codes}))
+}
+
+// JWKS publish the public keys the access tokens are verified with, it answers the bare RFC 7517 document
+func (a *AuthHandler) JWKS(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "public, max-age=3600")
+	return c.JSON(http.StatusOK, middleware.Keys(a.Cfg).JWKS())
+}
+
+// Impersonate issue a token acting as another user for the support staff, with the permissions and outlets
+// of that user. The token carries the support staff as actor, it has no refresh token and every write made
+// with it is audited
+func (a *AuthHandler) Impersonate(c echo.Context) (err error) {
+	claims := middleware.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var req domain.RequestImpersonate
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&req); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	user, err := a.AuUseCase.Impersonate(ctx, claims.ID, req, c.RealIP())
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	permissions, err := a.AuUseCase.GetPermissions(ctx, user.RoleID)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	outlets, err := a.AuUseCase.GetOutlets(ctx, user.ID)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	impersonation := &middleware.JwtCustomClaims{
+		Name:        user.Name,
+		ID:          user.ID,
+		RoleID:      user.RoleID,
+		UserName:    user.UserName,
+		Permissions: permissions,
+		Outlets:     outlets,
+		ActorID:     claims.ID,
+	}
+	generate, err := a.signToken(impersonation)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(domain.UserResponse{
+		ID:        user.ID,
+		Token:     generate,
+		ExpiresAt: impersonation.ExpiresAt,
+	}))
 }
 
 func getStatusCode(err error) int {
@@ -167,6 +1108,23 @@ func getStatusCode(err error) int {
 		return http.StatusNotFound
 	case domain.ErrConflict:
 		return http.StatusConflict
+	case constants.ErrRefreshTokenInvalid, constants.ErrResetTokenInvalid, constants.ErrGetOTPFromRedis,
+		constants.ErrOTPNotMatch, constants.ErrOTPTooManyAttempts:
+		return http.StatusUnauthorized
+	case constants.ErrEmailAndPasswordNotMatch, constants.ErrPinNotMatch, constants.ErrTerminalInvalid:
+		return http.StatusUnauthorized
+	case constants.ErrTwoFactorCodeNotMatch, constants.ErrTwoFactorChallenge:
+		return http.StatusUnauthorized
+	case constants.ErrLoginLocked, constants.ErrOTPResendLimit:
+		return http.StatusTooManyRequests
+	case constants.ErrUserInactive, constants.ErrUserPending, constants.ErrOutletForbidden, constants.ErrTwoFactorRequired:
+		return http.StatusForbidden
+	case constants.ErrImpersonationDisabled, constants.ErrImpersonationForbidden:
+		return http.StatusForbidden
+	case constants.ErrOldPasswordNotMatch, constants.ErrPasswordAlreadyTaken, constants.ErrPasswordNotMatch,
+		constants.ErrTwoFactorEnabled, constants.ErrTwoFactorNotEnabled, constants.ErrTwoFactorSetupMissing,
+		constants.ErrImpersonationPassword:
+		return http.StatusUnprocessableEntity
 	default:
 		return http.StatusInternalServerError
 	}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/constants"
+	"mini_pos/domain"
+	"mini_pos/utils"
+)
+
+type cacheLoginAttemptRepository struct {
+	Cache domain.Cache
+}
+
+// NewCacheLoginAttemptRepository will create an object that represent the domain.LoginAttemptRepository interface
+func NewCacheLoginAttemptRepository(c domain.Cache) domain.LoginAttemptRepository {
+	return &cacheLoginAttemptRepository{c}
+}
+
+// the failures are a counter increased in place, the lockout is kept apart so a lock never resets the count
+func failureKey(key string) string {
+	return "login_attempt:" + key + ":failures"
+}
+
+func lockKey(key string) string {
+	return "login_attempt:" + key + ":locked_until"
+}
+
+func (m *cacheLoginAttemptRepository) GetAttempt(ctx context.Context, key string) (res domain.LoginAttempt, err error) {
+	value, err := m.Cache.Get(ctx, failureKey(key))
+	if err != nil && err != domain.ErrNotFound {
+		logrus.Error(err)
+		return domain.LoginAttempt{}, constants.ErrGetTokenToRedis
+	}
+	res.Failures, _ = strconv.Atoi(value)
+
+	value, err = m.Cache.Get(ctx, lockKey(key))
+	if err == domain.ErrNotFound {
+		return res, nil
+	}
+	if err != nil {
+		logrus.Error(err)
+		return domain.LoginAttempt{}, constants.ErrGetTokenToRedis
+	}
+	until, _ := strconv.ParseInt(value, 10, 64)
+	res.LockedUntil = time.Unix(0, until*int64(time.Millisecond))
+	return res, nil
+}
+
+// AddFailure count one more failure on the key, the counter is dropped ttl after the last failure
+func (m *cacheLoginAttemptRepository) AddFailure(ctx context.Context, key string, ttl time.Duration) (failures int, err error) {
+	n, err := m.Cache.Incr(ctx, failureKey(key), ttl)
+	if err != nil {
+		logrus.Error(err)
+		return 0, constants.ErrSaveTokenToRedis
+	}
+	return int(n), nil
+}
+
+// Lock refuse the logins on the key until the given time
+func (m *cacheLoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) (err error) {
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return
+	}
+	err = m.Cache.Set(ctx, lockKey(key), strconv.FormatInt(utils.UnixMilli(until), 10), ttl)
+	if err != nil {
+		logrus.Error(err)
+		return constants.ErrSaveTokenToRedis
+	}
+	return
+}
+
+func (m *cacheLoginAttemptRepository) DeleteAttempt(ctx context.Context, key string) (err error) {
+	err = m.Cache.Delete(ctx, failureKey(key))
+	if err == nil {
+		err = m.Cache.Delete(ctx, lockKey(key))
+	}
+	if err != nil {
+		logrus.Error(err)
+		return constants.ErrSaveTokenToRedis
+	}
+	return
+}
@@ -0,0 +1,43 @@
+package cache_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	_cache "mini_pos/api/cache"
+	"mini_pos/api/v1/auth/repository/cache"
+)
+
+func TestLoginAttempt(t *testing.T) {
+	ctx := context.Background()
+	repo := cache.NewCacheLoginAttemptRepository(_cache.NewMemoryCache())
+
+	// parallel failures are all counted
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.AddFailure(ctx, "ip:10.0.0.1", time.Hour)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	until := time.Now().Add(time.Minute)
+	assert.NoError(t, repo.Lock(ctx, "ip:10.0.0.1", until))
+	attempt, err := repo.GetAttempt(ctx, "ip:10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, 20, attempt.Failures)
+	assert.WithinDuration(t, until, attempt.LockedUntil, time.Millisecond)
+
+	assert.NoError(t, repo.DeleteAttempt(ctx, "ip:10.0.0.1"))
+	attempt, err = repo.GetAttempt(ctx, "ip:10.0.0.1")
+	assert.NoError(t, err)
+	assert.Zero(t, attempt.Failures)
+	assert.True(t, attempt.LockedUntil.IsZero())
+}
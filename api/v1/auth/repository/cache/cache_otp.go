@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/constants"
+	"mini_pos/domain"
+	"mini_pos/utils"
+)
+
+type cacheOTPRepository struct {
+	Cache domain.Cache
+}
+
+// NewCacheOTPRepository will create an object that represent the domain.OTPRepository interface
+func NewCacheOTPRepository(c domain.Cache) domain.OTPRepository {
+	return &cacheOTPRepository{c}
+}
+
+func otpKey(purpose string, email string) string {
+	return "otp:" + purpose + ":" + email
+}
+
+func otpAttemptKey(purpose string, email string) string {
+	return "otp:" + purpose + ":" + email + ":attempts"
+}
+
+func resetTokenKey(token string) string {
+	return "otp:reset_token:" + utils.HashToken(token)
+}
+
+// StoreOTP keep the OTP until it expires, the attempts of the previous OTP of the email are dropped
+func (m *cacheOTPRepository) StoreOTP(ctx context.Context, purpose string, email string, otp domain.OTP) (err error) {
+	ttl := time.Until(otp.ExpiresAt)
+	if ttl <= 0 {
+		return m.DeleteOTP(ctx, purpose, email)
+	}
+	err = m.Cache.Delete(ctx, otpAttemptKey(purpose, email))
+	if err != nil {
+		logrus.Error(err)
+		return constants.ErrSaveOTPToRedis
+	}
+	value, err := json.Marshal(otp)
+	if err != nil {
+		return
+	}
+	err = m.Cache.Set(ctx, otpKey(purpose, email), string(value), ttl)
+	if err != nil {
+		logrus.Error(err)
+		return constants.ErrSaveOTPToRedis
+	}
+	return
+}
+
+func (m *cacheOTPRepository) GetOTP(ctx context.Context, purpose string, email string) (res domain.OTP, err error) {
+	value, err := m.Cache.Get(ctx, otpKey(purpose, email))
+	if err == domain.ErrNotFound {
+		return domain.OTP{}, err
+	}
+	if err != nil {
+		logrus.Error(err)
+		return domain.OTP{}, constants.ErrGetOTPFromRedis
+	}
+	err = json.Unmarshal([]byte(value), &res)
+	if err != nil {
+		return domain.OTP{}, domain.ErrNotFound
+	}
+	return
+}
+
+func (m *cacheOTPRepository) DeleteOTP(ctx context.Context, purpose string, email string) (err error) {
+	err = m.Cache.Delete(ctx, otpKey(purpose, email))
+	if err == nil {
+		err = m.Cache.Delete(ctx, otpAttemptKey(purpose, email))
+	}
+	if err != nil {
+		logrus.Error(err)
+		return constants.ErrDeleteOTPFromRedis
+	}
+	return
+}
+
+// AddOTPAttempt count one more attempt on the OTP of the email and return the attempts so far
+func (m *cacheOTPRepository) AddOTPAttempt(ctx context.Context, purpose string, email string, ttl time.Duration) (attempts int, err error) {
+	n, err := m.Cache.Incr(ctx, otpAttemptKey(purpose, email), ttl)
+	if err != nil {
+		logrus.Error(err)
+		return 0, constants.ErrSaveOTPToRedis
+	}
+	return int(n), nil
+}
+
+func (m *cacheOTPRepository) StoreResetToken(ctx context.Context, token string, userId int, ttl time.Duration) (err error) {
+	err = m.Cache.Set(ctx, resetTokenKey(token), strconv.Itoa(userId), ttl)
+	if err != nil {
+		logrus.Error(err)
+		return constants.ErrSaveTokenToRedis
+	}
+	return
+}
+
+func (m *cacheOTPRepository) GetResetToken(ctx context.Context, token string) (userId int, err error) {
+	value, err := m.Cache.Get(ctx, resetTokenKey(token))
+	if err == domain.ErrNotFound {
+		return 0, err
+	}
+	if err != nil {
+		logrus.Error(err)
+		return 0, constants.ErrGetTokenToRedis
+	}
+	userId, err = strconv.Atoi(value)
+	if err != nil {
+		return 0, domain.ErrNotFound
+	}
+	return
+}
+
+func (m *cacheOTPRepository) DeleteResetToken(ctx context.Context, token string) (err error) {
+	err = m.Cache.Delete(ctx, resetTokenKey(token))
+	if err != nil {
+		logrus.Error(err)
+		return constants.ErrSaveTokenToRedis
+	}
+	return
+}
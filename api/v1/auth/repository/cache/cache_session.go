@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/constants"
+	"mini_pos/domain"
+	"mini_pos/utils"
+)
+
+type cacheSessionRepository struct {
+	Cache domain.Cache
+}
+
+// NewCacheSessionRepository will create an object that represent the domain.SessionRepository interface
+func NewCacheSessionRepository(c domain.Cache) domain.SessionRepository {
+	return &cacheSessionRepository{c}
+}
+
+// only the hash of a refresh token is kept, a leaked store does not leak usable tokens
+func refreshKey(token string) string {
+	return "session:refresh:" + utils.HashToken(token)
+}
+
+func revokedTokenKey(tokenId string) string {
+	return "session:revoked:" + tokenId
+}
+
+func revokedUserKey(userId int) string {
+	return fmt.Sprintf("session:revoked_user:%d", userId)
+}
+
+func revokedTerminalKey(terminalId int64) string {
+	return fmt.Sprintf("session:revoked_terminal:%d", terminalId)
+}
+
+func (m *cacheSessionRepository) StoreRefreshToken(ctx context.Context, token string, userId int, ttl time.Duration) (err error) {
+	value := fmt.Sprintf("%d:%d", userId, utils.UnixMilli(time.Now()))
+	err = m.Cache.Set(ctx, refreshKey(token), value, ttl)
+	if err != nil {
+		logrus.Error(err)
+		return constants.ErrSaveTokenToRedis
+	}
+	return
+}
+
+func (m *cacheSessionRepository) GetRefreshToken(ctx context.Context, token string) (userId int, issuedAt int64, err error) {
+	value, err := m.Cache.Get(ctx, refreshKey(token))
+	return parseRefreshToken(value, err)
+}
+
+// ConsumeRefreshToken take the refresh token out of the store, of two concurrent refreshes only one finds it
+func (m *cacheSessionRepository) ConsumeRefreshToken(ctx context.Context, token string) (userId int, issuedAt int64, err error) {
+	value, err := m.Cache.Take(ctx, refreshKey(token))
+	return parseRefreshToken(value, err)
+}
+
+// parseRefreshToken read the user and the issue time kept with a refresh token
+func parseRefreshToken(value string, errGet error) (userId int, issuedAt int64, err error) {
+	if errGet == domain.ErrNotFound {
+		return 0, 0, errGet
+	}
+	if errGet != nil {
+		logrus.Error(errGet)
+		return 0, 0, constants.ErrGetTokenToRedis
+	}
+
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 {
+		return 0, 0, domain.ErrNotFound
+	}
+	userId, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, domain.ErrNotFound
+	}
+	issuedAt, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, domain.ErrNotFound
+	}
+	return
+}
+
+func (m *cacheSessionRepository) DeleteRefreshToken(ctx context.Context, token string) (err error) {
+	err = m.Cache.Delete(ctx, refreshKey(token))
+	if err != nil {
+		logrus.Error(err)
+		return constants.ErrSaveTokenToRedis
+	}
+	return
+}
+
+// RevokeToken put an access token in the revocation list until it expires on its own
+func (m *cacheSessionRepository) RevokeToken(ctx context.Context, tokenId string, ttl time.Duration) (err error) {
+	if ttl <= 0 {
+		return
+	}
+	err = m.Cache.Set(ctx, revokedTokenKey(tokenId), "1", ttl)
+	if err != nil {
+		logrus.Error(err)
+		return constants.ErrSaveTokenToRedis
+	}
+	return
+}
+
+func (m *cacheSessionRepository) IsTokenRevoked(ctx context.Context, tokenId string) (bool, error) {
+	_, err := m.Cache.Get(ctx, revokedTokenKey(tokenId))
+	if err == domain.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		logrus.Error(err)
+		return false, constants.ErrGetTokenToRedis
+	}
+	return true, nil
+}
+
+// RevokeUser reject every token of the user issued before at, access and refresh tokens alike.
+// The instant is kept in milliseconds so a token issued earlier in the same second is rejected too
+func (m *cacheSessionRepository) RevokeUser(ctx context.Context, userId int, at time.Time) (err error) {
+	err = m.Cache.Set(ctx, revokedUserKey(userId), strconv.FormatInt(utils.UnixMilli(at), 10), 0)
+	if err != nil {
+		logrus.Error(err)
+		return constants.ErrSaveTokenToRedis
+	}
+	return
+}
+
+func (m *cacheSessionRepository) GetUserRevokedAt(ctx context.Context, userId int) (int64, error) {
+	value, err := m.Cache.Get(ctx, revokedUserKey(userId))
+	if err == domain.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		logrus.Error(err)
+		return 0, constants.ErrGetTokenToRedis
+	}
+	at, _ := strconv.ParseInt(value, 10, 64)
+	return at, nil
+}
+
+// RevokeTerminal reject every PIN token issued on the terminal. A revoked terminal is never active again,
+// so the mark is kept without expiry
+func (m *cacheSessionRepository) RevokeTerminal(ctx context.Context, terminalId int64) (err error) {
+	err = m.Cache.Set(ctx, revokedTerminalKey(terminalId), "1", 0)
+	if err != nil {
+		logrus.Error(err)
+		return constants.ErrSaveTokenToRedis
+	}
+	return
+}
+
+func (m *cacheSessionRepository) IsTerminalRevoked(ctx context.Context, terminalId int64) (bool, error) {
+	_, err := m.Cache.Get(ctx, revokedTerminalKey(terminalId))
+	if err == domain.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		logrus.Error(err)
+		return false, constants.ErrGetTokenToRedis
+	}
+	return true, nil
+}
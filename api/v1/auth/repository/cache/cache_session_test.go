@@ -0,0 +1,73 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	_cache "mini_pos/api/cache"
+	"mini_pos/api/v1/auth/repository/cache"
+	"mini_pos/domain"
+)
+
+func TestRefreshToken(t *testing.T) {
+	ctx := context.Background()
+	repo := cache.NewCacheSessionRepository(_cache.NewMemoryCache())
+
+	err := repo.StoreRefreshToken(ctx, "token", 7, time.Hour)
+	assert.NoError(t, err)
+
+	userId, issuedAt, err := repo.GetRefreshToken(ctx, "token")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, userId)
+	assert.NotZero(t, issuedAt)
+
+	_, _, err = repo.GetRefreshToken(ctx, "other")
+	assert.Equal(t, domain.ErrNotFound, err)
+
+	assert.NoError(t, repo.DeleteRefreshToken(ctx, "token"))
+	_, _, err = repo.GetRefreshToken(ctx, "token")
+	assert.Equal(t, domain.ErrNotFound, err)
+
+	// a refresh token is consumed once
+	assert.NoError(t, repo.StoreRefreshToken(ctx, "token", 7, time.Hour))
+	userId, _, err = repo.ConsumeRefreshToken(ctx, "token")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, userId)
+	_, _, err = repo.ConsumeRefreshToken(ctx, "token")
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestRevoke(t *testing.T) {
+	ctx := context.Background()
+	repo := cache.NewCacheSessionRepository(_cache.NewMemoryCache())
+
+	revoked, err := repo.IsTokenRevoked(ctx, "jti")
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	assert.NoError(t, repo.RevokeToken(ctx, "jti", time.Hour))
+	revoked, err = repo.IsTokenRevoked(ctx, "jti")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	at, err := repo.GetUserRevokedAt(ctx, 7)
+	assert.NoError(t, err)
+	assert.Zero(t, at)
+
+	assert.NoError(t, repo.RevokeUser(ctx, 7, time.Unix(1600000000, 250*int64(time.Millisecond))))
+	at, err = repo.GetUserRevokedAt(ctx, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1600000000250), at)
+
+	revoked, err = repo.IsTerminalRevoked(ctx, 3)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	assert.NoError(t, repo.RevokeTerminal(ctx, 3))
+	revoked, err = repo.IsTerminalRevoked(ctx, 3)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/constants"
+	"mini_pos/domain"
+	"mini_pos/utils"
+)
+
+type cacheTwoFactorRepository struct {
+	Cache domain.Cache
+}
+
+// NewCacheTwoFactorRepository will create an object that represent the domain.TwoFactorRepository interface
+func NewCacheTwoFactorRepository(c domain.Cache) domain.TwoFactorRepository {
+	return &cacheTwoFactorRepository{c}
+}
+
+func challengeKey(token string) string {
+	return "2fa:challenge:" + utils.HashToken(token)
+}
+
+func setupKey(userId int) string {
+	return "2fa:setup:" + strconv.Itoa(userId)
+}
+
+func stepKey(userId int) string {
+	return "2fa:step:" + strconv.Itoa(userId)
+}
+
+// StoreChallenge keep the challenge until it expires
+func (m *cacheTwoFactorRepository) StoreChallenge(ctx context.Context, token string, a domain.TwoFactorPending) (err error) {
+	ttl := time.Until(a.ExpiresAt)
+	if ttl <= 0 {
+		return m.DeleteChallenge(ctx, token)
+	}
+	value, err := json.Marshal(a)
+	if err != nil {
+		return
+	}
+	err = m.Cache.Set(ctx, challengeKey(token), string(value), ttl)
+	if err != nil {
+		logrus.Error(err)
+		return constants.ErrSaveTokenToRedis
+	}
+	return
+}
+
+func (m *cacheTwoFactorRepository) GetChallenge(ctx context.Context, token string) (res domain.TwoFactorPending, err error) {
+	value, err := m.Cache.Get(ctx, challengeKey(token))
+	if err == domain.ErrNotFound {
+		return domain.TwoFactorPending{}, err
+	}
+	if err != nil {
+		logrus.Error(err)
+		return domain.TwoFactorPending{}, constants.ErrGetTokenToRedis
+	}
+	err = json.Unmarshal([]byte(value), &res)
+	if err != nil {
+		return domain.TwoFactorPending{}, domain.ErrNotFound
+	}
+	return
+}
+
+func (m *cacheTwoFactorRepository) DeleteChallenge(ctx context.Context, token string) (err error) {
+	err = m.Cache.Delete(ctx, challengeKey(token))
+	if err != nil {
+		logrus.Error(err)
+		return constants.ErrSaveTokenToRedis
+	}
+	return
+}
+
+func (m *cacheTwoFactorRepository) StoreSetup(ctx context.Context, userId int, secret string, ttl time.Duration) (err error) {
+	err = m.Cache.Set(ctx, setupKey(userId), secret, ttl)
+	if err != nil {
+		logrus.Error(err)
+		return constants.ErrSaveTokenToRedis
+	}
+	return
+}
+
+func (m *cacheTwoFactorRepository) GetSetup(ctx context.Context, userId int) (res string, err error) {
+	res, err = m.Cache.Get(ctx, setupKey(userId))
+	if err == domain.ErrNotFound {
+		return "", err
+	}
+	if err != nil {
+		logrus.Error(err)
+		return "", constants.ErrGetTokenToRedis
+	}
+	return
+}
+
+func (m *cacheTwoFactorRepository) DeleteSetup(ctx context.Context, userId int) (err error) {
+	err = m.Cache.Delete(ctx, setupKey(userId))
+	if err != nil {
+		logrus.Error(err)
+		return constants.ErrSaveTokenToRedis
+	}
+	return
+}
+
+// UseStep remember the last time step a code of the user was accepted for, so a code cannot be replayed
+func (m *cacheTwoFactorRepository) UseStep(ctx context.Context, userId int, step int64, ttl time.Duration) (ok bool, err error) {
+	value, err := m.Cache.Get(ctx, stepKey(userId))
+	if err != nil && err != domain.ErrNotFound {
+		logrus.Error(err)
+		return false, constants.ErrGetTokenToRedis
+	}
+	if err == nil {
+		last, errParse := strconv.ParseInt(value, 10, 64)
+		if errParse == nil && step <= last {
+			return false, nil
+		}
+	}
+	err = m.Cache.Set(ctx, stepKey(userId), strconv.FormatInt(step, 10), ttl)
+	if err != nil {
+		logrus.Error(err)
+		return false, constants.ErrSaveTokenToRedis
+	}
+	return true, nil
+}
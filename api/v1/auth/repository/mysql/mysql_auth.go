@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"mini_pos/utils"
+	"time"
 
 	"mini_pos/domain"
 )
@@ -33,19 +34,73 @@ func (m *mysqlAuthRepo) getUser(ctx context.Context, query string, args ...inter
 		&res.UserName,
 		&res.Email,
 		&res.Password,
+		&res.Pin,
+		&res.TOTPSecret,
 		&res.RoleID,
+		&res.Status,
 	)
 
 	return
 }
 
 func (m *mysqlAuthRepo) GetEmailUser(ctx context.Context, username string) (domain.User, error) {
-	query := `SELECT id, fullname as name, username, email, password, role_id FROM users WHERE email=? `
+	query := `SELECT id, fullname as name, username, email, password, IFNULL(pin,''), IFNULL(totp_secret,''), role_id, status FROM users WHERE email=? `
 	return m.getUser(ctx, query, username)
 }
 
+// GetUserByID load the user a refresh token was issued to
+func (m *mysqlAuthRepo) GetUserByID(ctx context.Context, id int) (domain.User, error) {
+	query := `SELECT id, fullname as name, username, email, password, IFNULL(pin,''), IFNULL(totp_secret,''), role_id, status FROM users WHERE id=? `
+	return m.getUser(ctx, query, id)
+}
+
+// FetchPermissions return the permission names granted to the role
+func (m *mysqlAuthRepo) FetchPermissions(ctx context.Context, roleId int) (res []string, err error) {
+	query := `SELECT permissions.name FROM role_permissions
+				join permissions on permissions.id = role_permissions.permission_id
+				WHERE role_permissions.role_id = ? ORDER BY permissions.name`
+	rows, err := m.DB.QueryContext(ctx, query, roleId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res = make([]string, 0)
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, name)
+	}
+	return res, rows.Err()
+}
+
+// FetchOutlets return the active outlets the user is assigned to in user_outlets
+func (m *mysqlAuthRepo) FetchOutlets(ctx context.Context, userId int) (res []int64, err error) {
+	query := `SELECT user_outlets.outlet_id FROM user_outlets join outlets on outlets.id = user_outlets.outlet_id
+			WHERE user_outlets.user_id = ? and outlets.status = ? ORDER BY user_outlets.outlet_id`
+	rows, err := m.DB.QueryContext(ctx, query, userId, domain.OutletActive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res = make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, id)
+	}
+	return res, rows.Err()
+}
+
 func (m *mysqlAuthRepo) Register(ctx context.Context, a domain.RegisterUser) (id int, err error) {
-	query := `INSERT  users SET email=?, password=?, username=?, fullname=?`
+	query := `INSERT  users SET email=?, password=?, username=?, fullname=?, role_id=0, status=?, created_at=?`
 	stmt, err := m.DB.PrepareContext(ctx, query)
 	if err != nil {
 		return
@@ -54,7 +109,7 @@ func (m *mysqlAuthRepo) Register(ctx context.Context, a domain.RegisterUser) (id
 	if err != nil {
 		return
 	}
-	res, err := stmt.ExecContext(ctx, a.Email, password, a.UserName, a.Name)
+	res, err := stmt.ExecContext(ctx, a.Email, password, a.UserName, a.Name, domain.UserPending, time.Now())
 	if err != nil {
 		return
 	}
@@ -64,4 +119,118 @@ func (m *mysqlAuthRepo) Register(ctx context.Context, a domain.RegisterUser) (id
 	}
 	id = int(lastID)
 	return
-}
\ No newline at end of file
+}
+// GetUserByUserName load the cashier switching on a terminal
+func (m *mysqlAuthRepo) GetUserByUserName(ctx context.Context, username string) (domain.User, error) {
+	query := `SELECT id, fullname as name, username, email, password, IFNULL(pin,''), IFNULL(totp_secret,''), role_id, status FROM users WHERE username=? `
+	return m.getUser(ctx, query, username)
+}
+
+// SetPin replace the PIN hash of the user
+func (m *mysqlAuthRepo) SetPin(ctx context.Context, id int, pin string) (err error) {
+	query := `UPDATE users SET pin=?, updated_at=? WHERE id=?`
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	_, err = stmt.ExecContext(ctx, pin, time.Now(), id)
+	return
+}
+
+// UpdatePassword replace the password hash of the user
+func (m *mysqlAuthRepo) UpdatePassword(ctx context.Context, id int, password string) (err error) {
+	query := `UPDATE users SET password=?, updated_at=? WHERE id=?`
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	res, err := stmt.ExecContext(ctx, password, time.Now(), id)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		return domain.ErrNotFound
+	}
+	return
+}
+
+// UpdateProfile change the fields of the user that the user may edit itself
+func (m *mysqlAuthRepo) UpdateProfile(ctx context.Context, id int, a *domain.RequestUpdateProfile) (err error) {
+	query := `UPDATE users SET fullname=?, updated_at=? WHERE id=?`
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	_, err = stmt.ExecContext(ctx, a.Name, time.Now(), id)
+	return
+}
+
+// SetTOTPSecret replace the TOTP secret of the user, an empty secret turns 2FA off
+func (m *mysqlAuthRepo) SetTOTPSecret(ctx context.Context, id int, secret string) (err error) {
+	query := `UPDATE users SET totp_secret=?, updated_at=? WHERE id=?`
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	var value interface{}
+	if secret != "" {
+		value = secret
+	}
+	_, err = stmt.ExecContext(ctx, value, time.Now(), id)
+	return
+}
+
+// ReplaceRecoveryCodes drop the recovery codes of the user and keep the hashes of the new ones
+func (m *mysqlAuthRepo) ReplaceRecoveryCodes(ctx context.Context, userId int, hashes []string) (err error) {
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id=?`, userId)
+	if err != nil {
+		return
+	}
+	if len(hashes) > 0 {
+		stmt, errPrepare := tx.PrepareContext(ctx, `INSERT user_recovery_codes SET user_id=?, code_hash=?, create_at=?`)
+		if errPrepare != nil {
+			return errPrepare
+		}
+		defer stmt.Close()
+		now := time.Now()
+		for _, hash := range hashes {
+			_, err = stmt.ExecContext(ctx, userId, hash, now)
+			if err != nil {
+				return
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// UseRecoveryCode mark the recovery code of the user as used, it return false when there is no such unused code
+func (m *mysqlAuthRepo) UseRecoveryCode(ctx context.Context, userId int, hash string) (ok bool, err error) {
+	query := `UPDATE user_recovery_codes SET used_at=? WHERE user_id=? AND code_hash=? AND used_at IS NULL`
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	res, err := stmt.ExecContext(ctx, time.Now(), userId, hash)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	return affect > 0, nil
+}
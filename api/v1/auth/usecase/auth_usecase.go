@@ -2,20 +2,71 @@ package usecase
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
+	"mini_pos/config"
 	"mini_pos/constants"
 	"mini_pos/domain"
+	"mini_pos/utils"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
+const (
+	otpLength        = 6
+	otpLifeTime      = 10 * time.Minute
+	resetTokenLifeTime = 15 * time.Minute
+	defaultOTPAttempt = 5
+)
+
+// An OTP is mailed again to an email after otpResendCooldown, and at most otpSendsPerEmail times
+// to an email or otpSendsPerIP times from an IP in otpSendWindow, so the attempts of each OTP stay meaningful
+const (
+	otpResendCooldown = time.Minute
+	otpSendWindow     = time.Hour
+	otpSendsPerEmail  = 3
+	otpSendsPerIP     = 10
+)
+
+// A login failing past the free failures of an account or of an IP locks it,
+// for lockBase doubled on each further failure up to lockMax
+const (
+	accountFreeFailures  = 3
+	ipFreeFailures       = 10
+	lockBase             = 30 * time.Second
+	lockMax              = 15 * time.Minute
+	attemptWindow        = time.Hour
+	pinFreeFailures      = 5
+	terminalFreeFailures = 20
+)
+
 type authUsecase struct {
 	authRepo domain.AuthRepository
+	sessionRepo domain.SessionRepository
+	otpRepo domain.OTPRepository
+	attemptRepo domain.LoginAttemptRepository
+	twoFactorRepo domain.TwoFactorRepository
+	terminalRepo domain.TerminalRepository
+	auditRepo domain.AuditRepository
+	mailer domain.Mailer
+	cfg config.Config
 	contextTimeout time.Duration
 }
 
-func NewAuthUseCase ( auth domain.AuthRepository, timeout time.Duration) domain.AuthUseCase {
+func NewAuthUseCase ( auth domain.AuthRepository, session domain.SessionRepository, otp domain.OTPRepository, attempt domain.LoginAttemptRepository, twoFactor domain.TwoFactorRepository, terminal domain.TerminalRepository, audit domain.AuditRepository, mailer domain.Mailer, cfg config.Config, timeout time.Duration) domain.AuthUseCase {
 	return &authUsecase{
 	 authRepo: auth,
+	 sessionRepo: session,
+	 otpRepo: otp,
+	 attemptRepo: attempt,
+	 twoFactorRepo: twoFactor,
+	 terminalRepo: terminal,
+	 auditRepo: audit,
+	 mailer: mailer,
+	 cfg: cfg,
 	 contextTimeout: timeout,
 	}
 }
@@ -46,5 +97,648 @@ func (a *authUsecase) Register(c context.Context, register domain.RegisterUser)(
 	res.Name=     register.Name
 	res.UserName= register.UserName
 	res.Email=    register.Email
+	res.Status=   domain.UserPending
 	return res, nil
 }
+
+// GetPermissions return the permissions of the role, they are carried by the token of the user
+func (a *authUsecase) GetPermissions(c context.Context, roleId int) (res []string, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.authRepo.FetchPermissions(ctx, roleId)
+}
+
+// GetOutlets return the outlets of the user, they are carried by the token of the user
+func (a *authUsecase) GetOutlets(c context.Context, userId int) (res []int64, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.authRepo.FetchOutlets(ctx, userId)
+}
+
+// CreateRefreshToken issue an opaque refresh token for the user, only its hash is kept by the session store
+func (a *authUsecase) CreateRefreshToken(c context.Context, userId int, ttl time.Duration) (res string, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err = utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	err = a.sessionRepo.StoreRefreshToken(ctx, res, userId, ttl)
+	if err != nil {
+		return "", err
+	}
+	return
+}
+
+// Refresh consume the refresh token and return the user to issue a new session to,
+// a refresh token is rotated so it can be used only once
+func (a *authUsecase) Refresh(c context.Context, refreshToken string) (res domain.User, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	userId, issuedAt, err := a.sessionRepo.ConsumeRefreshToken(ctx, refreshToken)
+	if err == domain.ErrNotFound {
+		return domain.User{}, constants.ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return
+	}
+
+	revokedAt, err := a.sessionRepo.GetUserRevokedAt(ctx, userId)
+	if err != nil {
+		return
+	}
+	if issuedAt < revokedAt {
+		return domain.User{}, constants.ErrRefreshTokenInvalid
+	}
+
+	res, err = a.authRepo.GetUserByID(ctx, userId)
+	if err != nil {
+		return domain.User{}, constants.ErrRefreshTokenInvalid
+	}
+	if res.Status == domain.UserPending {
+		return domain.User{}, constants.ErrUserPending
+	}
+	if res.Status != domain.UserActive {
+		return domain.User{}, constants.ErrUserInactive
+	}
+	return
+}
+
+// Logout revoke the access token until it expires and drop the refresh token of the session
+func (a *authUsecase) Logout(c context.Context, userId int, tokenId string, expiresAt int64, refreshToken string) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	err = a.sessionRepo.RevokeToken(ctx, tokenId, time.Until(time.Unix(expiresAt, 0)))
+	if err != nil {
+		return
+	}
+	if refreshToken == "" {
+		return
+	}
+
+	owner, _, err := a.sessionRepo.GetRefreshToken(ctx, refreshToken)
+	if err == domain.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return
+	}
+	if owner != userId {
+		return nil
+	}
+	return a.sessionRepo.DeleteRefreshToken(ctx, refreshToken)
+}
+
+// RevokeUser end every session of the user, the tokens already issued stop working at once
+func (a *authUsecase) RevokeUser(c context.Context, userId int) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.sessionRepo.RevokeUser(ctx, userId, time.Now())
+}
+
+// IsRevoked return true when the access token was logged out, or issued before the sessions of its user were revoked.
+// issuedAt is in milliseconds
+func (a *authUsecase) IsRevoked(c context.Context, userId int, tokenId string, issuedAt int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if tokenId != "" {
+		revoked, err := a.sessionRepo.IsTokenRevoked(ctx, tokenId)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	revokedAt, err := a.sessionRepo.GetUserRevokedAt(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	return issuedAt < revokedAt, nil
+}
+
+// IsTerminalRevoked return true when the terminal a PIN token was issued on has been revoked
+func (a *authUsecase) IsTerminalRevoked(c context.Context, terminalId int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.sessionRepo.IsTerminalRevoked(ctx, terminalId)
+}
+
+// maxOTPAttempt return the wrong attempts allowed on an OTP before it is dropped
+func (a *authUsecase) maxOTPAttempt() int {
+	if a.cfg.Mailer.MaxAttempt == 0 {
+		return defaultOTPAttempt
+	}
+	return a.cfg.Mailer.MaxAttempt
+}
+
+func otpEmailKey(email string) string {
+	return "otp_email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func otpIPKey(ip string) string {
+	return "otp_ip:" + ip
+}
+
+// throttleOTP count a request for an OTP on the email and on the IP, with the failed login counters,
+// and refuse it during the cooldown of the email or once the sends of the window are used up
+func (a *authUsecase) throttleOTP(ctx context.Context, email string, ip string) error {
+	emailKey := otpEmailKey(email)
+	locked, err := a.isLocked(ctx, emailKey)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return constants.ErrOTPResendLimit
+	}
+
+	sends, err := a.attemptRepo.AddFailure(ctx, otpIPKey(ip), otpSendWindow)
+	if err != nil {
+		return err
+	}
+	if sends > otpSendsPerIP {
+		return constants.ErrOTPResendLimit
+	}
+	sends, err = a.attemptRepo.AddFailure(ctx, emailKey, otpSendWindow)
+	if err != nil {
+		return err
+	}
+	if sends > otpSendsPerEmail {
+		return constants.ErrOTPResendLimit
+	}
+	return a.attemptRepo.Lock(ctx, emailKey, time.Now().Add(otpResendCooldown))
+}
+
+// ForgotPassword mail an OTP to the account of the email, an unknown email is not told apart
+// so the endpoint cannot be used to find the registered accounts. The requests are throttled
+// the same for every email, see throttleOTP
+func (a *authUsecase) ForgotPassword(c context.Context, email string, ip string) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	err = a.throttleOTP(ctx, email, ip)
+	if err != nil {
+		return
+	}
+
+	user, err := a.authRepo.GetEmailUser(ctx, email)
+	if err != nil {
+		return nil
+	}
+
+	code, err := utils.RandomDigits(otpLength)
+	if err != nil {
+		return
+	}
+	err = a.otpRepo.StoreOTP(ctx, constants.OTPPurposeResetPasswordUser, user.Email, domain.OTP{
+		Hash:      utils.HashToken(code),
+		ExpiresAt: time.Now().Add(otpLifeTime),
+	})
+	if err != nil {
+		return
+	}
+
+	link := ""
+	if a.cfg.ResetPassword.UserLink != "" {
+		link = a.cfg.ResetPassword.UserLink + "?email=" + url.QueryEscape(user.Email)
+	}
+	return a.mailer.Send(ctx, user.Email, "Reset Password", constants.MailResetPasswordTemplate, domain.ResetPasswordMail{
+		Name:            user.Name,
+		OTP:             code,
+		Link:            link,
+		LogoURL:         constants.DESILogoURL,
+		ExpiresInMinute: int(otpLifeTime / time.Minute),
+		MaxAttempt:      a.maxOTPAttempt(),
+	})
+}
+
+// VerifyOTP trade a valid OTP for a short lived reset token, every attempt is counted before the OTP is
+// compared so parallel guesses cannot share an attempt, and the OTP is dropped once the attempts are used up
+func (a *authUsecase) VerifyOTP(c context.Context, email string, code string) (res domain.ResetTokenResponse, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	otp, err := a.otpRepo.GetOTP(ctx, constants.OTPPurposeResetPasswordUser, email)
+	if err == domain.ErrNotFound {
+		return domain.ResetTokenResponse{}, constants.ErrGetOTPFromRedis
+	}
+	if err != nil {
+		return
+	}
+
+	attempts, err := a.otpRepo.AddOTPAttempt(ctx, constants.OTPPurposeResetPasswordUser, email, otpLifeTime)
+	if err != nil {
+		return
+	}
+	match := subtle.ConstantTimeCompare([]byte(otp.Hash), []byte(utils.HashToken(code))) == 1
+	if attempts > a.maxOTPAttempt() || !match && attempts == a.maxOTPAttempt() {
+		err = a.otpRepo.DeleteOTP(ctx, constants.OTPPurposeResetPasswordUser, email)
+		if err != nil {
+			return
+		}
+		return domain.ResetTokenResponse{}, constants.ErrOTPTooManyAttempts
+	}
+	if !match {
+		return domain.ResetTokenResponse{}, constants.ErrOTPNotMatch
+	}
+
+	err = a.otpRepo.DeleteOTP(ctx, constants.OTPPurposeResetPasswordUser, email)
+	if err != nil {
+		return
+	}
+	user, err := a.authRepo.GetEmailUser(ctx, email)
+	if err != nil {
+		return domain.ResetTokenResponse{}, constants.ErrGetOTPFromRedis
+	}
+
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		return
+	}
+	err = a.otpRepo.StoreResetToken(ctx, token, user.ID, resetTokenLifeTime)
+	if err != nil {
+		return
+	}
+	return domain.ResetTokenResponse{
+		ResetToken: token,
+		ExpiresAt:  time.Now().Add(resetTokenLifeTime).Unix(),
+	}, nil
+}
+
+// ResetPassword set the new password with a reset token, the token is used once
+// and every session of the user is ended
+func (a *authUsecase) ResetPassword(c context.Context, resetToken string, password string) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	userId, err := a.otpRepo.GetResetToken(ctx, resetToken)
+	if err == domain.ErrNotFound {
+		return constants.ErrResetTokenInvalid
+	}
+	if err != nil {
+		return
+	}
+	err = a.otpRepo.DeleteResetToken(ctx, resetToken)
+	if err != nil {
+		return
+	}
+
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return
+	}
+	err = a.authRepo.UpdatePassword(ctx, userId, hash)
+	if err != nil {
+		return
+	}
+	return a.sessionRepo.RevokeUser(ctx, userId, time.Now())
+}
+
+// GetProfile return the account of the user with its current permissions and outlets
+func (a *authUsecase) GetProfile(c context.Context, userId int) (res domain.Profile, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	user, err := a.authRepo.GetUserByID(ctx, userId)
+	if err != nil {
+		return domain.Profile{}, domain.ErrNotFound
+	}
+	res = domain.Profile{
+		ID:       user.ID,
+		Name:     user.Name,
+		UserName: user.UserName,
+		Email:    user.Email,
+		RoleID:   user.RoleID,
+		Status:   user.Status,
+		TwoFactor: user.TOTPSecret != "",
+	}
+	res.Permissions, err = a.authRepo.FetchPermissions(ctx, user.RoleID)
+	if err != nil {
+		return domain.Profile{}, err
+	}
+	res.Outlets, err = a.authRepo.FetchOutlets(ctx, user.ID)
+	if err != nil {
+		return domain.Profile{}, err
+	}
+	return
+}
+
+func (a *authUsecase) UpdateProfile(c context.Context, userId int, m *domain.RequestUpdateProfile) (res domain.Profile, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	err = a.authRepo.UpdateProfile(ctx, userId, m)
+	if err != nil {
+		return
+	}
+	return a.GetProfile(ctx, userId)
+}
+
+// ChangePassword set a new password after checking the old one, every session opened before is ended
+// and the user is returned to open the session of the caller again
+func (a *authUsecase) ChangePassword(c context.Context, userId int, m *domain.RequestChangePassword) (res domain.User, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err = a.authRepo.GetUserByID(ctx, userId)
+	if err != nil {
+		return domain.User{}, domain.ErrNotFound
+	}
+	if !utils.CheckPasswordHash(m.OldPassword, res.Password) {
+		return domain.User{}, constants.ErrOldPasswordNotMatch
+	}
+	if m.OldPassword == m.Password {
+		return domain.User{}, constants.ErrPasswordAlreadyTaken
+	}
+
+	hash, err := utils.HashPassword(m.Password)
+	if err != nil {
+		return domain.User{}, err
+	}
+	err = a.authRepo.UpdatePassword(ctx, userId, hash)
+	if err != nil {
+		return domain.User{}, err
+	}
+	err = a.sessionRepo.RevokeUser(ctx, userId, time.Now())
+	if err != nil {
+		return domain.User{}, err
+	}
+	res.Password = hash
+	return
+}
+
+func accountAttemptKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+// lockFor return how long a key stays locked after its failures, 0 while they are still free
+func lockFor(failures int, free int) time.Duration {
+	if failures < free {
+		return 0
+	}
+	lock := lockBase
+	for i := free; i < failures && lock < lockMax; i++ {
+		lock *= 2
+	}
+	if lock > lockMax {
+		lock = lockMax
+	}
+	return lock
+}
+
+// isLocked return true while the key is locked out
+func (a *authUsecase) isLocked(ctx context.Context, key string) (bool, error) {
+	attempt, err := a.attemptRepo.GetAttempt(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	return time.Now().Before(attempt.LockedUntil), nil
+}
+
+// recordFailure count a failed login on the key and lock it once the free failures are used up,
+// the count is atomic so parallel failures each lengthen the lockout
+func (a *authUsecase) recordFailure(ctx context.Context, key string, free int) error {
+	failures, err := a.attemptRepo.AddFailure(ctx, key, attemptWindow)
+	if err != nil {
+		return err
+	}
+	lock := lockFor(failures, free)
+	if lock == 0 {
+		return nil
+	}
+	return a.attemptRepo.Lock(ctx, key, time.Now().Add(lock))
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+// checkDummyPassword spend the time of a password check when the account does not exist,
+// so the answer time does not tell the registered emails apart
+func checkDummyPassword(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = utils.HashPassword("dummy-password")
+	})
+	utils.CheckPasswordHash(password, dummyHash)
+}
+
+// Login check the credentials against the failed login counters of the account and of the IP.
+// Every wrong email, username or password answers the same ErrEmailAndPasswordNotMatch,
+// the status of the account is told only once the credentials are right
+func (a *authUsecase) Login(c context.Context, m domain.UserLogin, ip string) (res domain.User, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	accountKey := accountAttemptKey(m.Email)
+	ipKey := ipAttemptKey(ip)
+	for _, key := range []string{accountKey, ipKey} {
+		locked, err := a.isLocked(ctx, key)
+		if err != nil {
+			return domain.User{}, err
+		}
+		if locked {
+			return domain.User{}, constants.ErrLoginLocked
+		}
+	}
+
+	res, err = a.authRepo.GetEmailUser(ctx, m.Email)
+	valid := false
+	if err != nil {
+		checkDummyPassword(m.Password)
+	} else {
+		valid = utils.CheckPasswordHash(m.Password, res.Password) && m.Username == res.UserName
+	}
+	if !valid {
+		err = a.recordFailure(ctx, accountKey, accountFreeFailures)
+		if err != nil {
+			return domain.User{}, err
+		}
+		err = a.recordFailure(ctx, ipKey, ipFreeFailures)
+		if err != nil {
+			return domain.User{}, err
+		}
+		return domain.User{}, constants.ErrEmailAndPasswordNotMatch
+	}
+
+	// a successful login clears the account only, the IP keeps counting for the other accounts it tries
+	err = a.attemptRepo.DeleteAttempt(ctx, accountKey)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if res.Status == domain.UserPending {
+		return domain.User{}, constants.ErrUserPending
+	}
+	if res.Status != domain.UserActive {
+		return domain.User{}, constants.ErrUserInactive
+	}
+	return
+}
+
+// Unlock clear the failed logins of the account and of the IP given, an empty one is left as it is
+func (a *authUsecase) Unlock(c context.Context, email string, ip string) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if email != "" {
+		err = a.attemptRepo.DeleteAttempt(ctx, accountAttemptKey(email))
+		if err != nil {
+			return
+		}
+	}
+	if ip != "" {
+		err = a.attemptRepo.DeleteAttempt(ctx, ipAttemptKey(ip))
+	}
+	return
+}
+
+func pinAttemptKey(userId int) string {
+	return "pin:user:" + strconv.Itoa(userId)
+}
+
+func terminalAttemptKey(terminalId int64) string {
+	return "pin:terminal:" + strconv.FormatInt(terminalId, 10)
+}
+
+// PinLogin switch the active cashier of a trusted terminal with the PIN of the cashier.
+// The PIN failures lock the cashier and the terminal apart from the password logins,
+// and every wrong username or PIN answers the same ErrPinNotMatch
+func (a *authUsecase) PinLogin(c context.Context, m domain.RequestPinLogin) (res domain.User, terminal domain.Terminal, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	terminal, err = a.terminalRepo.GetByToken(ctx, utils.HashToken(m.TerminalToken))
+	if err == domain.ErrNotFound || (err == nil && terminal.Status != domain.TerminalActive) {
+		return domain.User{}, domain.Terminal{}, constants.ErrTerminalInvalid
+	}
+	if err != nil {
+		return
+	}
+
+	terminalKey := terminalAttemptKey(terminal.ID)
+	locked, err := a.isLocked(ctx, terminalKey)
+	if err != nil {
+		return
+	}
+	if locked {
+		return domain.User{}, domain.Terminal{}, constants.ErrLoginLocked
+	}
+
+	res, err = a.authRepo.GetUserByUserName(ctx, m.UserName)
+	valid := false
+	pinKey := ""
+	if err != nil || res.Pin == "" {
+		checkDummyPassword(m.Pin)
+	} else {
+		pinKey = pinAttemptKey(res.ID)
+		locked, err = a.isLocked(ctx, pinKey)
+		if err != nil {
+			return
+		}
+		if locked {
+			return domain.User{}, domain.Terminal{}, constants.ErrLoginLocked
+		}
+		valid = utils.CheckPasswordHash(m.Pin, res.Pin)
+	}
+	if !valid {
+		if pinKey != "" {
+			err = a.recordFailure(ctx, pinKey, pinFreeFailures)
+			if err != nil {
+				return
+			}
+		}
+		err = a.recordFailure(ctx, terminalKey, terminalFreeFailures)
+		if err != nil {
+			return
+		}
+		return domain.User{}, domain.Terminal{}, constants.ErrPinNotMatch
+	}
+
+	err = a.attemptRepo.DeleteAttempt(ctx, pinKey)
+	if err != nil {
+		return
+	}
+	if res.Status == domain.UserPending {
+		return domain.User{}, domain.Terminal{}, constants.ErrUserPending
+	}
+	if res.Status != domain.UserActive {
+		return domain.User{}, domain.Terminal{}, constants.ErrUserInactive
+	}
+
+	// an account protected by 2FA cannot be entered with a PIN alone
+	if a.requiresTwoFactor(res) {
+		return domain.User{}, domain.Terminal{}, constants.ErrTwoFactorRequired
+	}
+
+	assigned, err := a.worksIn(ctx, res, terminal.OutletID)
+	if err != nil {
+		return
+	}
+	if !assigned {
+		return domain.User{}, domain.Terminal{}, constants.ErrOutletForbidden
+	}
+
+	err = a.terminalRepo.Touch(ctx, terminal.ID)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// SetPin set the PIN the user switches on the terminals with, the password of the user is checked first
+func (a *authUsecase) SetPin(c context.Context, userId int, m *domain.RequestSetPin) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	user, err := a.authRepo.GetUserByID(ctx, userId)
+	if err != nil {
+		return domain.ErrNotFound
+	}
+	if !utils.CheckPasswordHash(m.Password, user.Password) {
+		return constants.ErrPasswordNotMatch
+	}
+
+	hash, err := utils.HashPassword(m.Pin)
+	if err != nil {
+		return
+	}
+	err = a.authRepo.SetPin(ctx, userId, hash)
+	if err != nil {
+		return
+	}
+	return a.attemptRepo.DeleteAttempt(ctx, pinAttemptKey(userId))
+}
+
+// worksIn tell whether the user is assigned to the outlet, or works in every outlet
+func (a *authUsecase) worksIn(ctx context.Context, user domain.User, outletId int64) (bool, error) {
+	permissions, err := a.authRepo.FetchPermissions(ctx, user.RoleID)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range permissions {
+		if p == domain.PermOutletAll {
+			return true, nil
+		}
+	}
+
+	outlets, err := a.authRepo.FetchOutlets(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, id := range outlets {
+		if id == outletId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
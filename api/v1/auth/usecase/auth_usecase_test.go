@@ -1 +1,471 @@
-package usecase
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	_cache "mini_pos/api/cache"
+	"mini_pos/api/v1/auth/repository/cache"
+	"mini_pos/api/v1/auth/usecase"
+	"mini_pos/config"
+	"mini_pos/constants"
+	"mini_pos/domain"
+	"mini_pos/utils"
+)
+
+// stubAuthRepo keep a single user in memory
+type stubAuthRepo struct {
+	user     domain.User
+	outlets  []int64
+	recovery map[string]bool // code hash to used
+}
+
+func (m *stubAuthRepo) GetEmailUser(ctx context.Context, email string) (domain.User, error) {
+	if email != m.user.Email {
+		return domain.User{}, domain.ErrNotFound
+	}
+	return m.user, nil
+}
+
+func (m *stubAuthRepo) GetUserByUserName(ctx context.Context, username string) (domain.User, error) {
+	if username != m.user.UserName {
+		return domain.User{}, domain.ErrNotFound
+	}
+	return m.user, nil
+}
+
+func (m *stubAuthRepo) SetPin(ctx context.Context, id int, pin string) error {
+	m.user.Pin = pin
+	return nil
+}
+
+func (m *stubAuthRepo) SetTOTPSecret(ctx context.Context, id int, secret string) error {
+	m.user.TOTPSecret = secret
+	return nil
+}
+
+func (m *stubAuthRepo) ReplaceRecoveryCodes(ctx context.Context, userId int, hashes []string) error {
+	m.recovery = make(map[string]bool)
+	for _, hash := range hashes {
+		m.recovery[hash] = false
+	}
+	return nil
+}
+
+func (m *stubAuthRepo) UseRecoveryCode(ctx context.Context, userId int, hash string) (bool, error) {
+	used, ok := m.recovery[hash]
+	if !ok || used {
+		return false, nil
+	}
+	m.recovery[hash] = true
+	return true, nil
+}
+
+func (m *stubAuthRepo) GetUserByID(ctx context.Context, id int) (domain.User, error) {
+	if id != m.user.ID {
+		return domain.User{}, domain.ErrNotFound
+	}
+	return m.user, nil
+}
+
+func (m *stubAuthRepo) UpdatePassword(ctx context.Context, id int, password string) error {
+	m.user.Password = password
+	return nil
+}
+
+func (m *stubAuthRepo) UpdateProfile(ctx context.Context, id int, a *domain.RequestUpdateProfile) error {
+	m.user.Name = a.Name
+	return nil
+}
+
+func (m *stubAuthRepo) Register(ctx context.Context, register domain.RegisterUser) (int, error) {
+	return 0, nil
+}
+
+func (m *stubAuthRepo) FetchPermissions(ctx context.Context, roleId int) ([]string, error) {
+	return nil, nil
+}
+
+func (m *stubAuthRepo) FetchOutlets(ctx context.Context, userId int) ([]int64, error) {
+	return m.outlets, nil
+}
+
+// stubTerminalRepo keep a single terminal in memory
+type stubTerminalRepo struct {
+	domain.TerminalRepository
+	terminal  domain.Terminal
+	tokenHash string
+	touched   bool
+}
+
+func (m *stubTerminalRepo) GetByToken(ctx context.Context, tokenHash string) (domain.Terminal, error) {
+	if tokenHash != m.tokenHash {
+		return domain.Terminal{}, domain.ErrNotFound
+	}
+	return m.terminal, nil
+}
+
+func (m *stubTerminalRepo) Touch(ctx context.Context, id int64) error {
+	m.touched = true
+	return nil
+}
+
+// stubAuditRepo keep the audit logs in memory
+type stubAuditRepo struct {
+	domain.AuditRepository
+	logs []domain.AuditLog
+}
+
+func (m *stubAuditRepo) Store(ctx context.Context, a *domain.AuditLog) error {
+	m.logs = append(m.logs, *a)
+	return nil
+}
+
+// fakeMailer keep the last mail instead of sending it
+type fakeMailer struct {
+	to   string
+	data domain.ResetPasswordMail
+}
+
+func (m *fakeMailer) Send(ctx context.Context, to string, subject string, template string, data interface{}) error {
+	m.to = to
+	m.data = data.(domain.ResetPasswordMail)
+	return nil
+}
+
+func newUsecase(repo domain.AuthRepository, mailer domain.Mailer) domain.AuthUseCase {
+	return newTerminalUsecase(repo, &stubTerminalRepo{}, mailer)
+}
+
+func newTerminalUsecase(repo domain.AuthRepository, terminal domain.TerminalRepository, mailer domain.Mailer) domain.AuthUseCase {
+	return newConfigUsecase(repo, terminal, mailer, config.Config{Mailer: config.Mailer{MaxAttempt: 3}})
+}
+
+func newConfigUsecase(repo domain.AuthRepository, terminal domain.TerminalRepository, mailer domain.Mailer, cfg config.Config) domain.AuthUseCase {
+	store := _cache.NewMemoryCache()
+	return usecase.NewAuthUseCase(repo, cache.NewCacheSessionRepository(store), cache.NewCacheOTPRepository(store),
+		cache.NewCacheLoginAttemptRepository(store), cache.NewCacheTwoFactorRepository(store), terminal, &stubAuditRepo{}, mailer, cfg, time.Second)
+}
+
+func TestResetPassword(t *testing.T) {
+	ctx := context.Background()
+	repo := &stubAuthRepo{user: domain.User{ID: 7, Status: domain.UserActive, Name: "Admin", Email: "admin@admin.com", Password: "old"}}
+	mailer := &fakeMailer{}
+	u := newUsecase(repo, mailer)
+
+	assert.NoError(t, u.ForgotPassword(ctx, "unknown@admin.com", "10.0.0.1"))
+	assert.Empty(t, mailer.to)
+
+	assert.NoError(t, u.ForgotPassword(ctx, "admin@admin.com", "10.0.0.1"))
+	assert.Equal(t, "admin@admin.com", mailer.to)
+	assert.Len(t, mailer.data.OTP, 6)
+
+	_, err := u.VerifyOTP(ctx, "admin@admin.com", "wrong")
+	assert.Equal(t, constants.ErrOTPNotMatch, err)
+
+	res, err := u.VerifyOTP(ctx, "admin@admin.com", mailer.data.OTP)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, res.ResetToken)
+
+	// the OTP is used once
+	_, err = u.VerifyOTP(ctx, "admin@admin.com", mailer.data.OTP)
+	assert.Equal(t, constants.ErrGetOTPFromRedis, err)
+
+	assert.NoError(t, u.ResetPassword(ctx, res.ResetToken, "N3w-Password"))
+	assert.True(t, utils.CheckPasswordHash("N3w-Password", repo.user.Password))
+	assert.Equal(t, constants.ErrResetTokenInvalid, u.ResetPassword(ctx, res.ResetToken, "N3w-Password"))
+
+	// the sessions opened before the reset are ended
+	revoked, err := u.IsRevoked(ctx, 7, "", utils.UnixMilli(time.Now().Add(-time.Minute)))
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestVerifyOTPAttempts(t *testing.T) {
+	ctx := context.Background()
+	repo := &stubAuthRepo{user: domain.User{ID: 7, Status: domain.UserActive, Email: "admin@admin.com"}}
+	mailer := &fakeMailer{}
+	u := newUsecase(repo, mailer)
+
+	assert.NoError(t, u.ForgotPassword(ctx, "admin@admin.com", "10.0.0.1"))
+	_, err := u.VerifyOTP(ctx, "admin@admin.com", "wrong")
+	assert.Equal(t, constants.ErrOTPNotMatch, err)
+	_, err = u.VerifyOTP(ctx, "admin@admin.com", "wrong")
+	assert.Equal(t, constants.ErrOTPNotMatch, err)
+	_, err = u.VerifyOTP(ctx, "admin@admin.com", "wrong")
+	assert.Equal(t, constants.ErrOTPTooManyAttempts, err)
+
+	// the right OTP no longer works once the attempts are used up
+	_, err = u.VerifyOTP(ctx, "admin@admin.com", mailer.data.OTP)
+	assert.Equal(t, constants.ErrGetOTPFromRedis, err)
+
+	// a new OTP is not mailed at once, whether the email is registered or not
+	assert.Equal(t, constants.ErrOTPResendLimit, u.ForgotPassword(ctx, "admin@admin.com", "10.0.0.1"))
+	assert.NoError(t, u.ForgotPassword(ctx, "unknown@admin.com", "10.0.0.1"))
+	assert.Equal(t, constants.ErrOTPResendLimit, u.ForgotPassword(ctx, "unknown@admin.com", "10.0.0.1"))
+}
+
+func TestVerifyOTPParallel(t *testing.T) {
+	ctx := context.Background()
+	repo := &stubAuthRepo{user: domain.User{ID: 7, Status: domain.UserActive, Email: "admin@admin.com"}}
+	mailer := &fakeMailer{}
+	u := newUsecase(repo, mailer)
+
+	assert.NoError(t, u.ForgotPassword(ctx, "admin@admin.com", "10.0.0.1"))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	notMatch := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := u.VerifyOTP(ctx, "admin@admin.com", "wrong")
+			mu.Lock()
+			defer mu.Unlock()
+			if err == constants.ErrOTPNotMatch {
+				notMatch++
+			}
+		}()
+	}
+	wg.Wait()
+
+	// parallel guesses do not get more than the allowed attempts
+	assert.LessOrEqual(t, notMatch, 2)
+	_, err := u.VerifyOTP(ctx, "admin@admin.com", mailer.data.OTP)
+	assert.Equal(t, constants.ErrGetOTPFromRedis, err)
+}
+
+func TestChangePassword(t *testing.T) {
+	ctx := context.Background()
+	hash, _ := utils.HashPassword("Old-Passw0rd")
+	repo := &stubAuthRepo{user: domain.User{ID: 7, Status: domain.UserActive, Email: "admin@admin.com", Password: hash}}
+	u := newUsecase(repo, &fakeMailer{})
+
+	_, err := u.ChangePassword(ctx, 7, &domain.RequestChangePassword{OldPassword: "wrong", Password: "N3w-Password"})
+	assert.Equal(t, constants.ErrOldPasswordNotMatch, err)
+
+	_, err = u.ChangePassword(ctx, 7, &domain.RequestChangePassword{OldPassword: "Old-Passw0rd", Password: "Old-Passw0rd"})
+	assert.Equal(t, constants.ErrPasswordAlreadyTaken, err)
+
+	user, err := u.ChangePassword(ctx, 7, &domain.RequestChangePassword{OldPassword: "Old-Passw0rd", Password: "N3w-Password"})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, user.ID)
+	assert.True(t, utils.CheckPasswordHash("N3w-Password", repo.user.Password))
+
+	revoked, err := u.IsRevoked(ctx, 7, "", utils.UnixMilli(time.Now().Add(-time.Minute)))
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestRevokeSameSecond(t *testing.T) {
+	ctx := context.Background()
+	repo := &stubAuthRepo{user: domain.User{ID: 7, Status: domain.UserActive, Email: "admin@admin.com"}}
+	u := newUsecase(repo, &fakeMailer{})
+
+	refresh, err := u.CreateRefreshToken(ctx, 7, time.Hour)
+	assert.NoError(t, err)
+	issuedAt := utils.UnixMilli(time.Now())
+	time.Sleep(2 * time.Millisecond)
+	assert.NoError(t, u.RevokeUser(ctx, 7))
+
+	// the tokens issued a moment before the revoke stop working, even within the same second
+	revoked, err := u.IsRevoked(ctx, 7, "", issuedAt)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	_, err = u.Refresh(ctx, refresh)
+	assert.Equal(t, constants.ErrRefreshTokenInvalid, err)
+
+	time.Sleep(2 * time.Millisecond)
+	revoked, err = u.IsRevoked(ctx, 7, "", utils.UnixMilli(time.Now()))
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	// a refresh token is rotated, it works once
+	refresh, err = u.CreateRefreshToken(ctx, 7, time.Hour)
+	assert.NoError(t, err)
+	_, err = u.Refresh(ctx, refresh)
+	assert.NoError(t, err)
+	_, err = u.Refresh(ctx, refresh)
+	assert.Equal(t, constants.ErrRefreshTokenInvalid, err)
+}
+
+func TestLoginLockout(t *testing.T) {
+	ctx := context.Background()
+	hash, _ := utils.HashPassword("Passw0rd!")
+	repo := &stubAuthRepo{user: domain.User{ID: 7, UserName: "admin", Email: "admin@admin.com", Password: hash, Status: domain.UserActive}}
+	u := newUsecase(repo, &fakeMailer{})
+	good := domain.UserLogin{Username: "admin", Email: "admin@admin.com", Password: "Passw0rd!"}
+
+	// a wrong password, username or email answer the same error
+	for _, m := range []domain.UserLogin{
+		{Username: "admin", Email: "admin@admin.com", Password: "wrong"},
+		{Username: "other", Email: "admin@admin.com", Password: "Passw0rd!"},
+		{Username: "admin", Email: "unknown@admin.com", Password: "Passw0rd!"},
+	} {
+		_, err := u.Login(ctx, m, "10.0.0.1")
+		assert.Equal(t, constants.ErrEmailAndPasswordNotMatch, err)
+	}
+
+	user, err := u.Login(ctx, good, "10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, user.ID)
+
+	// the free failures of the account are used up, the right password is refused during the lockout
+	for i := 0; i < 3; i++ {
+		_, err = u.Login(ctx, domain.UserLogin{Username: "admin", Email: "admin@admin.com", Password: "wrong"}, "10.0.0.2")
+		assert.Equal(t, constants.ErrEmailAndPasswordNotMatch, err)
+	}
+	_, err = u.Login(ctx, good, "10.0.0.3")
+	assert.Equal(t, constants.ErrLoginLocked, err)
+
+	assert.NoError(t, u.Unlock(ctx, "ADMIN@admin.com", ""))
+	_, err = u.Login(ctx, good, "10.0.0.3")
+	assert.NoError(t, err)
+}
+
+func TestPinLogin(t *testing.T) {
+	ctx := context.Background()
+	hash, _ := utils.HashPassword("Passw0rd!")
+	repo := &stubAuthRepo{user: domain.User{ID: 7, UserName: "cashier", Password: hash, Status: domain.UserActive}, outlets: []int64{2}}
+	terminals := &stubTerminalRepo{terminal: domain.Terminal{ID: 3, OutletID: 2, Status: domain.TerminalActive}, tokenHash: utils.HashToken("device")}
+	u := newTerminalUsecase(repo, terminals, &fakeMailer{})
+
+	assert.Equal(t, constants.ErrPasswordNotMatch, u.SetPin(ctx, 7, &domain.RequestSetPin{Password: "wrong", Pin: "1234"}))
+	assert.NoError(t, u.SetPin(ctx, 7, &domain.RequestSetPin{Password: "Passw0rd!", Pin: "1234"}))
+
+	_, _, err := u.PinLogin(ctx, domain.RequestPinLogin{TerminalToken: "other", UserName: "cashier", Pin: "1234"})
+	assert.Equal(t, constants.ErrTerminalInvalid, err)
+
+	user, terminal, err := u.PinLogin(ctx, domain.RequestPinLogin{TerminalToken: "device", UserName: "cashier", Pin: "1234"})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, user.ID)
+	assert.Equal(t, int64(2), terminal.OutletID)
+	assert.True(t, terminals.touched)
+
+	// a cashier of another outlet cannot switch in
+	repo.outlets = []int64{5}
+	_, _, err = u.PinLogin(ctx, domain.RequestPinLogin{TerminalToken: "device", UserName: "cashier", Pin: "1234"})
+	assert.Equal(t, constants.ErrOutletForbidden, err)
+	repo.outlets = []int64{2}
+
+	// the free PIN failures of the cashier are used up, the right PIN is refused during the lockout
+	for i := 0; i < 5; i++ {
+		_, _, err = u.PinLogin(ctx, domain.RequestPinLogin{TerminalToken: "device", UserName: "cashier", Pin: "0000"})
+		assert.Equal(t, constants.ErrPinNotMatch, err)
+	}
+	_, _, err = u.PinLogin(ctx, domain.RequestPinLogin{TerminalToken: "device", UserName: "cashier", Pin: "1234"})
+	assert.Equal(t, constants.ErrLoginLocked, err)
+
+	terminals.terminal.Status = domain.TerminalRevoked
+	_, _, err = u.PinLogin(ctx, domain.RequestPinLogin{TerminalToken: "device", UserName: "cashier", Pin: "1234"})
+	assert.Equal(t, constants.ErrTerminalInvalid, err)
+}
+
+func TestTOTPCode(t *testing.T) {
+	// the SHA1 vectors of RFC 6238 appendix B, the secret is "12345678901234567890" in base32
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	for unix, code := range map[int64]string{59: "287082", 1111111109: "081804", 1234567890: "005924", 2000000000: "279037"} {
+		res, err := utils.TOTPCode(secret, unix/utils.TOTPPeriod)
+		assert.NoError(t, err)
+		assert.Equal(t, code, res)
+	}
+
+	_, ok := utils.ValidateTOTP(secret, "287082", time.Unix(89, 0))
+	assert.True(t, ok)
+	_, ok = utils.ValidateTOTP(secret, "287082", time.Unix(150, 0))
+	assert.False(t, ok)
+}
+
+func TestTwoFactorLogin(t *testing.T) {
+	ctx := context.Background()
+	hash, _ := utils.HashPassword("Passw0rd!")
+	repo := &stubAuthRepo{user: domain.User{ID: 7, RoleID: domain.RoleSuperAdmin, Email: "admin@admin.com", Password: hash, Status: domain.UserActive}}
+	cfg := config.Config{Server: config.ServerConfig{AdminTwoFactor: true}}
+	u := newConfigUsecase(repo, &stubTerminalRepo{}, &fakeMailer{}, cfg)
+
+	// a super admin forced to use 2FA enrolls before the first session
+	challenge, err := u.StartTwoFactor(ctx, repo.user)
+	assert.NoError(t, err)
+	assert.True(t, challenge.TwoFactorRequired)
+	assert.True(t, challenge.SetupRequired)
+
+	setup, err := u.SetupTwoFactorChallenge(ctx, challenge.ChallengeToken)
+	assert.NoError(t, err)
+	assert.Contains(t, setup.URI, "secret="+setup.Secret)
+
+	_, _, err = u.VerifyTwoFactor(ctx, domain.RequestTwoFactorLogin{ChallengeToken: challenge.ChallengeToken, Code: "000000"})
+	assert.Equal(t, constants.ErrTwoFactorCodeNotMatch, err)
+
+	code, _ := utils.TOTPCode(setup.Secret, utils.TOTPStep(time.Now()))
+	user, codes, err := u.VerifyTwoFactor(ctx, domain.RequestTwoFactorLogin{ChallengeToken: challenge.ChallengeToken, Code: code})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, user.ID)
+	assert.Len(t, codes, 10)
+	assert.Equal(t, setup.Secret, repo.user.TOTPSecret)
+
+	// the challenge is used up, and so is the code
+	_, _, err = u.VerifyTwoFactor(ctx, domain.RequestTwoFactorLogin{ChallengeToken: challenge.ChallengeToken, Code: code})
+	assert.Equal(t, constants.ErrTwoFactorChallenge, err)
+
+	challenge, err = u.StartTwoFactor(ctx, repo.user)
+	assert.NoError(t, err)
+	assert.False(t, challenge.SetupRequired)
+	_, _, err = u.VerifyTwoFactor(ctx, domain.RequestTwoFactorLogin{ChallengeToken: challenge.ChallengeToken, Code: code})
+	assert.Equal(t, constants.ErrTwoFactorCodeNotMatch, err)
+
+	// a recovery code stands in for the TOTP code once
+	_, _, err = u.VerifyTwoFactor(ctx, domain.RequestTwoFactorLogin{ChallengeToken: challenge.ChallengeToken, RecoveryCode: strings.ToUpper(codes[0])})
+	assert.NoError(t, err)
+	challenge, _ = u.StartTwoFactor(ctx, repo.user)
+	_, _, err = u.VerifyTwoFactor(ctx, domain.RequestTwoFactorLogin{ChallengeToken: challenge.ChallengeToken, RecoveryCode: codes[0]})
+	assert.Equal(t, constants.ErrTwoFactorCodeNotMatch, err)
+
+	err = u.DisableTwoFactor(ctx, 7, &domain.RequestDisableTwoFactor{Password: "Passw0rd!", Code: codes[1]})
+	assert.Equal(t, constants.ErrTwoFactorRequired, err)
+}
+
+func TestImpersonate(t *testing.T) {
+	ctx := context.Background()
+	repo := &stubAuthRepo{user: domain.User{ID: 7, RoleID: 2, Status: domain.UserActive}}
+	audit := &stubAuditRepo{}
+	cfg := config.Config{Impersonation: config.ImpersonationConfig{Password: "support", User: true}}
+	store := _cache.NewMemoryCache()
+	u := usecase.NewAuthUseCase(repo, cache.NewCacheSessionRepository(store), cache.NewCacheOTPRepository(store),
+		cache.NewCacheLoginAttemptRepository(store), cache.NewCacheTwoFactorRepository(store), &stubTerminalRepo{}, audit,
+		&fakeMailer{}, cfg, time.Second)
+
+	req := domain.RequestImpersonate{UserID: 7, Password: "support", Reason: "ticket 42"}
+	user, err := u.Impersonate(ctx, 1, req, "10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, user.ID)
+	assert.Equal(t, domain.AuditLog{ActorID: 1, UserID: 7, Action: domain.AuditImpersonate, IP: "10.0.0.1", Detail: "ticket 42"}, audit.logs[0])
+
+	// a wrong password, a super admin while Admin is off and oneself are refused, and audited
+	req.Password = "guess"
+	_, err = u.Impersonate(ctx, 1, req, "10.0.0.1")
+	assert.Equal(t, constants.ErrImpersonationPassword, err)
+	req.Password = "support"
+	repo.user.RoleID = domain.RoleSuperAdmin
+	_, err = u.Impersonate(ctx, 1, req, "10.0.0.1")
+	assert.Equal(t, constants.ErrImpersonationForbidden, err)
+	_, err = u.Impersonate(ctx, 7, req, "10.0.0.1")
+	assert.Equal(t, constants.ErrImpersonationForbidden, err)
+	assert.Len(t, audit.logs, 4)
+	assert.Equal(t, domain.AuditImpersonateDenied, audit.logs[3].Action)
+
+	// an unknown user leaves nothing to audit
+	req.UserID = 8
+	_, err = u.Impersonate(ctx, 1, req, "10.0.0.1")
+	assert.Equal(t, domain.ErrNotFound, err)
+	assert.Len(t, audit.logs, 4)
+
+	// without a password in the config nobody is impersonated
+	u = newConfigUsecase(repo, &stubTerminalRepo{}, &fakeMailer{}, config.Config{Impersonation: config.ImpersonationConfig{User: true}})
+	_, err = u.Impersonate(ctx, 1, domain.RequestImpersonate{UserID: 7, Password: "", Reason: "ticket 42"}, "10.0.0.1")
+	assert.Equal(t, constants.ErrImpersonationDisabled, err)
+}
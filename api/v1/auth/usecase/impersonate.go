@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/constants"
+	"mini_pos/domain"
+)
+
+// Impersonate return the user a support staff asked to act as, when the config allows to impersonate
+// this kind of user. Every attempt on an existing user is kept in the audit log, and no token may be
+// issued when the audit log cannot be written
+func (a *authUsecase) Impersonate(c context.Context, actorId int, m domain.RequestImpersonate, ip string) (res domain.User, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err = a.impersonate(ctx, actorId, m)
+	log := &domain.AuditLog{
+		ActorID: actorId,
+		UserID:  m.UserID,
+		Action:  domain.AuditImpersonate,
+		IP:      ip,
+		Detail:  m.Reason,
+	}
+	switch err {
+	case nil:
+	case constants.ErrImpersonationDisabled, constants.ErrImpersonationPassword, constants.ErrImpersonationForbidden,
+		constants.ErrUserInactive, constants.ErrUserPending:
+		log.Action = domain.AuditImpersonateDenied
+		log.Detail = err.Error()
+	default:
+		return domain.User{}, err
+	}
+
+	logrus.WithFields(logrus.Fields{"actor_id": log.ActorID, "user_id": log.UserID, "ip": log.IP}).Warn(log.Action)
+	errAudit := a.auditRepo.Store(ctx, log)
+	if errAudit != nil {
+		logrus.Error(errAudit)
+		if err == nil {
+			return domain.User{}, errAudit
+		}
+	}
+	return
+}
+
+func (a *authUsecase) impersonate(ctx context.Context, actorId int, m domain.RequestImpersonate) (res domain.User, err error) {
+	cfg := a.cfg.Impersonation
+	if cfg.Password == "" || (!cfg.Admin && !cfg.User) {
+		return domain.User{}, constants.ErrImpersonationDisabled
+	}
+	if subtle.ConstantTimeCompare([]byte(m.Password), []byte(cfg.Password)) != 1 {
+		return domain.User{}, constants.ErrImpersonationPassword
+	}
+
+	res, err = a.authRepo.GetUserByID(ctx, m.UserID)
+	if err != nil {
+		return domain.User{}, domain.ErrNotFound
+	}
+	if res.ID == actorId {
+		return domain.User{}, constants.ErrImpersonationForbidden
+	}
+	if res.RoleID == domain.RoleSuperAdmin && !cfg.Admin || res.RoleID != domain.RoleSuperAdmin && !cfg.User {
+		return domain.User{}, constants.ErrImpersonationForbidden
+	}
+	if res.Status == domain.UserPending {
+		return domain.User{}, constants.ErrUserPending
+	}
+	if res.Status != domain.UserActive {
+		return domain.User{}, constants.ErrUserInactive
+	}
+	return
+}
@@ -0,0 +1,313 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini_pos/constants"
+	"mini_pos/domain"
+	"mini_pos/utils"
+)
+
+// The TOTP codes of a user share one lockout, counted apart from the password logins.
+// A code stays refused for stepLifeTime once used, longer than the steps ValidateTOTP accepts
+const (
+	totpIssuer            = "Mini POS"
+	challengeLifeTime     = 5 * time.Minute
+	setupLifeTime         = 10 * time.Minute
+	stepLifeTime          = 3 * utils.TOTPPeriod * time.Second
+	twoFactorFreeFailures = 5
+	recoveryCodeCount     = 10
+)
+
+func twoFactorAttemptKey(userId int) string {
+	return "2fa:user:" + strconv.Itoa(userId)
+}
+
+// requiresTwoFactor tell whether a login of the user waits for a TOTP code, either the user has enrolled
+// or the user is a super admin and the config forces 2FA on them
+func (a *authUsecase) requiresTwoFactor(user domain.User) bool {
+	return user.TOTPSecret != "" || (a.cfg.Server.AdminTwoFactor && user.RoleID == domain.RoleSuperAdmin)
+}
+
+// StartTwoFactor open the second login step of a user whose password is right,
+// the challenge answered is the zero TwoFactorChallenge when the user does not need 2FA
+func (a *authUsecase) StartTwoFactor(c context.Context, user domain.User) (res domain.TwoFactorChallenge, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if !a.requiresTwoFactor(user) {
+		return
+	}
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		return
+	}
+	expiresAt := time.Now().Add(challengeLifeTime)
+	err = a.twoFactorRepo.StoreChallenge(ctx, token, domain.TwoFactorPending{UserID: user.ID, ExpiresAt: expiresAt})
+	if err != nil {
+		return
+	}
+	return domain.TwoFactorChallenge{
+		TwoFactorRequired: true,
+		SetupRequired:     user.TOTPSecret == "",
+		ChallengeToken:    token,
+		ExpiresAt:         expiresAt.Unix(),
+	}, nil
+}
+
+// challengeUser load the user a login challenge was opened for
+func (a *authUsecase) challengeUser(ctx context.Context, token string) (res domain.User, err error) {
+	pending, err := a.twoFactorRepo.GetChallenge(ctx, token)
+	if err == domain.ErrNotFound {
+		return domain.User{}, constants.ErrTwoFactorChallenge
+	}
+	if err != nil {
+		return
+	}
+	res, err = a.authRepo.GetUserByID(ctx, pending.UserID)
+	if err != nil {
+		return domain.User{}, constants.ErrTwoFactorChallenge
+	}
+	return
+}
+
+// VerifyTwoFactor finish a login with the TOTP code or a recovery code of the user. A user who must use 2FA
+// and has not enrolled yet confirms the setup started with the challenge, the recovery codes are then answered once
+func (a *authUsecase) VerifyTwoFactor(c context.Context, m domain.RequestTwoFactorLogin) (res domain.User, codes []string, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err = a.challengeUser(ctx, m.ChallengeToken)
+	if err != nil {
+		return
+	}
+
+	if res.TOTPSecret == "" {
+		codes, err = a.confirm(ctx, res.ID, m.Code)
+	} else {
+		code := m.Code
+		if code == "" {
+			code = m.RecoveryCode
+		}
+		err = a.guardCode(ctx, res.ID, func() (bool, error) {
+			return a.checkCode(ctx, res, code)
+		})
+	}
+	if err != nil {
+		return domain.User{}, nil, err
+	}
+
+	err = a.twoFactorRepo.DeleteChallenge(ctx, m.ChallengeToken)
+	if err != nil {
+		return
+	}
+	if res.Status != domain.UserActive {
+		return domain.User{}, nil, constants.ErrUserInactive
+	}
+	return
+}
+
+// SetupTwoFactorChallenge start the enrollment of a user who cannot login before enrolling
+func (a *authUsecase) SetupTwoFactorChallenge(c context.Context, challengeToken string) (res domain.TwoFactorSetup, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	user, err := a.challengeUser(ctx, challengeToken)
+	if err != nil {
+		return
+	}
+	return a.setup(ctx, user)
+}
+
+// SetupTwoFactor start the enrollment of the token holder, 2FA is on once the first code is confirmed
+func (a *authUsecase) SetupTwoFactor(c context.Context, userId int) (res domain.TwoFactorSetup, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	user, err := a.authRepo.GetUserByID(ctx, userId)
+	if err != nil {
+		return domain.TwoFactorSetup{}, domain.ErrNotFound
+	}
+	return a.setup(ctx, user)
+}
+
+func (a *authUsecase) setup(ctx context.Context, user domain.User) (res domain.TwoFactorSetup, err error) {
+	if user.TOTPSecret != "" {
+		return domain.TwoFactorSetup{}, constants.ErrTwoFactorEnabled
+	}
+	secret, err := utils.NewTOTPSecret()
+	if err != nil {
+		return
+	}
+	err = a.twoFactorRepo.StoreSetup(ctx, user.ID, secret, setupLifeTime)
+	if err != nil {
+		return
+	}
+	return domain.TwoFactorSetup{
+		Secret: secret,
+		URI:    utils.TOTPURI(totpIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTwoFactor turn 2FA on with the first code of the authenticator app and answer the recovery codes
+func (a *authUsecase) ConfirmTwoFactor(c context.Context, userId int, code string) (res []string, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.confirm(ctx, userId, code)
+}
+
+func (a *authUsecase) confirm(ctx context.Context, userId int, code string) (res []string, err error) {
+	secret, err := a.twoFactorRepo.GetSetup(ctx, userId)
+	if err == domain.ErrNotFound {
+		return nil, constants.ErrTwoFactorSetupMissing
+	}
+	if err != nil {
+		return
+	}
+
+	err = a.guardCode(ctx, userId, func() (bool, error) {
+		return a.checkTOTP(ctx, userId, secret, code)
+	})
+	if err != nil {
+		return
+	}
+
+	err = a.authRepo.SetTOTPSecret(ctx, userId, secret)
+	if err != nil {
+		return
+	}
+	res, err = a.newRecoveryCodes(ctx, userId)
+	if err != nil {
+		return
+	}
+	err = a.twoFactorRepo.DeleteSetup(ctx, userId)
+	return
+}
+
+// DisableTwoFactor turn 2FA off after checking the password and a code, a super admin forced to use 2FA cannot
+func (a *authUsecase) DisableTwoFactor(c context.Context, userId int, m *domain.RequestDisableTwoFactor) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	user, err := a.authRepo.GetUserByID(ctx, userId)
+	if err != nil {
+		return domain.ErrNotFound
+	}
+	if user.TOTPSecret == "" {
+		return constants.ErrTwoFactorNotEnabled
+	}
+	if a.cfg.Server.AdminTwoFactor && user.RoleID == domain.RoleSuperAdmin {
+		return constants.ErrTwoFactorRequired
+	}
+	if !utils.CheckPasswordHash(m.Password, user.Password) {
+		return constants.ErrPasswordNotMatch
+	}
+	err = a.guardCode(ctx, userId, func() (bool, error) {
+		return a.checkCode(ctx, user, m.Code)
+	})
+	if err != nil {
+		return
+	}
+
+	err = a.authRepo.SetTOTPSecret(ctx, userId, "")
+	if err != nil {
+		return
+	}
+	return a.authRepo.ReplaceRecoveryCodes(ctx, userId, nil)
+}
+
+// RegenerateRecoveryCodes replace every recovery code of the token holder, the old ones stop working
+func (a *authUsecase) RegenerateRecoveryCodes(c context.Context, userId int, code string) (res []string, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	user, err := a.authRepo.GetUserByID(ctx, userId)
+	if err != nil {
+		return nil, domain.ErrNotFound
+	}
+	if user.TOTPSecret == "" {
+		return nil, constants.ErrTwoFactorNotEnabled
+	}
+	err = a.guardCode(ctx, userId, func() (bool, error) {
+		return a.checkTOTP(ctx, userId, user.TOTPSecret, code)
+	})
+	if err != nil {
+		return
+	}
+	return a.newRecoveryCodes(ctx, userId)
+}
+
+// guardCode run the check of a code under the 2FA lockout of the user
+func (a *authUsecase) guardCode(ctx context.Context, userId int, check func() (bool, error)) error {
+	key := twoFactorAttemptKey(userId)
+	locked, err := a.isLocked(ctx, key)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return constants.ErrLoginLocked
+	}
+
+	ok, err := check()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		err = a.recordFailure(ctx, key, twoFactorFreeFailures)
+		if err != nil {
+			return err
+		}
+		return constants.ErrTwoFactorCodeNotMatch
+	}
+	return a.attemptRepo.DeleteAttempt(ctx, key)
+}
+
+// checkTOTP check a TOTP code, a code is accepted once only
+func (a *authUsecase) checkTOTP(ctx context.Context, userId int, secret string, code string) (bool, error) {
+	step, ok := utils.ValidateTOTP(secret, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+	return a.twoFactorRepo.UseStep(ctx, userId, step, stepLifeTime)
+}
+
+// checkCode check a TOTP code, or a recovery code when it is not one
+func (a *authUsecase) checkCode(ctx context.Context, user domain.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return false, nil
+	}
+	if len(code) == utils.TOTPDigits {
+		return a.checkTOTP(ctx, user.ID, user.TOTPSecret, code)
+	}
+	return a.authRepo.UseRecoveryCode(ctx, user.ID, utils.HashToken(normalizeRecoveryCode(code)))
+}
+
+// newRecoveryCodes replace the recovery codes of the user, only their hashes are kept
+func (a *authUsecase) newRecoveryCodes(ctx context.Context, userId int) (res []string, err error) {
+	res = make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range res {
+		token, errToken := utils.RandomToken(5)
+		if errToken != nil {
+			return nil, errToken
+		}
+		res[i] = token[:5] + "-" + token[5:]
+		hashes[i] = utils.HashToken(normalizeRecoveryCode(res[i]))
+	}
+	err = a.authRepo.ReplaceRecoveryCodes(ctx, userId, hashes)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// normalizeRecoveryCode drop the dash and the case a recovery code may be typed with
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,266 @@
+package http
+
+import (
+	"mini_pos/api/common"
+	middlwr "mini_pos/api/middleware"
+	"mini_pos/config"
+	"mini_pos/constants"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"mini_pos/domain"
+)
+
+// BarcodeHandler  represent the httphandler for Barcode
+type BarcodeHandler struct {
+	AUsecase domain.BarcodeUsecase
+}
+
+// NewBarcodeHandler will initialize the barcodes/ resources endpoint
+func NewBarcodeHandler(e *echo.Echo, us domain.BarcodeUsecase, cfg config.Config) {
+	handler := &BarcodeHandler{
+		AUsecase: us,
+	}
+	BarcodeV1 := e.Group("")
+
+	BarcodeV1.Use(middlwr.JWTMiddleware(cfg))
+	BarcodeV1.GET("/barcodes/scan", handler.Scan, middlwr.Permission(domain.PermSell, domain.PermProductOutletRead))
+	BarcodeV1.DELETE("/barcodes/:id", handler.Delete, middlwr.Permission(domain.PermProductOutletWrite))
+	BarcodeV1.GET("/product_outlets/:id/barcodes", handler.FetchBarcode, middlwr.Permission(domain.PermProductOutletRead))
+	BarcodeV1.POST("/product_outlets/:id/barcodes", handler.Store, middlwr.Permission(domain.PermProductOutletWrite))
+	BarcodeV1.POST("/product_outlets/:id/barcodes/generate", handler.Generate, middlwr.Permission(domain.PermProductOutletWrite))
+}
+
+func isRequestValid(m *domain.Barcode) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(m)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Scan will resolve the code read at the till to the outlet product of outlet, with its price and stock
+func (a *BarcodeHandler) Scan(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	outlet, err := strconv.Atoi(c.QueryParam("outlet"))
+	if err != nil || outlet == 0 {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: constants.ErrParamsIsNotInvalid.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	ctx := c.Request().Context()
+	scan, err := a.AUsecase.Scan(ctx, c.QueryParam("code"), int64(outlet))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(scan))
+}
+
+// FetchBarcode will fetch the barcodes of the outlet product, outlet may be left empty by a user allowed on all the outlets
+func (a *BarcodeHandler) FetchBarcode(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+	outlet, _ := strconv.Atoi(c.QueryParam("outlet"))
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	ctx := c.Request().Context()
+	listAr, err := a.AUsecase.Fetch(ctx, int64(idP), int64(outlet))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(listAr))
+}
+
+// Store will add a barcode to the outlet product by given request body
+func (a *BarcodeHandler) Store(c echo.Context) (err error) {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+	outlet, _ := strconv.Atoi(c.QueryParam("outlet"))
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	var barcode domain.Barcode
+	err = c.Bind(&barcode)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&barcode); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	barcode.OutletProductID = int64(idP)
+	err = a.AUsecase.Store(ctx, &barcode, int64(outlet))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(barcode))
+}
+
+// Generate will give the outlet product an in-store EAN-13 for goods sold without a label
+func (a *BarcodeHandler) Generate(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+	outlet, _ := strconv.Atoi(c.QueryParam("outlet"))
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	ctx := c.Request().Context()
+	barcode, err := a.AUsecase.Generate(ctx, int64(idP), int64(outlet))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(barcode))
+}
+
+// Delete will remove the barcode by given param
+func (a *BarcodeHandler) Delete(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+	outlet, _ := strconv.Atoi(c.QueryParam("outlet"))
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	ctx := c.Request().Context()
+	err = a.AUsecase.Delete(ctx, int64(idP), int64(outlet))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponseWithoutData())
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound, constants.ErrDataNotFound:
+		return http.StatusNotFound
+	case domain.ErrConflict:
+		return http.StatusConflict
+	case domain.ErrBadParamInput:
+		return http.StatusBadRequest
+	case constants.ErrBarcodeInvalid:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
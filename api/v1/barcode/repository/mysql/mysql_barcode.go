@@ -0,0 +1,151 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/domain"
+)
+
+type mysqlBarcodeRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlBarcodeRepository will create an object that represent the domain.BarcodeRepository interface
+func NewMysqlBarcodeRepository(Conn *sql.DB) domain.BarcodeRepository {
+	return &mysqlBarcodeRepository{Conn}
+}
+
+func (m *mysqlBarcodeRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Barcode, err error) {
+	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Barcode, 0)
+	for rows.Next() {
+		t := domain.Barcode{}
+		err = rows.Scan(
+			&t.ID,
+			&t.OutletProductID,
+			&t.OutletID,
+			&t.Code,
+			&t.Type,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+func (m *mysqlBarcodeRepository) getOne(ctx context.Context, query string, args ...interface{}) (res domain.Barcode, err error) {
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return domain.Barcode{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+
+	return
+}
+
+func (m *mysqlBarcodeRepository) FetchByOutletProduct(ctx context.Context, outletProductId int64) ([]domain.Barcode, error) {
+	query := `SELECT id, outlet_product_id, outlet_id, code, type, update_at, create_at
+  						FROM barcodes WHERE outlet_product_id = ? ORDER BY id`
+	return m.fetch(ctx, query, outletProductId)
+}
+
+func (m *mysqlBarcodeRepository) GetByID(ctx context.Context, id int64) (domain.Barcode, error) {
+	query := `SELECT id, outlet_product_id, outlet_id, code, type, update_at, create_at
+  						FROM barcodes WHERE id = ?`
+	return m.getOne(ctx, query, id)
+}
+
+// GetByCode return the barcode of the outlet matching one of the codes, the first code is preferred
+func (m *mysqlBarcodeRepository) GetByCode(ctx context.Context, outletId int64, codes []string) (domain.Barcode, error) {
+	if len(codes) == 0 {
+		return domain.Barcode{}, domain.ErrNotFound
+	}
+	query := `SELECT id, outlet_product_id, outlet_id, code, type, update_at, create_at
+  						FROM barcodes WHERE outlet_id = ? and code IN (?` + strings.Repeat(",?", len(codes)-1) + `)
+  						ORDER BY code = ? DESC LIMIT 1`
+	args := []interface{}{outletId}
+	for _, c := range codes {
+		args = append(args, c)
+	}
+	args = append(args, codes[0])
+	return m.getOne(ctx, query, args...)
+}
+
+func (m *mysqlBarcodeRepository) Store(ctx context.Context, a *domain.Barcode) (err error) {
+	query := `INSERT  barcodes
+			  SET outlet_product_id=?, outlet_id=?, code=?, type=?, update_at=? , create_at=?`
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, a.OutletProductID, a.OutletID, a.Code, a.Type, now, now)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	a.UpdatedAt = now
+	a.CreatedAt = now
+	return
+}
+
+func (m *mysqlBarcodeRepository) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM barcodes WHERE id = ? "
+
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAfected != 1 {
+		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", rowsAfected)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"mini_pos/constants"
+	"mini_pos/domain"
+)
+
+type BarcodeUsecase struct {
+	BarcodeRepo        domain.BarcodeRepository
+	ProductOutletsRepo domain.ProductOutletsRepository
+	ProductRepo        domain.ProductRepository
+	contextTimeout     time.Duration
+}
+
+// NewBarcodeUsecase will create new an BarcodeUsecase object representation of domain.BarcodeUsecase interface
+func NewBarcodeUsecase(a domain.BarcodeRepository, productOutlets domain.ProductOutletsRepository, product domain.ProductRepository, timeout time.Duration) domain.BarcodeUsecase {
+	return &BarcodeUsecase{
+		BarcodeRepo:        a,
+		ProductOutletsRepo: productOutlets,
+		ProductRepo:        product,
+		contextTimeout:     timeout,
+	}
+}
+
+// Fetch return the barcodes of the outlet product, which must be in the outlet unless outlet is 0
+func (a *BarcodeUsecase) Fetch(c context.Context, outletProductId int64, outletId int64) (res []domain.Barcode, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	_, err = a.ProductOutletsRepo.GetByID(ctx, outletProductId, outletId)
+	if err != nil {
+		return
+	}
+	res, err = a.BarcodeRepo.FetchByOutletProduct(ctx, outletProductId)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) <= 0 {
+		return nil, constants.ErrDataNotFound
+	}
+	return
+}
+
+// Store add a barcode to the outlet product, its type is guessed from the code when empty and its check digit
+// must be right. A code already used in the outlet, even as the EAN-13 form of an UPC-A, is refused
+func (a *BarcodeUsecase) Store(c context.Context, m *domain.Barcode, outletId int64) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	item, err := a.ProductOutletsRepo.GetByID(ctx, m.OutletProductID, outletId)
+	if err != nil {
+		return
+	}
+	m.Code = strings.TrimSpace(m.Code)
+	m.DetectType()
+	if !m.Valid() {
+		return constants.ErrBarcodeInvalid
+	}
+	m.OutletID = item.Outlet.ID
+	_, err = a.BarcodeRepo.GetByCode(ctx, m.OutletID, m.Aliases())
+	if err == nil {
+		return domain.ErrConflict
+	}
+	if err != domain.ErrNotFound {
+		return
+	}
+	return a.BarcodeRepo.Store(ctx, m)
+}
+
+// Generate give the outlet product its in-store EAN-13 for goods sold without a label, the same code is answered
+// when it was already generated
+func (a *BarcodeUsecase) Generate(c context.Context, outletProductId int64, outletId int64) (res domain.Barcode, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	item, err := a.ProductOutletsRepo.GetByID(ctx, outletProductId, outletId)
+	if err != nil {
+		return
+	}
+	res = domain.Barcode{
+		OutletProductID: item.ID,
+		OutletID:        item.Outlet.ID,
+		Code:            domain.InternalEAN13(item.ID),
+		Type:            domain.BarcodeEAN13,
+	}
+	if !res.Valid() {
+		return domain.Barcode{}, constants.ErrBarcodeInvalid
+	}
+	existed, err := a.BarcodeRepo.GetByCode(ctx, res.OutletID, res.Aliases())
+	if err == nil {
+		if existed.OutletProductID != item.ID {
+			return domain.Barcode{}, domain.ErrConflict
+		}
+		return existed, nil
+	}
+	if err != domain.ErrNotFound {
+		return domain.Barcode{}, err
+	}
+	err = a.BarcodeRepo.Store(ctx, &res)
+	return
+}
+
+// Delete remove the barcode, which must be in the outlet unless outlet is 0
+func (a *BarcodeUsecase) Delete(c context.Context, id int64, outletId int64) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	existed, err := a.BarcodeRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	if outletId != 0 && existed.OutletID != outletId {
+		return domain.ErrNotFound
+	}
+	return a.BarcodeRepo.Delete(ctx, id)
+}
+
+// Scan resolve a code read at the till to the outlet product of the outlet with its price and stock.
+// The barcodes are looked up first, then the SKU
+func (a *BarcodeUsecase) Scan(c context.Context, code string, outletId int64) (res domain.BarcodeScan, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return domain.BarcodeScan{}, domain.ErrBadParamInput
+	}
+	barcode, err := a.BarcodeRepo.GetByCode(ctx, outletId, domain.Barcode{Code: code}.Aliases())
+	switch err {
+	case nil:
+		res.Barcode = barcode
+		res.Item, err = a.ProductOutletsRepo.GetByID(ctx, barcode.OutletProductID, outletId)
+	case domain.ErrNotFound:
+		res.Item, err = a.ProductOutletsRepo.GetBySku(ctx, code)
+		if err == nil && res.Item.Outlet.ID != outletId {
+			err = domain.ErrNotFound
+		}
+	}
+	if err != nil {
+		return domain.BarcodeScan{}, err
+	}
+
+	product, err := a.ProductRepo.GetByID(ctx, res.Item.Product.ID)
+	if err != nil {
+		return domain.BarcodeScan{}, err
+	}
+	res.Item.Product = product
+	return
+}
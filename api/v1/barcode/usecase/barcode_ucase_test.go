@@ -0,0 +1,53 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"mini_pos/api/v1/barcode/usecase"
+	"mini_pos/constants"
+	"mini_pos/domain"
+	"mini_pos/domain/fakes"
+)
+
+func TestBarcodeScan(t *testing.T) {
+	ctx := context.Background()
+	// the outlet product 7 sells the product 3 in the outlet 1 under the SKU TSHIRT-M
+	items := &fakes.ProductOutletsRepository{Items: []domain.ProductOutlets{
+		{ID: 7, Sku: "TSHIRT-M", Price: 85000, Product: domain.Product{ID: 3}, Outlet: domain.Outlet{ID: 1}},
+	}}
+	products := &fakes.ProductRepository{Products: []domain.Product{{ID: 3, Name: "T-Shirt"}}}
+	u := usecase.NewBarcodeUsecase(&fakes.BarcodeRepository{}, items, products, time.Second)
+
+	assert.Equal(t, constants.ErrBarcodeInvalid, u.Store(ctx, &domain.Barcode{OutletProductID: 7, Code: "036000291453"}, 1))
+	upc := domain.Barcode{OutletProductID: 7, Code: "036000291452"}
+	assert.NoError(t, u.Store(ctx, &upc, 1))
+	assert.Equal(t, domain.BarcodeUPCA, upc.Type)
+	// the EAN-13 form of the UPC-A is the same goods
+	assert.Equal(t, domain.ErrConflict, u.Store(ctx, &domain.Barcode{OutletProductID: 7, Code: "0036000291452"}, 1))
+
+	internal, err := u.Generate(ctx, 7, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "2000000000077", internal.Code)
+	again, err := u.Generate(ctx, 7, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, internal.ID, again.ID)
+
+	scan, err := u.Scan(ctx, "0036000291452", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, upc.ID, scan.Barcode.ID)
+	assert.Equal(t, "T-Shirt", scan.Item.Product.Name)
+	assert.Equal(t, 85000.0, scan.Item.Price)
+
+	// the SKU is read when no barcode matches, in the outlet of the caller only
+	scan, err = u.Scan(ctx, "TSHIRT-M", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), scan.Item.ID)
+	_, err = u.Scan(ctx, "TSHIRT-M", 2)
+	assert.Equal(t, domain.ErrNotFound, err)
+	_, err = u.Scan(ctx, "036000291452", 2)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
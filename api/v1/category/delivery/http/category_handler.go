@@ -0,0 +1,354 @@
+package http
+
+import (
+	"mini_pos/api/common"
+	middlwr "mini_pos/api/middleware"
+	"mini_pos/config"
+	"mini_pos/constants"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"mini_pos/domain"
+)
+
+// CategoryHandler  represent the httphandler for Category
+type CategoryHandler struct {
+	AUsecase domain.CategoryUsecase
+}
+
+// NewCategoryHandler will initialize the categories/ resources endpoint
+func NewCategoryHandler(e *echo.Echo, us domain.CategoryUsecase, cfg config.Config) {
+	handler := &CategoryHandler{
+		AUsecase: us,
+	}
+	CategoryV1 := e.Group("")
+
+	CategoryV1.Use(middlwr.JWTMiddleware(cfg))
+	CategoryV1.GET("/categories", handler.FetchCategory, middlwr.Permission(domain.PermProductRead))
+	CategoryV1.POST("/categories", handler.Store, middlwr.Permission(domain.PermProductWrite))
+	CategoryV1.GET("/categories/:id", handler.GetByID, middlwr.Permission(domain.PermProductRead))
+	CategoryV1.GET("/categories/:id/products", handler.FetchProduct, middlwr.Permission(domain.PermProductRead))
+	CategoryV1.PUT("/categories/:id", handler.Update, middlwr.Permission(domain.PermProductWrite))
+	CategoryV1.DELETE("/categories/:id", handler.Delete, middlwr.Permission(domain.PermProductWrite))
+}
+
+func isRequestValid(m *domain.Category) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(m)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FetchCategory will fetch the global categories with the categories of outlet, or every category when outlet is empty.
+// parent restricts the list to the subcategories of a category, parent 0 lists the top level categories
+func (a *CategoryHandler) FetchCategory(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	outlet, _ := strconv.Atoi(c.QueryParam("outlet"))
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
+	}
+	parent := int64(domain.CategoryAnyParent)
+	if c.QueryParam("parent") != "" {
+		parentP, err := strconv.Atoi(c.QueryParam("parent"))
+		if err != nil {
+			return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+				Code:    http.StatusBadRequest,
+				Message: domain.ErrBadParamInput.Error(),
+				Data:    nil,
+			}))
+		}
+		parent = int64(parentP)
+	}
+	cursor := c.QueryParam("cursor")
+	ctx := c.Request().Context()
+
+	listAr, nextCursor, err := a.AUsecase.Fetch(ctx, cursor, int64(num), int64(outlet), parent)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(common.NewSuccessResponse(listAr))
+}
+
+// GetByID will get the category by given id
+func (a *CategoryHandler) GetByID(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	category, err := a.AUsecase.GetByID(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	if category.OutletID != 0 && !claims.HasOutlet(category.OutletID) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	return c.JSON(common.NewSuccessResponse(category))
+}
+
+// FetchProduct will fetch the products of the category and of its subcategories, the subcategories of
+// other outlets than outlet are left out
+func (a *CategoryHandler) FetchProduct(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	outlet, _ := strconv.Atoi(c.QueryParam("outlet"))
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
+	}
+	cursor := c.QueryParam("cursor")
+
+	ctx := c.Request().Context()
+	category, err := a.AUsecase.GetByID(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	if category.OutletID != 0 && !claims.HasOutlet(category.OutletID) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	listAr, nextCursor, err := a.AUsecase.FetchProducts(ctx, category.ID, int64(outlet), cursor, int64(num))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(common.NewSuccessResponse(listAr))
+}
+
+// Store will store the category by given request body, a global category is added with the outlet 0
+func (a *CategoryHandler) Store(c echo.Context) (err error) {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	var category domain.Category
+	err = c.Bind(&category)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&category); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(category.OutletID) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	ctx := c.Request().Context()
+	err = a.AUsecase.Store(ctx, &category)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(category))
+}
+
+// Update will rename or move the category by given request body, the outlet of a category is kept
+func (a *CategoryHandler) Update(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	existed, err := a.AUsecase.GetByID(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(existed.OutletID) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	var category domain.Category
+	err = c.Bind(&category)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&category); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	category.ID = existed.ID
+	err = a.AUsecase.Update(ctx, &category)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(category))
+}
+
+// Delete will delete the category by given param, it must have no subcategories nor products left
+func (a *CategoryHandler) Delete(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	existed, err := a.AUsecase.GetByID(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(existed.OutletID) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	err = a.AUsecase.Delete(ctx, existed.ID)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponseWithoutData())
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound, constants.ErrDataNotFound:
+		return http.StatusNotFound
+	case domain.ErrConflict, constants.ErrCategoryInUse:
+		return http.StatusConflict
+	case domain.ErrBadParamInput:
+		return http.StatusBadRequest
+	case constants.ErrCategoryParent:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -3,7 +3,12 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"time"
 
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/api/v1/category/repository"
 	"mini_pos/domain"
 )
 
@@ -18,24 +23,178 @@ func NewMysqlCategoryRepository(db *sql.DB) domain.CategoryRepository {
 	}
 }
 
+// nullID store the id 0 of a global or a top level category as NULL
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
+
+func (m *mysqlCategoryRepo) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Category, err error) {
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Category, 0)
+	for rows.Next() {
+		t := domain.Category{}
+		outletID := sql.NullInt64{}
+		parentID := sql.NullInt64{}
+		err = rows.Scan(
+			&t.ID,
+			&t.Name,
+			&t.Description,
+			&outletID,
+			&parentID,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		t.OutletID = outletID.Int64
+		t.ParentID = parentID.Int64
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+// Fetch return the global categories with the categories of the outlet, or every category for the outlet 0
+func (m *mysqlCategoryRepo) Fetch(ctx context.Context, cursor string, num int64, outletId int64, parentId int64) (res []domain.Category, nextCursor string, err error) {
+	query := `SELECT id, name, description, outlet_id, parent_id, update_at, create_at
+  						FROM categories WHERE create_at > ? and (? = 0 or outlet_id is null or outlet_id = ?)
+  						and (? = -1 or IFNULL(parent_id, 0) = ?) ORDER BY create_at LIMIT ? `
+
+	decodedCursor, err := repository.DecodeCursor(cursor)
+	if err != nil && cursor != "" {
+		return nil, "", domain.ErrBadParamInput
+	}
+
+	res, err = m.fetch(ctx, query, decodedCursor, outletId, outletId, parentId, parentId, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(res) == int(num) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	}
+
+	return
+}
+
 func (m *mysqlCategoryRepo) getOne(ctx context.Context, query string, args ...interface{}) (res domain.Category, err error) {
-	stmt, err := m.DB.PrepareContext(ctx, query)
+	list, err := m.fetch(ctx, query, args...)
 	if err != nil {
 		return domain.Category{}, err
 	}
-	row := stmt.QueryRowContext(ctx, args...)
-	res = domain.Category{}
 
-	err = row.Scan(
-		&res.ID,
-		&res.Name,
-		&res.CreatedAt,
-		&res.UpdatedAt,
-	)
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+
 	return
 }
 
 func (m *mysqlCategoryRepo) GetByID(ctx context.Context, id int64) (domain.Category, error) {
-	query := `SELECT id, name, create_at, update_at FROM categories WHERE id=?`
+	query := `SELECT id, name, description, outlet_id, parent_id, update_at, create_at FROM categories WHERE id=?`
 	return m.getOne(ctx, query, id)
 }
+
+// FetchChildren return the direct subcategories of the category
+func (m *mysqlCategoryRepo) FetchChildren(ctx context.Context, id int64) ([]domain.Category, error) {
+	query := `SELECT id, name, description, outlet_id, parent_id, update_at, create_at
+  						FROM categories WHERE parent_id = ? ORDER BY id`
+	return m.fetch(ctx, query, id)
+}
+
+func (m *mysqlCategoryRepo) CountProducts(ctx context.Context, id int64) (res int64, err error) {
+	query := `SELECT COUNT(*) FROM products WHERE category_id = ?`
+	err = m.DB.QueryRowContext(ctx, query, id).Scan(&res)
+	return
+}
+
+func (m *mysqlCategoryRepo) Store(ctx context.Context, a *domain.Category) (err error) {
+	query := `INSERT  categories
+			  SET name=?, description=?, outlet_id=?, parent_id=?, update_at=? , create_at=?`
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, a.Name, a.Description, nullID(a.OutletID), nullID(a.ParentID), now, now)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	a.UpdatedAt = now
+	a.CreatedAt = now
+	return
+}
+
+// Update change the name, the description and the parent of the category, its outlet is kept
+func (m *mysqlCategoryRepo) Update(ctx context.Context, a *domain.Category) (err error) {
+	query := `UPDATE categories SET name=?, description=?, parent_id=?, update_at=? WHERE ID = ?`
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, a.Name, a.Description, nullID(a.ParentID), now, a.ID)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+		return
+	}
+	a.UpdatedAt = now
+	return
+}
+
+func (m *mysqlCategoryRepo) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM categories WHERE id = ? "
+
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAfected != 1 {
+		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", rowsAfected)
+		return
+	}
+
+	return
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"mini_pos/constants"
+	"mini_pos/domain"
+)
+
+type CategoryUsecase struct {
+	CategoryRepo   domain.CategoryRepository
+	ProductRepo    domain.ProductRepository
+	OutletRepo     domain.OutletRepository
+	contextTimeout time.Duration
+}
+
+// NewCategoryUsecase will create new an CategoryUsecase object representation of domain.CategoryUsecase interface
+func NewCategoryUsecase(a domain.CategoryRepository, product domain.ProductRepository, outlet domain.OutletRepository, timeout time.Duration) domain.CategoryUsecase {
+	return &CategoryUsecase{
+		CategoryRepo:   a,
+		ProductRepo:    product,
+		OutletRepo:     outlet,
+		contextTimeout: timeout,
+	}
+}
+
+func (a *CategoryUsecase) Fetch(c context.Context, cursor string, num int64, outletId int64, parentId int64) (res []domain.Category, nextCursor string, err error) {
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, nextCursor, err = a.CategoryRepo.Fetch(ctx, cursor, num, outletId, parentId)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(res) <= 0 {
+		return nil, "", constants.ErrDataNotFound
+	}
+	return
+}
+
+func (a *CategoryUsecase) GetByID(c context.Context, id int64) (res domain.Category, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.CategoryRepo.GetByID(ctx, id)
+}
+
+// FetchProducts return the products of the category and of its subcategories. The subcategories of
+// another outlet are left out, unless outlet is 0
+func (a *CategoryUsecase) FetchProducts(c context.Context, id int64, outletId int64, cursor string, num int64) (res []domain.Product, nextCursor string, err error) {
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	category, err := a.CategoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	categories := map[int64]domain.Category{id: category}
+	ids := []int64{id}
+	for i := 0; i < len(ids); i++ {
+		children, errChildren := a.CategoryRepo.FetchChildren(ctx, ids[i])
+		if errChildren != nil {
+			return nil, "", errChildren
+		}
+		for _, child := range children {
+			if _, ok := categories[child.ID]; ok {
+				continue
+			}
+			if outletId != 0 && child.OutletID != 0 && child.OutletID != outletId {
+				continue
+			}
+			categories[child.ID] = child
+			ids = append(ids, child.ID)
+		}
+	}
+
+	res, nextCursor, err = a.ProductRepo.FetchByCategory(ctx, cursor, num, ids)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(res) <= 0 {
+		return nil, "", constants.ErrDataNotFound
+	}
+	for i := range res {
+		res[i].Category = categories[res[i].Category.ID]
+	}
+	return
+}
+
+// checkParent refuse a parent of another outlet, and a parent under the category itself which would make a cycle
+func (a *CategoryUsecase) checkParent(ctx context.Context, m *domain.Category) (err error) {
+	if m.ParentID == 0 {
+		return
+	}
+	parent, err := a.CategoryRepo.GetByID(ctx, m.ParentID)
+	if err == domain.ErrNotFound {
+		return constants.ErrCategoryParent
+	}
+	if err != nil {
+		return
+	}
+	if parent.OutletID != 0 && parent.OutletID != m.OutletID {
+		return constants.ErrCategoryParent
+	}
+
+	for parent.ID != 0 {
+		if m.ID != 0 && parent.ID == m.ID {
+			return constants.ErrCategoryParent
+		}
+		if parent.ParentID == 0 {
+			return nil
+		}
+		parent, err = a.CategoryRepo.GetByID(ctx, parent.ParentID)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Store add a category to the outlet, or a global category for the outlet 0
+func (a *CategoryUsecase) Store(c context.Context, m *domain.Category) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if m.OutletID != 0 {
+		_, err = a.OutletRepo.GetByID(ctx, m.OutletID)
+		if err != nil {
+			return
+		}
+	}
+	m.ID = 0
+	err = a.checkParent(ctx, m)
+	if err != nil {
+		return
+	}
+	return a.CategoryRepo.Store(ctx, m)
+}
+
+// Update rename or move the category under another parent, a category never changes of outlet
+func (a *CategoryUsecase) Update(c context.Context, m *domain.Category) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	existed, err := a.CategoryRepo.GetByID(ctx, m.ID)
+	if err != nil {
+		return
+	}
+	m.OutletID = existed.OutletID
+	m.CreatedAt = existed.CreatedAt
+	err = a.checkParent(ctx, m)
+	if err != nil {
+		return
+	}
+	return a.CategoryRepo.Update(ctx, m)
+}
+
+// Delete remove a category left without subcategories nor products
+func (a *CategoryUsecase) Delete(c context.Context, id int64) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	_, err = a.CategoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	children, err := a.CategoryRepo.FetchChildren(ctx, id)
+	if err != nil {
+		return
+	}
+	products, err := a.CategoryRepo.CountProducts(ctx, id)
+	if err != nil {
+		return
+	}
+	if len(children) > 0 || products > 0 {
+		return constants.ErrCategoryInUse
+	}
+	return a.CategoryRepo.Delete(ctx, id)
+}
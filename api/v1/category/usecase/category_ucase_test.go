@@ -0,0 +1,55 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"mini_pos/api/v1/category/usecase"
+	"mini_pos/constants"
+	"mini_pos/domain"
+	"mini_pos/domain/fakes"
+)
+
+func TestCategoryTree(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakes.CategoryRepository{Products: make(map[int64]int64)}
+	products := &fakes.ProductRepository{}
+	outlets := &fakes.OutletRepository{Outlets: map[int64]domain.Outlet{2: {ID: 2}, 3: {ID: 3}}}
+	u := usecase.NewCategoryUsecase(repo, products, outlets, time.Second)
+
+	drinks := domain.Category{Name: "Drinks"}
+	assert.NoError(t, u.Store(ctx, &drinks))
+	coffee := domain.Category{Name: "Coffee", OutletID: 2, ParentID: drinks.ID}
+	assert.NoError(t, u.Store(ctx, &coffee))
+	tea := domain.Category{Name: "Tea", OutletID: 3, ParentID: drinks.ID}
+	assert.NoError(t, u.Store(ctx, &tea))
+
+	// a global category cannot sit under the category of an outlet, nor a category under another outlet
+	err := u.Store(ctx, &domain.Category{Name: "Beans", ParentID: coffee.ID})
+	assert.Equal(t, constants.ErrCategoryParent, err)
+	err = u.Store(ctx, &domain.Category{Name: "Beans", OutletID: 3, ParentID: coffee.ID})
+	assert.Equal(t, constants.ErrCategoryParent, err)
+
+	// nor be moved under its own subcategory
+	drinks.ParentID = coffee.ID
+	assert.Equal(t, constants.ErrCategoryParent, u.Update(ctx, &drinks))
+
+	// the products of the subcategories of the outlet are listed with the category
+	for _, c := range []domain.Category{drinks, coffee, tea} {
+		products.Products = append(products.Products, domain.Product{ID: c.ID, Category: domain.Category{ID: c.ID}})
+	}
+	res, _, err := u.FetchProducts(ctx, drinks.ID, 2, "", 0)
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, "Coffee", res[1].Category.Name)
+	res, _, err = u.FetchProducts(ctx, drinks.ID, 0, "", 0)
+	assert.NoError(t, err)
+	assert.Len(t, res, 3)
+
+	assert.Equal(t, constants.ErrCategoryInUse, u.Delete(ctx, drinks.ID))
+	repo.Products[tea.ID] = 1
+	assert.Equal(t, constants.ErrCategoryInUse, u.Delete(ctx, tea.ID))
+}
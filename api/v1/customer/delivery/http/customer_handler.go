@@ -33,11 +33,11 @@ func NewCustomerHandler(e *echo.Echo, us domain.CustomerUsecase, cfg config.Conf
 	CustomerV1:= e.Group("")
 
 	CustomerV1.Use(middlwr.JWTMiddleware(cfg))
-	CustomerV1.GET("/customers", handler.FetchCustomer)
-	CustomerV1.POST("/customers", handler.Store)
-	CustomerV1.PUT("/customers", handler.Update)
-	CustomerV1.GET("/customers/:id", handler.GetByID)
-	CustomerV1.DELETE("/customers/:id", handler.Delete)
+	CustomerV1.GET("/customers", handler.FetchCustomer, middlwr.Permission(domain.PermCustomerRead))
+	CustomerV1.POST("/customers", handler.Store, middlwr.Permission(domain.PermCustomerWrite))
+	CustomerV1.PUT("/customers", handler.Update, middlwr.Permission(domain.PermCustomerWrite))
+	CustomerV1.GET("/customers/:id", handler.GetByID, middlwr.Permission(domain.PermCustomerRead))
+	CustomerV1.DELETE("/customers/:id", handler.Delete, middlwr.Permission(domain.PermCustomerWrite))
 }
 
 // FetchCustomer will fetch the Customer based on given params
@@ -50,13 +50,6 @@ func (a *CustomerHandler) FetchCustomer(c echo.Context) error {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	numS := c.QueryParam("num")
 	num, _ := strconv.Atoi(numS)
 	cursor := c.QueryParam("cursor")
@@ -85,13 +78,6 @@ func (a *CustomerHandler) GetByID(c echo.Context) error {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
@@ -126,13 +112,6 @@ func (a *CustomerHandler) GetByName(c echo.Context) error {
 		}))
 	}
 
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	sku := c.Param("name")
 
 	ctx := c.Request().Context()
@@ -168,13 +147,6 @@ func (a *CustomerHandler) Update(c echo.Context) (err error) {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	var Customer domain.Customer
 	err = c.Bind(&Customer)
 	if err != nil {
@@ -210,13 +182,6 @@ func (a *CustomerHandler) Store(c echo.Context) (err error) {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	var Customer domain.Customer
 	err = c.Bind(&Customer)
 	if err != nil {
@@ -251,13 +216,6 @@ func (a *CustomerHandler) Delete(c echo.Context) error {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
@@ -29,7 +29,7 @@ func NewCustomerHandler(e *echo.Echo, us domain.MediaUseCase, cfg config.Config)
 	CustomerV1:= e.Group("")
 
 	CustomerV1.Use(middlwr.JWTMiddleware(cfg))
-	CustomerV1.POST("/media/upload", handler.UploadMedia)
+	CustomerV1.POST("/media/upload", handler.UploadMedia, middlwr.Permission(domain.PermMediaUpload))
 }
 
 // FetchCustomer will fetch the Customer based on given params
@@ -42,13 +42,6 @@ func (a *MediaHandler) UploadMedia(c echo.Context) error {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	form, err := c.FormFile("file")
 	if err != nil {
 		fmt.Println(form,err)
@@ -9,6 +9,8 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"mini_pos/api/v1/order/repository"
+	"mini_pos/api/transaction"
+	"mini_pos/constants"
 	"mini_pos/domain"
 )
 
@@ -22,7 +24,7 @@ func NewMysqlOrderRepository(Conn *sql.DB) domain.OrderRepository {
 }
 
 func (m *mysqlOrderRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Order, err error) {
-	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
@@ -38,11 +40,25 @@ func (m *mysqlOrderRepository) fetch(ctx context.Context, query string, args ...
 	result = make([]domain.Order, 0)
 	for rows.Next() {
 		t := domain.Order{}
-		customerID := int64(0)
+		customerID := sql.NullInt64{}
+		userID := sql.NullInt64{}
+		outletID := sql.NullInt64{}
+		label := sql.NullString{}
+		voidReason := sql.NullString{}
+		voidBy := sql.NullInt64{}
+		voidAt := sql.NullTime{}
 		err = rows.Scan(
 			&t.ID,
 			&customerID,
+			&userID,
+			&outletID,
+			&label,
+			&t.Tax,
 			&t.IsCheckout,
+			&t.Status,
+			&voidReason,
+			&voidBy,
+			&voidAt,
 			&t.UpdatedAt,
 			&t.CreatedAt,
 		)
@@ -52,7 +68,15 @@ func (m *mysqlOrderRepository) fetch(ctx context.Context, query string, args ...
 			return nil, err
 		}
 		t.Customer = domain.Customer{
-			ID: customerID,
+			ID: customerID.Int64,
+		}
+		t.UserID = userID.Int64
+		t.OutletID = outletID.Int64
+		t.Label = label.String
+		t.VoidReason = domain.VoidReason(voidReason.String)
+		t.VoidBy = voidBy.Int64
+		if voidAt.Valid {
+			t.VoidAt = &voidAt.Time
 		}
 		result = append(result, t)
 	}
@@ -62,8 +86,8 @@ func (m *mysqlOrderRepository) fetch(ctx context.Context, query string, args ...
 
 func (m *mysqlOrderRepository) Fetch(ctx context.Context, cursor string, num int64) (res []domain.Order, nextCursor string, err error) {
 
-	query := `SELECT id,customer_id, isCheckout, update_at, create_at
-  						FROM orders WHERE %s create_at > ? ORDER BY create_at LIMIT ? `
+	query := `SELECT id,customer_id, user_id, outlet_id, label, tax, isCheckout, status, void_reason, void_by, void_at, update_at, create_at
+  						FROM orders WHERE create_at > ? ORDER BY create_at LIMIT ? `
 
 	decodedCursor, err := repository.DecodeCursor(cursor)
 	if err != nil && cursor != "" {
@@ -84,8 +108,8 @@ func (m *mysqlOrderRepository) Fetch(ctx context.Context, cursor string, num int
 
 func (m *mysqlOrderRepository) GetByID(ctx context.Context, id int64) (res domain.Order, err error) {
 
-	query := `SELECT id,customer_id, isCheckout, update_at, create_at
-  						FROM orders WHERE ID = ? and isCheckout=0`
+	query := `SELECT id,customer_id, user_id, outlet_id, label, tax, isCheckout, status, void_reason, void_by, void_at, update_at, create_at
+  						FROM orders WHERE ID = ? and isCheckout=0 and status='active'`
 
 	list, err := m.fetch(ctx, query, id)
 	if err != nil {
@@ -101,12 +125,52 @@ func (m *mysqlOrderRepository) GetByID(ctx context.Context, id int64) (res domai
 	return
 }
 
-func (m *mysqlOrderRepository) GetStatus(ctx context.Context) (res int64, err error) {
+// GetAnyByID return an order whether it is still open or already checked out
+func (m *mysqlOrderRepository) GetAnyByID(ctx context.Context, id int64) (res domain.Order, err error) {
+
+	query := `SELECT id,customer_id, user_id, outlet_id, label, tax, isCheckout, status, void_reason, void_by, void_at, update_at, create_at
+  						FROM orders WHERE ID = ?`
+
+	list, err := m.fetch(ctx, query, id)
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+
+	return
+}
+
+// Lock hold a row lock on the order until the running transaction ends
+func (m *mysqlOrderRepository) Lock(ctx context.Context, id int64) (err error) {
+	query := `SELECT id FROM orders WHERE ID = ? FOR UPDATE`
+
+	var lockedID int64
+	err = transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, id).Scan(&lockedID)
+	if err == sql.ErrNoRows {
+		return domain.ErrNotFound
+	}
+	return
+}
+
+func (m *mysqlOrderRepository) FetchOpen(ctx context.Context, userId int64, outletId int64) (res []domain.Order, err error) {
+
+	query := `SELECT id,customer_id, user_id, outlet_id, label, tax, isCheckout, status, void_reason, void_by, void_at, update_at, create_at
+  						FROM orders WHERE user_id = ? and outlet_id = ? and isCheckout=0 and status='active' ORDER BY create_at DESC`
+
+	return m.fetch(ctx, query, userId, outletId)
+}
+
+func (m *mysqlOrderRepository) GetStatus(ctx context.Context, userId int64, outletId int64) (res int64, err error) {
 
-	query := `SELECT id,customer_id, isCheckout, update_at, create_at
-  						FROM orders WHERE isCheckout=0`
+	query := `SELECT id,customer_id, user_id, outlet_id, label, tax, isCheckout, status, void_reason, void_by, void_at, update_at, create_at
+  						FROM orders WHERE user_id = ? and outlet_id = ? and isCheckout=0 and status='active' ORDER BY create_at DESC LIMIT 1`
 
-	list, err := m.fetch(ctx, query)
+	list, err := m.fetch(ctx, query, userId, outletId)
 	if err != nil {
 		return 0, err
 	}
@@ -122,14 +186,14 @@ func (m *mysqlOrderRepository) GetStatus(ctx context.Context) (res int64, err er
 
 func (m *mysqlOrderRepository) Store(ctx context.Context, a *domain.Order) (id int64, err error) {
 	query := `INSERT  orders 
-			  SET customer_id=?, isCheckout=0,
+			  SET customer_id=?, user_id=?, actor_id=?, outlet_id=?, label=?, tax=?, isCheckout=0,
 			  update_at=? , create_at=?`
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	fmt.Println(err,stmt)
 	if err != nil {
 		return
 	}
-	res, err := stmt.ExecContext(ctx,a.Customer.ID, time.Now(), time.Now())
+	res, err := stmt.ExecContext(ctx,nullID(a.Customer.ID), a.UserID, nullID(a.ActorID), a.OutletID, a.Label, a.Tax, time.Now(), time.Now())
 	if err != nil {
 		return
 	}
@@ -145,15 +209,15 @@ func (m *mysqlOrderRepository) Store(ctx context.Context, a *domain.Order) (id i
 func (m *mysqlOrderRepository) Update(ctx context.Context, a *domain.Order) (err error) {
 
 	query := `UPDATE orders 
-			SET customer_id=?, isCheckout=?, update_at=?
+			SET customer_id=?, label=?, tax=?, isCheckout=?, update_at=?
 			 WHERE ID = ? `
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
 
-	res, err := stmt.ExecContext(ctx,a.Customer.ID, a.IsCheckout, time.Now(), a.ID)
+	res, err := stmt.ExecContext(ctx,nullID(a.Customer.ID), a.Label, a.Tax, a.IsCheckout, time.Now(), a.ID)
 	if err != nil {
 		return
 	}
@@ -169,3 +233,120 @@ func (m *mysqlOrderRepository) Update(ctx context.Context, a *domain.Order) (err
 
 	return
 }
+
+// Checkout close an open order, it fails when the order was already checked out by another request
+func (m *mysqlOrderRepository) Checkout(ctx context.Context, a *domain.Order) (err error) {
+
+	query := `UPDATE orders 
+			SET customer_id=?, isCheckout=1, update_at=?
+			 WHERE ID = ? and isCheckout=0 and status='active'`
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx,nullID(a.Customer.ID), time.Now(), a.ID)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		return constants.ErrOrderNotOpen
+	}
+	a.IsCheckout = 1
+
+	return
+}
+
+// Void mark an open order as voided, the row and its lines are kept for the void report
+func (m *mysqlOrderRepository) Void(ctx context.Context, a *domain.Order) (err error) {
+
+	query := `UPDATE orders 
+			SET status='void', void_reason=?, void_by=?, void_actor_id=?, void_at=?, update_at=?
+			 WHERE ID = ? and isCheckout=0 and status='active'`
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, a.VoidReason, a.VoidBy, nullID(a.VoidActorID), now, now, a.ID)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		return constants.ErrOrderNotOpen
+	}
+	a.Status = domain.StatusVoid
+	a.VoidAt = &now
+
+	return
+}
+
+// FetchVoids return the voided orders of the filter, valued with the outlet price of their active lines
+func (m *mysqlOrderRepository) FetchVoids(ctx context.Context, filter domain.VoidFilter) (res []domain.VoidEntry, err error) {
+	query := `SELECT orders.id, orders.outlet_id, orders.user_id, orders.void_reason, orders.void_by, orders.void_at,
+				IFNULL(SUM(orderItem.qty),0),
+				IFNULL(SUM(orderItem.qty * IFNULL(orderItem.price, (SELECT price FROM outlet_products
+					WHERE outlet_products.product_id = orderItem.product_id and outlet_products.outlet_id = orders.outlet_id
+					ORDER BY outlet_products.id LIMIT 1))),0)
+  				FROM orders left join orderItem on orderItem.order_id = orders.id and orderItem.status='active'
+  				WHERE orders.status='void' and orders.void_at >= ? and orders.void_at < ?
+  				and (? = 0 or orders.outlet_id = ?) and (? = 0 or orders.user_id = ?)
+  				GROUP BY orders.id ORDER BY orders.void_at`
+
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, filter.From, filter.To,
+		filter.OutletID, filter.OutletID, filter.UserID, filter.UserID)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	res = make([]domain.VoidEntry, 0)
+	for rows.Next() {
+		t := domain.VoidEntry{}
+		outletID := sql.NullInt64{}
+		userID := sql.NullInt64{}
+		err = rows.Scan(
+			&t.OrderID,
+			&outletID,
+			&userID,
+			&t.Reason,
+			&t.VoidBy,
+			&t.VoidAt,
+			&t.Qty,
+			&t.Amount,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		t.OutletID = outletID.Int64
+		t.UserID = userID.Int64
+		t.Amount = domain.RoundMoney(t.Amount)
+		res = append(res, t)
+	}
+
+	return
+}
+
+// nullID stores an empty foreign key as NULL instead of 0
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
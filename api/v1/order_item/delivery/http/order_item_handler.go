@@ -7,6 +7,7 @@ import (
 	"mini_pos/constants"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
@@ -15,6 +16,8 @@ import (
 	"mini_pos/domain"
 )
 
+const dateFormat = "2006-01-02"
+
 // ResponseError represent the reseponse error struct
 type ResponseError struct {
 	Message string `json:"message"`
@@ -33,12 +36,17 @@ func NewOrderItemHandler(e *echo.Echo, us domain.OrderItemUsecase, cfg config.Co
 	OrderItemV1:= e.Group("")
 
 	OrderItemV1.Use(middlwr.JWTMiddleware(cfg))
-	OrderItemV1.GET("/cart", handler.FetchOrderItem)
-	OrderItemV1.GET("/cart/order/:id", handler.FetchByOrderID)
-	OrderItemV1.POST("/cart", handler.Store)
-	OrderItemV1.PUT("/cart", handler.Update)
-	OrderItemV1.GET("/cart/:id", handler.GetByID)
-	OrderItemV1.DELETE("/cart/:id", handler.Delete)
+	OrderItemV1.GET("/cart", handler.FetchOrderItem, middlwr.Permission(domain.PermSell))
+	OrderItemV1.GET("/cart/order", handler.FetchCarts, middlwr.Permission(domain.PermSell))
+	OrderItemV1.POST("/cart/order", handler.OpenCart, middlwr.Permission(domain.PermSell))
+	OrderItemV1.PUT("/cart/order/:id", handler.UpdateCart, middlwr.Permission(domain.PermSell))
+	OrderItemV1.GET("/cart/order/:id", handler.FetchByOrderID, middlwr.Permission(domain.PermSell))
+	OrderItemV1.POST("/cart", handler.Store, middlwr.Permission(domain.PermSell))
+	OrderItemV1.PUT("/cart", handler.Update, middlwr.Permission(domain.PermSell))
+	OrderItemV1.GET("/cart/:id", handler.GetByID, middlwr.Permission(domain.PermSell))
+	OrderItemV1.DELETE("/cart/:id", handler.Void, middlwr.Permission(domain.PermSell))
+	OrderItemV1.DELETE("/cart/order/:id", handler.VoidCart, middlwr.Permission(domain.PermSell))
+	OrderItemV1.GET("/reports/voids", handler.FetchVoids, middlwr.Permission(domain.PermReportRead))
 }
 
 // FetchOrderItem will fetch the OrderItem based on given params
@@ -61,7 +69,7 @@ func (a *OrderItemHandler) FetchByOrderID(c echo.Context) error {
 	}
 	ctx := c.Request().Context()
 
-	listAr, err := a.AUsecase.FetchByOrderID(ctx, int64(idP))
+	listAr, err := a.AUsecase.FetchByOrderID(ctx, int64(idP), int64(claims.ID))
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
 			Code:    getStatusCode(err),
@@ -82,12 +90,23 @@ func (a *OrderItemHandler) FetchOrderItem(c echo.Context) error {
 			Data:    nil,
 		}))
 	}
+	outlet, err := strconv.Atoi(c.QueryParam("outlet"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: constants.ErrParamsIsNotInvalid.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
+	}
 	numS := c.QueryParam("num")
 	num, _ := strconv.Atoi(numS)
 	cursor := c.QueryParam("cursor")
 	ctx := c.Request().Context()
 
-	listAr, nextCursor, err := a.AUsecase.Fetch(ctx, cursor, int64(num))
+	listAr, nextCursor, err := a.AUsecase.Fetch(ctx, cursor, int64(num), int64(claims.ID), int64(outlet))
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
 			Code:    getStatusCode(err),
@@ -100,6 +119,142 @@ func (a *OrderItemHandler) FetchOrderItem(c echo.Context) error {
 	return c.JSON(common.NewSuccessResponse(listAr))
 }
 
+// FetchCarts will fetch the open (parked) carts of the logged in cashier for an outlet
+func (a *OrderItemHandler) FetchCarts(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	outlet, err := strconv.Atoi(c.QueryParam("outlet"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: constants.ErrParamsIsNotInvalid.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
+	}
+	ctx := c.Request().Context()
+
+	listAr, err := a.AUsecase.FetchCarts(ctx, int64(claims.ID), int64(outlet))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(listAr))
+}
+
+// OpenCart will park the current cart and open a new one for the logged in cashier
+func (a *OrderItemHandler) OpenCart(c echo.Context) (err error) {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	var cart domain.RequestCart
+	err = c.Bind(&cart)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	validate := validator.New()
+	err = validate.Struct(cart)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	if !claims.HasOutlet(cart.OutletID) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	ctx := c.Request().Context()
+	cart.UserID = int64(claims.ID)
+	cart.ActorID = int64(claims.ActorID)
+	order, err := a.AUsecase.OpenCart(ctx, &cart)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(order))
+}
+
+// UpdateCart will update the customer, label and order tax of a cart
+func (a *OrderItemHandler) UpdateCart(c echo.Context) (err error) {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+	var cart domain.RequestCart
+	err = c.Bind(&cart)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	validate := validator.New()
+	err = validate.Struct(cart)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	cart.UserID = int64(claims.ID)
+	order, err := a.AUsecase.UpdateCart(ctx, int64(idP), &cart)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(order))
+}
+
 // GetByID will get OrderItem by given id
 func (a *OrderItemHandler) GetByID(c echo.Context) error {
 	claims := middlwr.GetTokenFromContext(c)
@@ -123,7 +278,7 @@ func (a *OrderItemHandler) GetByID(c echo.Context) error {
 	id := int64(idP)
 	ctx := c.Request().Context()
 
-	prod, err := a.AUsecase.GetByID(ctx, id)
+	prod, err := a.AUsecase.GetByID(ctx, id, int64(claims.ID))
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
 			Code:    getStatusCode(err),
@@ -154,13 +309,6 @@ func (a *OrderItemHandler) Update(c echo.Context) (err error) {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	var OrderItem domain.RequestOrderItem
 	err = c.Bind(&OrderItem)
 	if err != nil {
@@ -181,6 +329,7 @@ func (a *OrderItemHandler) Update(c echo.Context) (err error) {
 	}
 
 	ctx := c.Request().Context()
+	OrderItem.UserID = int64(claims.ID)
 	err = a.AUsecase.Update(ctx, &OrderItem)
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
@@ -204,13 +353,6 @@ func (a *OrderItemHandler) Store(c echo.Context) (err error) {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	var OrderItem domain.RequestOrderItem
 	err = c.Bind(&OrderItem)
 	if err != nil {
@@ -230,7 +372,14 @@ func (a *OrderItemHandler) Store(c echo.Context) (err error) {
 		}))
 	}
 
+	// a line added to an existing order is checked against the owner of the order
+	if OrderItem.OrderID == 0 && !claims.HasOutlet(OrderItem.OutletID) {
+		return middlwr.OutletForbidden(c)
+	}
+
 	ctx := c.Request().Context()
+	OrderItem.UserID = int64(claims.ID)
+	OrderItem.ActorID = int64(claims.ActorID)
 	err = a.AUsecase.Store(ctx, &OrderItem)
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
@@ -243,8 +392,37 @@ func (a *OrderItemHandler) Store(c echo.Context) (err error) {
 	return c.JSON(common.NewSuccessResponse(OrderItem))
 }
 
-// Delete will delete OrderItem by given param
-func (a *OrderItemHandler) Delete(c echo.Context) error {
+func isVoidRequestValid(m *domain.RequestVoid) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(m)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// bindVoid read the void request of the id param, the reason may come from the body or the query.
+// actorId is the support staff behind an impersonation token, 0 otherwise
+func bindVoid(c echo.Context, userId int64, actorId int64) (m domain.RequestVoid, err error) {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return m, domain.ErrNotFound
+	}
+	err = c.Bind(&m)
+	if err != nil {
+		return m, domain.ErrBadParamInput
+	}
+	if ok, errValid := isVoidRequestValid(&m); !ok {
+		return m, errValid
+	}
+	m.ID = int64(idP)
+	m.UserID = userId
+	m.ActorID = actorId
+	return
+}
+
+// Void will void an OrderItem by given param, the line is kept with its reason
+func (a *OrderItemHandler) Void(c echo.Context) error {
 	claims := middlwr.GetTokenFromContext(c)
 	if claims == nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
@@ -253,26 +431,51 @@ func (a *OrderItemHandler) Delete(c echo.Context) error {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
+	req, err := bindVoid(c, int64(claims.ID), int64(claims.ActorID))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getVoidStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+
+	err = a.AUsecase.Void(ctx, &req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponseWithoutData())
+}
+
+// VoidCart will void an open order of the logged in cashier, the order is kept with its reason
+func (a *OrderItemHandler) VoidCart(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
 			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
+			Message: constants.ErrTokenAlreadyExpired.Error(),
 			Data:    nil,
 		}))
 	}
-	idP, err := strconv.Atoi(c.Param("id"))
+	req, err := bindVoid(c, int64(claims.ID), int64(claims.ActorID))
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusNotFound,
-			Message: constants.ErrDataNotFound.Error(),
+			Code:    getVoidStatusCode(err),
+			Message: err.Error(),
 			Data:    nil,
 		}))
 	}
 
-	id := int64(idP)
 	ctx := c.Request().Context()
 
-	err = a.AUsecase.Delete(ctx, id)
+	err = a.AUsecase.VoidCart(ctx, &req)
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
 			Code:    getStatusCode(err),
@@ -284,6 +487,68 @@ func (a *OrderItemHandler) Delete(c echo.Context) error {
 	return c.JSON(common.NewSuccessResponseWithoutData())
 }
 
+// FetchVoids will report the voided orders and lines between the from and to dates (2006-01-02, inclusive)
+func (a *OrderItemHandler) FetchVoids(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	now := time.Now()
+	filter := domain.VoidFilter{
+		From: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+	}
+	filter.To = filter.From
+	var err error
+	if from := c.QueryParam("from"); from != "" {
+		filter.From, err = time.ParseInLocation(dateFormat, from, now.Location())
+	}
+	if to := c.QueryParam("to"); to != "" && err == nil {
+		filter.To, err = time.ParseInLocation(dateFormat, to, now.Location())
+	}
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: domain.ErrBadParamInput.Error(),
+			Data:    nil,
+		}))
+	}
+	filter.To = filter.To.AddDate(0, 0, 1)
+	outlet, _ := strconv.Atoi(c.QueryParam("outlet"))
+	user, _ := strconv.Atoi(c.QueryParam("user"))
+	filter.OutletID = int64(outlet)
+	filter.UserID = int64(user)
+	if !claims.HasOutlet(filter.OutletID) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	ctx := c.Request().Context()
+
+	report, err := a.AUsecase.FetchVoids(ctx, filter)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(report))
+}
+
+// getVoidStatusCode map the errors of a void request before it reaches the usecase
+func getVoidStatusCode(err error) int {
+	switch err {
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
+
 func getStatusCode(err error) int {
 	if err == nil {
 		return http.StatusOK
@@ -297,6 +562,10 @@ func getStatusCode(err error) int {
 		return http.StatusNotFound
 	case domain.ErrConflict:
 		return http.StatusConflict
+	case constants.ErrOrderHasPayment, constants.ErrOrderItemVoided, constants.ErrOrderNotOpen:
+		return http.StatusConflict
+	case constants.ErrVoidReasonInvalid:
+		return http.StatusBadRequest
 	default:
 		return http.StatusInternalServerError
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"mini_pos/api/v1/order_item/repository"
+	"mini_pos/api/transaction"
 	"mini_pos/domain"
 )
 
@@ -23,7 +24,7 @@ func NewMysqlOrderItemRepository(Conn *sql.DB) domain.OrderItemRepository {
 }
 
 func (m *mysqlOrderItemRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.OrderItem, err error) {
-	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
@@ -41,12 +42,20 @@ func (m *mysqlOrderItemRepository) fetch(ctx context.Context, query string, args
 		t := domain.OrderItem{}
 		orderID := int64(0)
 		productID := int64(0)
+		voidReason := sql.NullString{}
+		voidBy := sql.NullInt64{}
+		voidAt := sql.NullTime{}
 		err = rows.Scan(
 			&t.ID,
 			&orderID,
 			&productID,
 			&t.Qty,
 			&t.Discount,
+			&t.TaxApply,
+			&t.Status,
+			&voidReason,
+			&voidBy,
+			&voidAt,
 			&t.UpdatedAt,
 			&t.CreatedAt,
 		)
@@ -61,23 +70,31 @@ func (m *mysqlOrderItemRepository) fetch(ctx context.Context, query string, args
 		t.Product = domain.Product{
 			ID: productID,
 		}
+		t.VoidReason = domain.VoidReason(voidReason.String)
+		t.VoidBy = voidBy.Int64
+		if voidAt.Valid {
+			t.VoidAt = &voidAt.Time
+		}
 		result = append(result, t)
 	}
 
 	return result, nil
 }
 
-func (m *mysqlOrderItemRepository) Fetch(ctx context.Context, cursor string, num int64) (res []domain.OrderItem, nextCursor string, err error) {
+func (m *mysqlOrderItemRepository) Fetch(ctx context.Context, cursor string, num int64, userId int64, outletId int64) (res []domain.OrderItem, nextCursor string, err error) {
 
-	query := `SELECT id,order_id, product_id, qty, discount, update_at, create_at
-  						FROM orderItem WHERE  create_at > ? ORDER BY create_at LIMIT ? `
+	query := `SELECT orderItem.id,orderItem.order_id, orderItem.product_id, orderItem.qty, orderItem.discount, orderItem.tax_apply, orderItem.status, orderItem.void_reason, orderItem.void_by, orderItem.void_at, orderItem.update_at, orderItem.create_at
+  						FROM orderItem join orders on orders.id = orderItem.order_id
+  						WHERE orders.user_id = ? and orders.outlet_id = ? and orders.isCheckout=0 and orders.status='active'
+  						and orderItem.status='active'
+  						and orderItem.create_at > ? ORDER BY orderItem.create_at LIMIT ? `
 
 	decodedCursor, err := repository.DecodeCursor(cursor)
 	if err != nil && cursor != "" {
 		return nil, "", domain.ErrBadParamInput
 	}
 
-	res, err = m.fetch(ctx, query, decodedCursor, num)
+	res, err = m.fetch(ctx, query, userId, outletId, decodedCursor, num)
 	if err != nil {
 		return nil, "", err
 	}
@@ -91,7 +108,7 @@ func (m *mysqlOrderItemRepository) Fetch(ctx context.Context, cursor string, num
 
 func (m *mysqlOrderItemRepository) FetchByOrderID(ctx context.Context, id int64) (res []domain.OrderItem, err error) {
 
-	query := `SELECT id,order_id, product_id, qty, discount, update_at, create_at
+	query := `SELECT id,order_id, product_id, qty, discount, tax_apply, status, void_reason, void_by, void_at, update_at, create_at
   						FROM orderItem WHERE  order_id = ? `
 
 
@@ -110,7 +127,7 @@ func (m *mysqlOrderItemRepository) FetchByOrderID(ctx context.Context, id int64)
 
 func (m *mysqlOrderItemRepository) GetByID(ctx context.Context, id int64) (res domain.OrderItem, err error) {
 
-	query := `SELECT id,order_id, product_id, qty, discount, update_at, create_at
+	query := `SELECT id,order_id, product_id, qty, discount, tax_apply, status, void_reason, void_by, void_at, update_at, create_at
   						FROM orderItem WHERE ID = ? `
 
 	list, err := m.fetch(ctx, query, id)
@@ -129,8 +146,8 @@ func (m *mysqlOrderItemRepository) GetByID(ctx context.Context, id int64) (res d
 
 func (m *mysqlOrderItemRepository) GetByOrderIDAndProdID(ctx context.Context, orderId int64, prodId int64) (res domain.OrderItem, err error) {
 
-	query := `SELECT id,order_id, product_id, qty, discount, update_at, create_at
-  						FROM orderItem WHERE order_id = ? and product_id=?`
+	query := `SELECT id,order_id, product_id, qty, discount, tax_apply, status, void_reason, void_by, void_at, update_at, create_at
+  						FROM orderItem WHERE order_id = ? and product_id=? and status='active'`
 
 	list, err := m.fetch(ctx, query, orderId, prodId)
 	if err != nil {
@@ -147,16 +164,82 @@ func (m *mysqlOrderItemRepository) GetByOrderIDAndProdID(ctx context.Context, or
 }
 
 
+// FetchBillLines return the lines of an order priced with the price kept at checkout,
+// or the outlet_products price of the order outlet while the order is open
+func (m *mysqlOrderItemRepository) FetchBillLines(ctx context.Context, orderId int64, outletId int64) (res []domain.BillLine, err error) {
+	query := `SELECT orderItem.id, orderItem.product_id, products.name, orderItem.qty,
+				IFNULL(orderItem.discount,0), IFNULL(orderItem.tax_apply,0),
+				IFNULL(orderItem.price, (SELECT price FROM outlet_products WHERE outlet_products.product_id = orderItem.product_id
+					and outlet_products.outlet_id = ? ORDER BY outlet_products.id LIMIT 1)) price
+  				FROM orderItem join products on products.id = orderItem.product_id
+  				WHERE orderItem.order_id = ? and orderItem.status='active' ORDER BY orderItem.id`
+
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, outletId, orderId)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	res = make([]domain.BillLine, 0)
+	for rows.Next() {
+		t := domain.BillLine{}
+		price := sql.NullFloat64{}
+		err = rows.Scan(
+			&t.OrderItemID,
+			&t.ProductID,
+			&t.Name,
+			&t.Qty,
+			&t.Discount,
+			&t.TaxRate,
+			&price,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		if !price.Valid {
+			return nil, constants.ErrProductNotInOutlet
+		}
+		t.Price = price.Float64
+		res = append(res, t)
+	}
+
+	if len(res) == 0 {
+		return nil, constants.ErrOrderEmpty
+	}
+
+	return
+}
+
+// SetPrice keep the unit price of the line so a paid order is not repriced later
+func (m *mysqlOrderItemRepository) SetPrice(ctx context.Context, id int64, price float64) (err error) {
+	query := `UPDATE orderItem SET price=?, update_at=? WHERE ID = ?`
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	_, err = stmt.ExecContext(ctx, price, time.Now(), id)
+	return
+}
+
 func (m *mysqlOrderItemRepository) Store(ctx context.Context, a *domain.RequestOrderItem) (err error) {
 	query := `INSERT  orderItem 
-			  SET order_id=?, product_id=?, qty=?, discount=?, 
+			  SET order_id=?, product_id=?, qty=?, discount=?, tax_apply=?,
 			   update_at=? , create_at=?`
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
 
-	res, err := stmt.ExecContext(ctx,a.OrderID,a.ProductID,a.Qty, a.Discount, time.Now(), time.Now())
+	res, err := stmt.ExecContext(ctx,a.OrderID,a.ProductID,a.Qty, a.Discount, a.TaxApply, time.Now(), time.Now())
 	if err != nil {
 		return
 	}
@@ -168,15 +251,19 @@ func (m *mysqlOrderItemRepository) Store(ctx context.Context, a *domain.RequestO
 	return
 }
 
-func (m *mysqlOrderItemRepository) Delete(ctx context.Context, id int64) (err error) {
-	query := "DELETE FROM orderItem WHERE id = ? "
+// Void mark an active line as voided, the row is kept for the void report
+func (m *mysqlOrderItemRepository) Void(ctx context.Context, id int64, a *domain.RequestVoid) (err error) {
+	query := `UPDATE orderItem 
+			SET status='void', void_reason=?, void_by=?, void_actor_id=?, void_at=?, update_at=?
+			 WHERE ID = ? and status='active'`
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
 
-	res, err := stmt.ExecContext(ctx, id)
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, a.Reason, a.UserID, nullID(a.ActorID), now, now, id)
 	if err != nil {
 		return
 	}
@@ -187,8 +274,65 @@ func (m *mysqlOrderItemRepository) Delete(ctx context.Context, id int64) (err er
 	}
 
 	if rowsAfected != 1 {
-		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", rowsAfected)
-		return
+		return constants.ErrOrderItemVoided
+	}
+
+	return
+}
+
+// FetchVoids return the lines voided one by one in the filter, the lines of a voided order stay active
+// and are counted with their order
+func (m *mysqlOrderItemRepository) FetchVoids(ctx context.Context, filter domain.VoidFilter) (res []domain.VoidEntry, err error) {
+	query := `SELECT orderItem.order_id, orderItem.id, orderItem.product_id, orderItem.qty,
+				orderItem.qty * IFNULL(orderItem.price, (SELECT price FROM outlet_products
+					WHERE outlet_products.product_id = orderItem.product_id and outlet_products.outlet_id = orders.outlet_id
+					ORDER BY outlet_products.id LIMIT 1)) amount,
+				orders.outlet_id, orders.user_id, orderItem.void_reason, orderItem.void_by, orderItem.void_at
+  				FROM orderItem join orders on orders.id = orderItem.order_id
+  				WHERE orderItem.status='void' and orderItem.void_at >= ? and orderItem.void_at < ?
+  				and (? = 0 or orders.outlet_id = ?) and (? = 0 or orders.user_id = ?)
+  				ORDER BY orderItem.void_at`
+
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, filter.From, filter.To,
+		filter.OutletID, filter.OutletID, filter.UserID, filter.UserID)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	res = make([]domain.VoidEntry, 0)
+	for rows.Next() {
+		t := domain.VoidEntry{}
+		amount := sql.NullFloat64{}
+		outletID := sql.NullInt64{}
+		userID := sql.NullInt64{}
+		err = rows.Scan(
+			&t.OrderID,
+			&t.OrderItemID,
+			&t.ProductID,
+			&t.Qty,
+			&amount,
+			&outletID,
+			&userID,
+			&t.Reason,
+			&t.VoidBy,
+			&t.VoidAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		t.Amount = domain.RoundMoney(amount.Float64)
+		t.OutletID = outletID.Int64
+		t.UserID = userID.Int64
+		res = append(res, t)
 	}
 
 	return
@@ -197,14 +341,14 @@ func (m *mysqlOrderItemRepository) Delete(ctx context.Context, id int64) (err er
 func (m *mysqlOrderItemRepository) Update(ctx context.Context, a *domain.RequestOrderItem) (err error) {
 
 	query := `UPDATE orderItem 
-			SET  qty=?, update_at=? 
+			SET  qty=?, discount=?, tax_apply=?, update_at=? 
 			 WHERE ID = ? `
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
-	res, err := stmt.ExecContext(ctx,a.Qty, time.Now(), a.ID)
+	res, err := stmt.ExecContext(ctx,a.Qty, a.Discount, a.TaxApply, time.Now(), a.ID)
 	if err != nil {
 		return
 	}
@@ -219,3 +363,8 @@ func (m *mysqlOrderItemRepository) Update(ctx context.Context, a *domain.Request
 
 	return
 }
+
+// nullID stores an empty foreign key as NULL instead of 0
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
@@ -2,8 +2,8 @@ package usecase
 
 import (
 	"context"
-	"fmt"
 	"mini_pos/constants"
+	"sort"
 	"time"
 
 	"mini_pos/domain"
@@ -13,15 +13,17 @@ type OrderItemUsecase struct {
 	OrderItemRepo    domain.OrderItemRepository
 	OrderRepo     domain.OrderRepository
 	ProductRepo     domain.ProductRepository
+	PaymentRepo     domain.PaymentRepository
 	contextTimeout time.Duration
 }
 
 // NewOrderItemUsecase will create new an OrderItemUsecase object representation of domain.OrderItemUsecase interface
-func NewOrderItemUsecase(a domain.OrderItemRepository, order domain.OrderRepository, prod domain.ProductRepository, timeout time.Duration) domain.OrderItemUsecase {
+func NewOrderItemUsecase(a domain.OrderItemRepository, order domain.OrderRepository, prod domain.ProductRepository, payment domain.PaymentRepository, timeout time.Duration) domain.OrderItemUsecase {
 	return &OrderItemUsecase{
 		OrderItemRepo:    a,
 		OrderRepo:     order,
 		ProductRepo: prod,
+		PaymentRepo: payment,
 		contextTimeout: timeout,
 	}
 }
@@ -49,7 +51,7 @@ func (a *OrderItemUsecase) fillOrderItemDetails(c context.Context, data []domain
 	return data, nil
 }
 
-func (a *OrderItemUsecase) Fetch(c context.Context, cursor string, num int64) (res []domain.OrderItem, nextCursor string, err error) {
+func (a *OrderItemUsecase) Fetch(c context.Context, cursor string, num int64, userId int64, outletId int64) (res []domain.OrderItem, nextCursor string, err error) {
 	if num == 0 {
 		num = 10
 	}
@@ -57,7 +59,7 @@ func (a *OrderItemUsecase) Fetch(c context.Context, cursor string, num int64) (r
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 
-	res, nextCursor, err = a.OrderItemRepo.Fetch(ctx, cursor, num)
+	res, nextCursor, err = a.OrderItemRepo.Fetch(ctx, cursor, num, userId, outletId)
 	if err != nil {
 		return nil, "", err
 	}
@@ -73,11 +75,45 @@ func (a *OrderItemUsecase) Fetch(c context.Context, cursor string, num int64) (r
 	return
 }
 
-func (a *OrderItemUsecase) FetchByOrderID(c context.Context, id int64) (res []domain.OrderItem, err error) {
+// getOwnedCart return the open order when it belongs to the given cashier
+func (a *OrderItemUsecase) getOwnedCart(ctx context.Context, id int64, userId int64) (res domain.Order, err error) {
+	res, err = a.OrderRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	if res.UserID != userId {
+		return domain.Order{}, domain.ErrNotFound
+	}
+	return
+}
+
+// getEditableCart return the open order of the cashier when no tender was taken against it yet,
+// a partly paid order is frozen so the amount due cannot change under the tenders
+func (a *OrderItemUsecase) getEditableCart(ctx context.Context, id int64, userId int64) (res domain.Order, err error) {
+	res, err = a.getOwnedCart(ctx, id, userId)
+	if err != nil {
+		return
+	}
+	tenders, err := a.PaymentRepo.FetchByOrderID(ctx, id)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if len(tenders) > 0 {
+		return domain.Order{}, constants.ErrOrderHasPayment
+	}
+	return
+}
+
+func (a *OrderItemUsecase) FetchByOrderID(c context.Context, id int64, userId int64) (res []domain.OrderItem, err error) {
 
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 
+	_, err = a.getOwnedCart(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
 	res, err = a.OrderItemRepo.FetchByOrderID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -91,7 +127,66 @@ func (a *OrderItemUsecase) FetchByOrderID(c context.Context, id int64) (res []do
 	return
 }
 
-func (a *OrderItemUsecase) GetByID(c context.Context, id int64) (res domain.OrderItem, err error) {
+func (a *OrderItemUsecase) FetchCarts(c context.Context, userId int64, outletId int64) (res []domain.Order, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err = a.OrderRepo.FetchOpen(ctx, userId, outletId)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) <= 0 {
+		return nil, constants.ErrDataNotFound
+	}
+	return
+}
+
+func (a *OrderItemUsecase) OpenCart(c context.Context, m *domain.RequestCart) (res domain.Order, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	if m.OutletID == 0 {
+		return domain.Order{}, constants.ErrParamsIsNotInvalid
+	}
+
+	res = domain.Order{
+		Customer: domain.Customer{
+			ID: m.CustomerID,
+		},
+		UserID:     m.UserID,
+		ActorID:    m.ActorID,
+		OutletID:   m.OutletID,
+		Label:      m.Label,
+		Tax:        m.Tax,
+		IsCheckout: 0,
+	}
+	_, err = a.OrderRepo.Store(ctx, &res)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	return
+}
+
+func (a *OrderItemUsecase) UpdateCart(c context.Context, id int64, m *domain.RequestCart) (res domain.Order, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err = a.getEditableCart(ctx, id, m.UserID)
+	if err != nil {
+		return
+	}
+	res.Customer = domain.Customer{
+		ID: m.CustomerID,
+	}
+	res.Label = m.Label
+	res.Tax = m.Tax
+	err = a.OrderRepo.Update(ctx, &res)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	return
+}
+
+func (a *OrderItemUsecase) GetByID(c context.Context, id int64, userId int64) (res domain.OrderItem, err error) {
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 
@@ -100,7 +195,7 @@ func (a *OrderItemUsecase) GetByID(c context.Context, id int64) (res domain.Orde
 		return
 	}
 
-	resOrder, err := a.OrderRepo.GetByID(ctx, res.Order.ID)
+	resOrder, err := a.getOwnedCart(ctx, res.Order.ID, userId)
 	if err != nil {
 		return domain.OrderItem{}, err
 	}
@@ -119,6 +214,19 @@ func (a *OrderItemUsecase) Update(c context.Context, ar *domain.RequestOrderItem
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 
+	existedOrderItem, err := a.OrderItemRepo.GetByID(ctx, ar.ID)
+	if err != nil {
+		return
+	}
+	if existedOrderItem.Status == domain.StatusVoid {
+		return constants.ErrOrderItemVoided
+	}
+	_, err = a.getEditableCart(ctx, existedOrderItem.Order.ID, ar.UserID)
+	if err != nil {
+		return
+	}
+	keepPricing(ar, existedOrderItem)
+
 	return a.OrderItemRepo.Update(ctx, ar)
 }
 
@@ -126,37 +234,122 @@ func (a *OrderItemUsecase) Update(c context.Context, ar *domain.RequestOrderItem
 func (a *OrderItemUsecase) Store(c context.Context, m *domain.RequestOrderItem) (err error) {
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
-	//check order id
-	id,err:= a.OrderRepo.GetStatus(ctx)
-
-	if err != nil {
-		id,err = a.OrderRepo.Store(ctx,&domain.Order{
-			IsCheckout: 0,
-		})
+	//check order id, a cashier may keep several parked carts so an explicit order wins
+	id := m.OrderID
+	if id != 0 {
+		var order domain.Order
+		order, err = a.getEditableCart(ctx, id, m.UserID)
+		if err != nil {
+			return
+		}
+		m.OutletID = order.OutletID
+	} else {
+		if m.OutletID == 0 {
+			return constants.ErrParamsIsNotInvalid
+		}
+		id, err = a.OrderRepo.GetStatus(ctx, m.UserID, m.OutletID)
+		if err != nil {
+			id, err = a.OrderRepo.Store(ctx, &domain.Order{
+				UserID:     m.UserID,
+				ActorID:    m.ActorID,
+				OutletID:   m.OutletID,
+				IsCheckout: 0,
+			})
+			if err != nil {
+				return
+			}
+		} else {
+			_, err = a.getEditableCart(ctx, id, m.UserID)
+			if err != nil {
+				return
+			}
+		}
 	}
 
+	m.OrderID = id
 	existedOrderItem, _ := a.OrderItemRepo.GetByOrderIDAndProdID(ctx, id, m.ProductID)
-	if existedOrderItem != (domain.OrderItem{}) {
+	if existedOrderItem.ID != 0 {
 		m.ID = existedOrderItem.ID
 		m.Qty += existedOrderItem.Qty
-		a.OrderItemRepo.Update(ctx,m)
-		return
+		keepPricing(m, existedOrderItem)
+		return a.OrderItemRepo.Update(ctx,m)
 	}
-	fmt.Println(id,err)
-	m.OrderID = id
 	err = a.OrderItemRepo.Store(ctx, m)
 	return
 }
 
-func (a *OrderItemUsecase) Delete(c context.Context, id int64) (err error) {
+func (a *OrderItemUsecase) Void(c context.Context, m *domain.RequestVoid) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+	if !m.Reason.Valid() {
+		return constants.ErrVoidReasonInvalid
+	}
+	existedOrderItem, err := a.OrderItemRepo.GetByID(ctx, m.ID)
+	if err != nil {
+		return
+	}
+	_, err = a.getEditableCart(ctx, existedOrderItem.Order.ID, m.UserID)
+	if err != nil {
+		return
+	}
+	return a.OrderItemRepo.Void(ctx, m.ID, m)
+}
+
+func (a *OrderItemUsecase) VoidCart(c context.Context, m *domain.RequestVoid) (err error) {
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
-	existedOrderItem, err := a.OrderItemRepo.GetByID(ctx, id)
+	if !m.Reason.Valid() {
+		return constants.ErrVoidReasonInvalid
+	}
+	order, err := a.getEditableCart(ctx, m.ID, m.UserID)
 	if err != nil {
 		return
 	}
-	if existedOrderItem == (domain.OrderItem{}) {
-		return domain.ErrNotFound
+	order.VoidReason = m.Reason
+	order.VoidBy = m.UserID
+	order.VoidActorID = m.ActorID
+	return a.OrderRepo.Void(ctx, &order)
+}
+
+// FetchVoids build the void report of the filter from the voided orders and the lines voided one by one
+func (a *OrderItemUsecase) FetchVoids(c context.Context, filter domain.VoidFilter) (res domain.VoidReport, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	orders, err := a.OrderRepo.FetchVoids(ctx, filter)
+	if err != nil {
+		return
+	}
+	lines, err := a.OrderItemRepo.FetchVoids(ctx, filter)
+	if err != nil {
+		return
+	}
+
+	res = domain.VoidReport{
+		From:     filter.From,
+		To:       filter.To,
+		Entries:  make([]domain.VoidEntry, 0),
+		ByReason: make(map[domain.VoidReason]domain.VoidTotal),
+		ByUser:   make(map[int64]domain.VoidTotal),
+	}
+	for _, e := range orders {
+		res.Add(e)
+	}
+	for _, e := range lines {
+		res.Add(e)
+	}
+	sort.SliceStable(res.Entries, func(i, j int) bool {
+		return res.Entries[i].VoidAt.Before(res.Entries[j].VoidAt)
+	})
+	return
+}
+
+// keepPricing keep the discount and tax of the line when the request does not send them
+func keepPricing(m *domain.RequestOrderItem, existed domain.OrderItem) {
+	if m.Discount == nil {
+		m.Discount = existed.Discount
+	}
+	if m.TaxApply == nil {
+		m.TaxApply = existed.TaxApply
 	}
-	return a.OrderItemRepo.Delete(ctx, id)
 }
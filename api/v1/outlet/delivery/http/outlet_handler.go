@@ -0,0 +1,311 @@
+package http
+
+import (
+	"mini_pos/api/common"
+	middlwr "mini_pos/api/middleware"
+	"mini_pos/config"
+	"mini_pos/constants"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"mini_pos/domain"
+)
+
+// OutletHandler  represent the httphandler for Outlet
+type OutletHandler struct {
+	AUsecase domain.OutletUsecase
+}
+
+// NewOutletHandler will initialize the outlets/ resources endpoint
+func NewOutletHandler(e *echo.Echo, us domain.OutletUsecase, cfg config.Config) {
+	handler := &OutletHandler{
+		AUsecase: us,
+	}
+	OutletV1 := e.Group("")
+
+	OutletV1.Use(middlwr.UserMiddleware(cfg))
+	OutletV1.GET("/me/outlets", handler.FetchMine)
+	OutletV1.GET("/outlets", handler.FetchOutlet, middlwr.Permission(domain.PermOutletManage))
+	OutletV1.POST("/outlets", handler.Store, middlwr.Permission(domain.PermOutletManage))
+	OutletV1.GET("/outlets/:id", handler.GetByID, middlwr.Permission(domain.PermOutletManage))
+	OutletV1.PUT("/outlets/:id", handler.Update, middlwr.Permission(domain.PermOutletManage))
+	OutletV1.POST("/outlets/:id/activate", handler.Activate, middlwr.Permission(domain.PermOutletManage))
+	OutletV1.POST("/outlets/:id/deactivate", handler.Deactivate, middlwr.Permission(domain.PermOutletManage))
+}
+
+func isRequestValid(m *domain.Outlet) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(m)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FetchMine will fetch the active outlets of the token holder, every active outlet for a user allowed on all of them
+func (a *OutletHandler) FetchMine(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	listAr, err := a.AUsecase.FetchMine(ctx, int64(claims.ID), claims.Can(domain.PermOutletAll))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(listAr))
+}
+
+// FetchOutlet will fetch every outlet, or the outlets of status when it is given
+func (a *OutletHandler) FetchOutlet(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(0) {
+		return middlwr.OutletForbidden(c)
+	}
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	status := c.QueryParam("status")
+	if status != "" && status != domain.OutletActive && status != domain.OutletInactive {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: domain.ErrBadParamInput.Error(),
+			Data:    nil,
+		}))
+	}
+	cursor := c.QueryParam("cursor")
+	ctx := c.Request().Context()
+
+	listAr, nextCursor, err := a.AUsecase.Fetch(ctx, cursor, int64(num), status)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(common.NewSuccessResponse(listAr))
+}
+
+// GetByID will get the outlet by given id
+func (a *OutletHandler) GetByID(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(int64(idP)) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	ctx := c.Request().Context()
+	outlet, err := a.AUsecase.GetByID(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(outlet))
+}
+
+// Store will open a new outlet by given request body, only a user allowed on all the outlets can
+func (a *OutletHandler) Store(c echo.Context) (err error) {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(0) {
+		return middlwr.OutletForbidden(c)
+	}
+	var outlet domain.Outlet
+	err = c.Bind(&outlet)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&outlet); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	err = a.AUsecase.Store(ctx, &outlet)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(outlet))
+}
+
+// Update will change the details of the outlet by given request body, its status is kept
+func (a *OutletHandler) Update(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(int64(idP)) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	var outlet domain.Outlet
+	err = c.Bind(&outlet)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&outlet); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	outlet.ID = int64(idP)
+	err = a.AUsecase.Update(ctx, &outlet)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(outlet))
+}
+
+// Activate will open again a deactivated outlet
+func (a *OutletHandler) Activate(c echo.Context) error {
+	return a.setStatus(c, domain.OutletActive)
+}
+
+// Deactivate will close the outlet, it is left out of the next tokens of its users
+func (a *OutletHandler) Deactivate(c echo.Context) error {
+	return a.setStatus(c, domain.OutletInactive)
+}
+
+func (a *OutletHandler) setStatus(c echo.Context, status string) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(int64(idP)) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	ctx := c.Request().Context()
+	outlet, err := a.AUsecase.SetStatus(ctx, int64(idP), status)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(outlet))
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound, constants.ErrDataNotFound:
+		return http.StatusNotFound
+	case domain.ErrConflict:
+		return http.StatusConflict
+	case domain.ErrBadParamInput:
+		return http.StatusBadRequest
+	case constants.ErrOutletTimezone:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
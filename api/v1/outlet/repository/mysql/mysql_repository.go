@@ -1,8 +1,14 @@
 package mysql
+
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"time"
+
 	"github.com/sirupsen/logrus"
+
+	"mini_pos/api/v1/outlet/repository"
 	"mini_pos/domain"
 )
 
@@ -17,22 +23,12 @@ func NewMysqlOutletRepository(db *sql.DB) domain.OutletRepository {
 	}
 }
 
-func (m *mysqlOutletRepo) getOne(ctx context.Context, query string, args ...interface{}) (res domain.Outlet, err error) {
-	stmt, err := m.DB.PrepareContext(ctx, query)
-	if err != nil {
-		return domain.Outlet{}, err
-	}
-	row := stmt.QueryRowContext(ctx, args...)
-	res = domain.Outlet{}
+const outletColumns = `outlets.id, outlets.name, outlets.address, outlets.phone, outlets.tax_id, outlets.timezone, outlets.currency,
+			outlets.receipt_header, outlets.receipt_footer, outlets.status, outlets.create_at, outlets.update_at`
 
-	err = row.Scan(
-		&res.ID,
-		&res.Name,
-		&res.Address,
-		&res.CreatedAt,
-		&res.UpdatedAt,
-	)
-	return
+// nullString store the empty optional fields of an outlet as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
 }
 
 func (m *mysqlOutletRepo) getAll(ctx context.Context, query string, args ...interface{}) (res []domain.Outlet, err error) {
@@ -52,10 +48,21 @@ func (m *mysqlOutletRepo) getAll(ctx context.Context, query string, args ...inte
 	result := make([]domain.Outlet, 0)
 	for rows.Next() {
 		t := domain.Outlet{}
+		phone := sql.NullString{}
+		taxID := sql.NullString{}
+		header := sql.NullString{}
+		footer := sql.NullString{}
 		err = rows.Scan(
 			&t.ID,
 			&t.Name,
 			&t.Address,
+			&phone,
+			&taxID,
+			&t.Timezone,
+			&t.Currency,
+			&header,
+			&footer,
+			&t.Status,
 			&t.CreatedAt,
 			&t.UpdatedAt,
 		)
@@ -64,25 +71,169 @@ func (m *mysqlOutletRepo) getAll(ctx context.Context, query string, args ...inte
 			logrus.Error(err)
 			return nil, err
 		}
+		t.Phone = phone.String
+		t.TaxID = taxID.String
+		t.ReceiptHeader = header.String
+		t.ReceiptFooter = footer.String
 		result = append(result, t)
 	}
 	res = result
+	return res, rows.Err()
+}
+
+func (m *mysqlOutletRepo) getOne(ctx context.Context, query string, args ...interface{}) (res domain.Outlet, err error) {
+	list, err := m.getAll(ctx, query, args...)
+	if err != nil {
+		return domain.Outlet{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+
 	return
 }
-func (m *mysqlOutletRepo) GetByID(ctx context.Context, id int64) (domain.Outlet, error) {
-	query := `SELECT id, name, address, create_at, update_at FROM outlets 
-			join user_outlets on user_outlets.outlet_id = outlets.id WHERE outlets.id =?`
-	return m.getOne(ctx, query,  id)
+
+// Fetch return every outlet, or only the outlets of the status when it is not empty
+func (m *mysqlOutletRepo) Fetch(ctx context.Context, cursor string, num int64, status string) (res []domain.Outlet, nextCursor string, err error) {
+	query := `SELECT ` + outletColumns + `
+  						FROM outlets WHERE create_at > ? and (? = '' or status = ?) ORDER BY create_at LIMIT ? `
+
+	decodedCursor, err := repository.DecodeCursor(cursor)
+	if err != nil && cursor != "" {
+		return nil, "", domain.ErrBadParamInput
+	}
+
+	res, err = m.getAll(ctx, query, decodedCursor, status, status, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(res) == int(num) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	}
+
+	return
+}
+
+func (m *mysqlOutletRepo) GetByID(ctx context.Context, id int64) (res domain.Outlet, err error) {
+	query := `SELECT ` + outletColumns + ` FROM outlets WHERE outlets.id =?`
+	return m.getOne(ctx, query, id)
 }
 
 func (m *mysqlOutletRepo) GetByUserID(ctx context.Context, id int64, userId int64) (domain.Outlet, error) {
-	query := `SELECT id, name, address, create_at, update_at FROM outlets 
+	query := `SELECT ` + outletColumns + ` FROM outlets
 			join user_outlets on user_outlets.outlet_id = outlets.id WHERE user_id=? and outlets.id =?`
-	return m.getOne(ctx, query,userId,  id)
+	return m.getOne(ctx, query, userId, id)
 }
 
+// GetByAll return the active outlets the user is assigned to in user_outlets
 func (m *mysqlOutletRepo) GetByAll(ctx context.Context, userId int64) ([]domain.Outlet, error) {
-	query := `SELECT id, name, address, create_at, update_at FROM outlets 
-			join user_outlets on user_outlets.outlet_id = outlets.id WHERE user_id=?`
-	return m.getAll(ctx, query, userId)
-}
\ No newline at end of file
+	query := `SELECT ` + outletColumns + ` FROM outlets
+			join user_outlets on user_outlets.outlet_id = outlets.id WHERE user_id=? and outlets.status=? ORDER BY outlets.id`
+	return m.getAll(ctx, query, userId, domain.OutletActive)
+}
+
+// FetchActive return every active outlet, for the users allowed on all the outlets
+func (m *mysqlOutletRepo) FetchActive(ctx context.Context) ([]domain.Outlet, error) {
+	query := `SELECT ` + outletColumns + ` FROM outlets WHERE outlets.status=? ORDER BY outlets.id`
+	return m.getAll(ctx, query, domain.OutletActive)
+}
+
+func (m *mysqlOutletRepo) FetchUserIDs(ctx context.Context, id int64) (res []int, err error) {
+	query := `SELECT user_id FROM user_outlets WHERE outlet_id = ? ORDER BY user_id`
+	rows, err := m.DB.QueryContext(ctx, query, id)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res = make([]int, 0)
+	for rows.Next() {
+		var userId int
+		err = rows.Scan(&userId)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, userId)
+	}
+	return res, rows.Err()
+}
+
+func (m *mysqlOutletRepo) Store(ctx context.Context, a *domain.Outlet) (err error) {
+	query := `INSERT  outlets
+			  SET name=?, address=?, phone=?, tax_id=?, timezone=?, currency=?, receipt_header=?, receipt_footer=?,
+			  status=?, update_at=? , create_at=?`
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, a.Name, a.Address, nullString(a.Phone), nullString(a.TaxID), a.Timezone, a.Currency,
+		nullString(a.ReceiptHeader), nullString(a.ReceiptFooter), a.Status, now, now)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	a.UpdatedAt = now
+	a.CreatedAt = now
+	return
+}
+
+// Update change the details of the outlet, its status is kept
+func (m *mysqlOutletRepo) Update(ctx context.Context, a *domain.Outlet) (err error) {
+	query := `UPDATE outlets SET name=?, address=?, phone=?, tax_id=?, timezone=?, currency=?, receipt_header=?, receipt_footer=?,
+			  update_at=? WHERE ID = ?`
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, a.Name, a.Address, nullString(a.Phone), nullString(a.TaxID), a.Timezone, a.Currency,
+		nullString(a.ReceiptHeader), nullString(a.ReceiptFooter), now, a.ID)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+		return
+	}
+	a.UpdatedAt = now
+	return
+}
+
+func (m *mysqlOutletRepo) SetStatus(ctx context.Context, id int64, status string) (err error) {
+	query := `UPDATE outlets SET status=?, update_at=? WHERE ID = ?`
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, status, time.Now(), id)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+		return
+	}
+	return
+}
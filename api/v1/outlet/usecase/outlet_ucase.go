@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"mini_pos/constants"
+	"mini_pos/domain"
+)
+
+// Defaults of the outlets stored without a timezone or a currency, as in the outlets table
+const (
+	defaultTimezone = "Asia/Jakarta"
+	defaultCurrency = "IDR"
+)
+
+type OutletUsecase struct {
+	OutletRepo     domain.OutletRepository
+	SessionRepo    domain.SessionRepository
+	contextTimeout time.Duration
+}
+
+// NewOutletUsecase will create new an OutletUsecase object representation of domain.OutletUsecase interface
+func NewOutletUsecase(a domain.OutletRepository, session domain.SessionRepository, timeout time.Duration) domain.OutletUsecase {
+	return &OutletUsecase{
+		OutletRepo:     a,
+		SessionRepo:    session,
+		contextTimeout: timeout,
+	}
+}
+
+func (a *OutletUsecase) Fetch(c context.Context, cursor string, num int64, status string) (res []domain.Outlet, nextCursor string, err error) {
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, nextCursor, err = a.OutletRepo.Fetch(ctx, cursor, num, status)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(res) <= 0 {
+		return nil, "", constants.ErrDataNotFound
+	}
+	return
+}
+
+func (a *OutletUsecase) GetByID(c context.Context, id int64) (res domain.Outlet, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.OutletRepo.GetByID(ctx, id)
+}
+
+// normalize fill the default timezone and currency, and refuse a timezone the receipts could not be printed in
+func normalize(m *domain.Outlet) error {
+	if m.Timezone == "" {
+		m.Timezone = defaultTimezone
+	}
+	if _, err := time.LoadLocation(m.Timezone); err != nil || m.Timezone == "Local" {
+		return constants.ErrOutletTimezone
+	}
+	m.Currency = strings.ToUpper(m.Currency)
+	if m.Currency == "" {
+		m.Currency = defaultCurrency
+	}
+	return nil
+}
+
+// Store open a new outlet, it starts active
+func (a *OutletUsecase) Store(c context.Context, m *domain.Outlet) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	err = normalize(m)
+	if err != nil {
+		return
+	}
+	m.Status = domain.OutletActive
+	return a.OutletRepo.Store(ctx, m)
+}
+
+// Update change the details of the outlet, its status is only changed by SetStatus
+func (a *OutletUsecase) Update(c context.Context, m *domain.Outlet) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	existed, err := a.OutletRepo.GetByID(ctx, m.ID)
+	if err != nil {
+		return
+	}
+	err = normalize(m)
+	if err != nil {
+		return
+	}
+	m.Status = existed.Status
+	m.CreatedAt = existed.CreatedAt
+	return a.OutletRepo.Update(ctx, m)
+}
+
+// SetStatus activate or deactivate the outlet, deactivating it also ends the sessions of its users
+func (a *OutletUsecase) SetStatus(c context.Context, id int64, status string) (res domain.Outlet, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if status != domain.OutletActive && status != domain.OutletInactive {
+		return domain.Outlet{}, domain.ErrBadParamInput
+	}
+	res, err = a.OutletRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	if res.Status == status {
+		return
+	}
+	err = a.OutletRepo.SetStatus(ctx, id, status)
+	if err != nil {
+		return domain.Outlet{}, err
+	}
+	res.Status = status
+
+	// the tokens already issued still carry the outlet, its users sign in again without it
+	if status == domain.OutletInactive {
+		userIds, errUsers := a.OutletRepo.FetchUserIDs(ctx, id)
+		if errUsers != nil {
+			return domain.Outlet{}, errUsers
+		}
+		now := time.Now()
+		for _, userId := range userIds {
+			err = a.SessionRepo.RevokeUser(ctx, userId, now)
+			if err != nil {
+				return domain.Outlet{}, err
+			}
+		}
+	}
+	return
+}
+
+// FetchMine return the active outlets of the user, every active outlet when the user is allowed on all of them
+func (a *OutletUsecase) FetchMine(c context.Context, userId int64, all bool) (res []domain.Outlet, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if all {
+		return a.OutletRepo.FetchActive(ctx)
+	}
+	return a.OutletRepo.GetByAll(ctx, userId)
+}
@@ -0,0 +1,50 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	_cache "mini_pos/api/cache"
+	"mini_pos/api/v1/auth/repository/cache"
+	"mini_pos/api/v1/outlet/usecase"
+	"mini_pos/domain"
+	"mini_pos/domain/fakes"
+)
+
+func TestSetStatusInactiveRevokesUsers(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakes.OutletRepository{
+		Outlets: map[int64]domain.Outlet{
+			2: {ID: 2, Name: "Pusat", Status: domain.OutletActive},
+			3: {ID: 3, Name: "Cabang", Status: domain.OutletActive},
+		},
+		Users: map[int64][]int{2: {7, 8}, 3: {9}},
+	}
+	session := cache.NewCacheSessionRepository(_cache.NewMemoryCache())
+	u := usecase.NewOutletUsecase(repo, session, time.Second)
+
+	res, err := u.SetStatus(ctx, 2, domain.OutletInactive)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OutletInactive, res.Status)
+	assert.Equal(t, domain.OutletInactive, repo.Outlets[2].Status)
+
+	// the users of the outlet sign in again, the users of the other outlet keep their sessions
+	for _, userId := range []int{7, 8} {
+		at, err := session.GetUserRevokedAt(ctx, userId)
+		assert.NoError(t, err)
+		assert.NotZero(t, at)
+	}
+	at, err := session.GetUserRevokedAt(ctx, 9)
+	assert.NoError(t, err)
+	assert.Zero(t, at)
+
+	// activating it again does not revoke anything
+	_, err = u.SetStatus(ctx, 3, domain.OutletInactive)
+	assert.NoError(t, err)
+	_, err = u.SetStatus(ctx, 3, domain.OutletActive)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OutletActive, repo.Outlets[3].Status)
+}
@@ -33,11 +33,12 @@ func NewPaymentHandler(e *echo.Echo, us domain.PaymentUsecase, cfg config.Config
 	PaymentV1:= e.Group("")
 
 	PaymentV1.Use(middlwr.JWTMiddleware(cfg))
-	PaymentV1.POST("/payment", handler.Payment)
-	PaymentV1.GET("/payment/customer", handler.GetByCustomers)
-	PaymentV1.GET("/payment/customer/:id", handler.GetByCustomerID)
-	PaymentV1.GET("/payment/product", handler.GetByProducts)
-	PaymentV1.GET("/payment/product/:id", handler.GetByProductID)
+	PaymentV1.POST("/payment", handler.Payment, middlwr.Permission(domain.PermSell))
+	PaymentV1.GET("/payment/order/:id", handler.GetByOrderID, middlwr.Permission(domain.PermSell))
+	PaymentV1.GET("/payment/customer", handler.GetByCustomers, middlwr.Permission(domain.PermReportRead))
+	PaymentV1.GET("/payment/customer/:id", handler.GetByCustomerID, middlwr.Permission(domain.PermReportRead))
+	PaymentV1.GET("/payment/product", handler.GetByProducts, middlwr.Permission(domain.PermReportRead))
+	PaymentV1.GET("/payment/product/:id", handler.GetByProductID, middlwr.Permission(domain.PermReportRead))
 }
 
 func isRequestValid(m *domain.RequestPayment) (bool, error) {
@@ -59,13 +60,6 @@ func (a *PaymentHandler) Payment(c echo.Context) (err error) {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	var Payment domain.RequestPayment
 	err = c.Bind(&Payment)
 	if err != nil {
@@ -85,8 +79,15 @@ func (a *PaymentHandler) Payment(c echo.Context) (err error) {
 		}))
 	}
 
+	// an order given by id is checked against its owner
+	if Payment.Order == 0 && !claims.HasOutlet(Payment.OutletID) {
+		return middlwr.OutletForbidden(c)
+	}
+
 	ctx := c.Request().Context()
-	err = a.AUsecase.Payment(ctx, Payment)
+	Payment.UserID = int64(claims.ID)
+	Payment.ActorID = int64(claims.ActorID)
+	res, err := a.AUsecase.Payment(ctx, Payment)
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
 			Code:    getStatusCode(err),
@@ -95,11 +96,11 @@ func (a *PaymentHandler) Payment(c echo.Context) (err error) {
 		}))
 	}
 
-	return c.JSON(common.NewSuccessResponse(Payment))
+	return c.JSON(common.NewSuccessResponse(res))
 }
 
-// GetByID will get Purchase by given id
-func (a *PaymentHandler) GetByCustomerID(c echo.Context) error {
+// GetByOrderID will get the bill and the tenders of an order of the logged in cashier
+func (a *PaymentHandler) GetByOrderID(c echo.Context) error {
 	claims := middlwr.GetTokenFromContext(c)
 	if claims == nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
@@ -109,7 +110,32 @@ func (a *PaymentHandler) GetByCustomerID(c echo.Context) error {
 		}))
 	}
 
-	if claims.RoleID != 1 {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+	ctx := c.Request().Context()
+
+	bill, err := a.AUsecase.GetByOrderID(ctx, int64(idP), int64(claims.ID))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(bill))
+}
+
+// GetByID will get Purchase by given id
+func (a *PaymentHandler) GetByCustomerID(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
 			Code:    http.StatusUnauthorized,
 			Message: constants.ErrTokenAlreadyExpired.Error(),
@@ -148,14 +174,6 @@ func (a *PaymentHandler) GetByCustomers(c echo.Context) error {
 		}))
 	}
 
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrTokenAlreadyExpired.Error(),
-			Data:    nil,
-		}))
-	}
-
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		idP=0
@@ -187,14 +205,6 @@ func (a *PaymentHandler) GetByProductID(c echo.Context) error {
 		}))
 	}
 
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrTokenAlreadyExpired.Error(),
-			Data:    nil,
-		}))
-	}
-
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		idP=0
@@ -226,14 +236,6 @@ func (a *PaymentHandler) GetByProducts(c echo.Context) error {
 		}))
 	}
 
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrTokenAlreadyExpired.Error(),
-			Data:    nil,
-		}))
-	}
-
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		idP=0
@@ -267,6 +269,11 @@ func getStatusCode(err error) int {
 		return http.StatusNotFound
 	case domain.ErrConflict:
 		return http.StatusConflict
+	case constants.ErrQuantityOutOfStock, constants.ErrOrderNotOpen, constants.ErrShiftNotOpen:
+		return http.StatusConflict
+	case constants.ErrOrderEmpty, constants.ErrProductNotInOutlet, constants.ErrPaymentExceedTotal,
+		constants.ErrPaymentMethodInvalid, constants.ErrPaymentReferenceRequired:
+		return http.StatusBadRequest
 	default:
 		return http.StatusInternalServerError
 	}
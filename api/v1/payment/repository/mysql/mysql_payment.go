@@ -7,6 +7,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"time"
 
+	"mini_pos/api/transaction"
 	"mini_pos/domain"
 )
 
@@ -21,14 +22,16 @@ func NewMysqlPaymentRepository(Conn *sql.DB) domain.PaymentRepository {
 
 func (m *mysqlPaymentRepository) Payment(ctx context.Context, a domain.RequestPayment) (err error) {
 	query := `INSERT  payments 
-			  SET order_id =?, tax=?, type_payment=?, total_payment=?, update_at=?, create_at=?`
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+			  SET order_id =?, tax=?, type_payment=?, reference=?, total_payment=?, paid=?, change_due=?, amount=?, shift_id=?, actor_id=?,
+			  update_at=?, create_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
 
 	res, err := stmt.ExecContext(ctx,
-		a.Order, a.Tax,a.TypePayment, a.TotalPayment, time.Now(), time.Now())
+		a.Order, a.Tax,a.TypePayment, nullString(a.Reference), a.TotalPayment, a.Paid, a.Change, a.Amount, nullID(a.ShiftID), nullID(a.ActorID),
+		time.Now(), time.Now())
 	if err != nil {
 		return
 	}
@@ -40,6 +43,56 @@ func (m *mysqlPaymentRepository) Payment(ctx context.Context, a domain.RequestPa
 	return
 }
 
+func (m *mysqlPaymentRepository) FetchByOrderID(ctx context.Context, orderId int64) (res []domain.Payment, err error) {
+	query := `SELECT id, order_id, tax, type_payment, reference, total_payment, paid, change_due, amount, shift_id, update_at, create_at
+  				FROM payments WHERE order_id = ? ORDER BY id`
+
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, orderId)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	res = make([]domain.Payment, 0)
+	for rows.Next() {
+		t := domain.Payment{}
+		tax := sql.NullFloat64{}
+		reference := sql.NullString{}
+		shiftID := sql.NullInt64{}
+		err = rows.Scan(
+			&t.ID,
+			&t.Order,
+			&tax,
+			&t.TypePayment,
+			&reference,
+			&t.TotalPayment,
+			&t.Paid,
+			&t.Change,
+			&t.Amount,
+			&shiftID,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		t.Tax = tax.Float64
+		t.Reference = reference.String
+		t.ShiftID = shiftID.Int64
+		res = append(res, t)
+	}
+
+	return
+}
+
 func (m *mysqlPaymentRepository) PaymentByCustomer(ctx context.Context, id int64) (res []domain.PaymentCustomer,err error) {
 	var sQuery string
 	if id !=0 {
@@ -50,7 +103,7 @@ func (m *mysqlPaymentRepository) PaymentByCustomer(ctx context.Context, id int64
   				FROM orders join orderItem on orderItem.order_id = orders.id join products on products.id = orderItem.product_id  join customers p on p.id = orders.customer_id  
 				%s order by orders.customer_id `,sQuery)
 
-	rows, err := m.Conn.QueryContext(ctx, query)
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query)
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
@@ -92,7 +145,7 @@ func (m *mysqlPaymentRepository) PaymentByProduct(ctx context.Context, id int64)
   				FROM orders join orderItem on orderItem.order_id = orders.id join products on products.id = orderItem.product_id   
 				%s order by orderItem.product_id `,sQuery)
 
-	rows, err := m.Conn.QueryContext(ctx, query)
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query)
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
@@ -123,4 +176,13 @@ func (m *mysqlPaymentRepository) PaymentByProduct(ctx context.Context, id int64)
 	}
 
 	return
-}
\ No newline at end of file
+}
+// nullID stores an empty foreign key as NULL instead of 0
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
+
+// nullString stores an empty string as NULL
+func nullString(v string) sql.NullString {
+	return sql.NullString{String: v, Valid: v != ""}
+}
@@ -2,7 +2,6 @@ package usecase
 
 import (
 	"context"
-	"errors"
 	"mini_pos/constants"
 	"time"
 
@@ -11,41 +10,202 @@ import (
 
 type PaymentUsecase struct {
 	OrderRepo    domain.OrderRepository
+	OrderItemRepo    domain.OrderItemRepository
+	ProductOutletsRepo    domain.ProductOutletsRepository
 	PaymentRepo    domain.PaymentRepository
+	ShiftRepo    domain.ShiftRepository
+	Transactor    domain.Transactor
 	contextTimeout time.Duration
 }
 
 // NewPaymentUsecase will create new an PaymentUsecase object representation of domain.PaymentUsecase interface
-func NewPaymentUsecase(order domain.OrderRepository, a domain.PaymentRepository, timeout time.Duration) domain.PaymentUsecase {
+func NewPaymentUsecase(order domain.OrderRepository, item domain.OrderItemRepository, prodOutlet domain.ProductOutletsRepository, a domain.PaymentRepository, shift domain.ShiftRepository, tx domain.Transactor, timeout time.Duration) domain.PaymentUsecase {
 	return &PaymentUsecase{
 		OrderRepo:    order,
+		OrderItemRepo:    item,
+		ProductOutletsRepo:    prodOutlet,
 		PaymentRepo:    a,
+		ShiftRepo:    shift,
+		Transactor:    tx,
 		contextTimeout: timeout,
 	}
 }
 
 
-func (a *PaymentUsecase) Payment(c context.Context, m domain.RequestPayment) (err error) {
+// bill compute the order amount from the order lines and the outlet prices
+func (a *PaymentUsecase) bill(ctx context.Context, order domain.Order) (res domain.OrderBill, err error) {
+	lines, err := a.OrderItemRepo.FetchBillLines(ctx, order.ID, order.OutletID)
+	if err != nil {
+		return
+	}
+	res = domain.OrderBill{
+		OrderID: order.ID,
+		Lines:   lines,
+		TaxRate: order.Tax,
+	}
+	res.Calculate()
+	return
+}
+
+// getOwnedOrder return the open order of the cashier, or its latest cart in the outlet when id is empty
+func (a *PaymentUsecase) getOwnedOrder(ctx context.Context, id int64, userId int64, outletId int64) (res domain.Order, err error) {
+	if id == 0 {
+		id, err = a.OrderRepo.GetStatus(ctx, userId, outletId)
+		if err != nil {
+			return domain.Order{}, constants.ErrOrderEmpty
+		}
+	}
+	res, err = a.OrderRepo.GetByID(ctx, id)
+	if err != nil || res.UserID != userId {
+		return domain.Order{}, constants.ErrOrderEmpty
+	}
+	return
+}
+
+// result sum the tenders taken against the bill
+func result(bill domain.OrderBill, tenders []domain.Payment) (res domain.PaymentResult) {
+	res = domain.PaymentResult{
+		OrderBill: bill,
+		Tenders:   tenders,
+	}
+	for _, t := range tenders {
+		res.Paid += t.Amount
+		res.Change += t.Change
+	}
+	res.Paid = domain.RoundMoney(res.Paid)
+	res.Change = domain.RoundMoney(res.Change)
+	res.Due = domain.RoundMoney(bill.Total - res.Paid)
+	if res.Due <= 0 {
+		res.Due = 0
+		res.IsPaid = true
+	}
+	return
+}
+
+// GetByOrderID return the bill of an order of the cashier with the tenders taken so far
+func (a *PaymentUsecase) GetByOrderID(c context.Context, orderId int64, userId int64) (res domain.PaymentResult, err error) {
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
-	//check payment ammount must be same
-	id,err:= a.OrderRepo.GetStatus(ctx)
+
+	order, err := a.OrderRepo.GetAnyByID(ctx, orderId)
+	if err != nil {
+		return
+	}
+	if order.UserID != userId {
+		return domain.PaymentResult{}, domain.ErrNotFound
+	}
+
+	bill, err := a.bill(ctx, order)
+	if err != nil {
+		return
+	}
+	tenders, err := a.PaymentRepo.FetchByOrderID(ctx, order.ID)
 	if err != nil {
-		err = errors.New("error: cannot checkout order empty")
 		return
 	}
-	m.Order = id
-	err = a.OrderRepo.Update(ctx, &domain.Order{
-						ID:         m.Order,
-						Customer:   domain.Customer{
-							ID:        m.CustomerID,
-						},
-						IsCheckout: 1,
-					})
+	return result(bill, tenders), nil
+}
+
+// Payment take one tender against an order, the order is closed and its stock reserved
+// once the tenders cover the total
+func (a *PaymentUsecase) Payment(c context.Context, m domain.RequestPayment) (res domain.PaymentResult, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if !m.TypePayment.Valid() {
+		return domain.PaymentResult{}, constants.ErrPaymentMethodInvalid
+	}
+	if m.TypePayment != domain.PaymentMethodCash && m.Reference == "" {
+		return domain.PaymentResult{}, constants.ErrPaymentReferenceRequired
+	}
+
+	order, err := a.getOwnedOrder(ctx, m.Order, m.UserID, m.OutletID)
 	if err != nil {
 		return
 	}
-	err = a.PaymentRepo.Payment(ctx, m)
+	m.Order = order.ID
+
+	// the tender, closing the order and reserving the stock of every line succeed or fail together
+	err = a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// serialize tenders of the same order so two tills cannot both settle the amount due
+		err := a.OrderRepo.Lock(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+		order, err = a.OrderRepo.GetByID(ctx, order.ID)
+		if err != nil {
+			return constants.ErrOrderNotOpen
+		}
+
+		bill, err := a.bill(ctx, order)
+		if err != nil {
+			return err
+		}
+		tenders, err := a.PaymentRepo.FetchByOrderID(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+		due := result(bill, tenders).Due
+
+		//only cash may be paid above the amount due, the rest is given back as change
+		if m.TypePayment != domain.PaymentMethodCash && m.Paid > due {
+			return constants.ErrPaymentExceedTotal
+		}
+		m.Change = 0
+		if m.Paid > due {
+			m.Change = domain.RoundMoney(m.Paid - due)
+		}
+		m.Amount = domain.RoundMoney(m.Paid - m.Change)
+		m.Tax = bill.Tax
+		m.TotalPayment = bill.Total
+
+		//the tender is counted in the open shift of the cashier, cash is only taken into an open drawer
+		shift, err := a.ShiftRepo.GetOpen(ctx, m.UserID, order.OutletID)
+		if err == domain.ErrNotFound && m.TypePayment == domain.PaymentMethodCash {
+			return constants.ErrShiftNotOpen
+		}
+		if err != nil && err != domain.ErrNotFound {
+			return err
+		}
+		m.ShiftID = shift.ID
+
+		err = a.PaymentRepo.Payment(ctx, m)
+		if err != nil {
+			return err
+		}
+
+		if m.Amount >= due {
+			if m.CustomerID != 0 {
+				order.Customer = domain.Customer{
+					ID: m.CustomerID,
+				}
+			}
+			err = a.OrderRepo.Checkout(ctx, &order)
+			if err != nil {
+				return err
+			}
+			for _, line := range bill.Lines {
+				err = a.OrderItemRepo.SetPrice(ctx, line.OrderItemID, line.Price)
+				if err != nil {
+					return err
+				}
+				err = a.ProductOutletsRepo.ReserveStock(ctx, line.ProductID, order.OutletID, line.Qty)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		tenders, err = a.PaymentRepo.FetchByOrderID(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+		res = result(bill, tenders)
+		return nil
+	})
+	if err != nil {
+		return domain.PaymentResult{}, err
+	}
 	return
 }
 
@@ -33,11 +33,12 @@ func NewProductHandler(e *echo.Echo, us domain.ProductUsecase, cfg config.Config
 	ProductV1:= e.Group("")
 
 	ProductV1.Use(middlwr.JWTMiddleware(cfg))
-	ProductV1.GET("/products", handler.FetchProduct)
-	ProductV1.POST("/products", handler.Store)
-	ProductV1.PUT("/products", handler.Update)
-	ProductV1.GET("/products/:id", handler.GetByID)
-	ProductV1.DELETE("/products/:id", handler.Delete)
+	ProductV1.GET("/products", handler.FetchProduct, middlwr.Permission(domain.PermProductRead))
+	ProductV1.POST("/products", handler.Store, middlwr.Permission(domain.PermProductWrite))
+	ProductV1.PUT("/products", handler.Update, middlwr.Permission(domain.PermProductWrite))
+	ProductV1.GET("/products/:id", handler.GetByID, middlwr.Permission(domain.PermProductRead))
+	ProductV1.DELETE("/products/:id", handler.Delete, middlwr.Permission(domain.PermProductWrite))
+	ProductV1.PUT("/products/:id/options", handler.SetOptions, middlwr.Permission(domain.PermProductWrite))
 }
 
 // FetchProduct will fetch the Product based on given params
@@ -100,7 +101,72 @@ func (a *ProductHandler) GetByID(c echo.Context) error {
 		}))
 	}
 
-	return c.JSON(common.NewSuccessResponse(prod))
+	return c.JSON(common.NewSuccessResponse(outletStocks(claims, prod)))
+}
+
+// outletStocks leave out the stock of the variants in the outlets the token holder is not assigned to
+func outletStocks(claims *middlwr.JwtCustomClaims, prod domain.Product) domain.Product {
+	for i, v := range prod.Variants {
+		stocks := make([]domain.VariantStock, 0, len(v.Stocks))
+		for _, st := range v.Stocks {
+			if claims.HasOutlet(st.OutletID) {
+				stocks = append(stocks, st)
+			}
+		}
+		prod.Variants[i].Stocks = stocks
+	}
+	return prod
+}
+
+// SetOptions will replace the options of the Product and generate its variants, one for each combination of
+// the option values. An empty list of options removes the variants
+func (a *ProductHandler) SetOptions(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var request domain.RequestProductOptions
+	err = c.Bind(&request)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	err = validator.New().Struct(&request)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	prod, err := a.AUsecase.SetOptions(ctx, int64(idP), request.Options)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(outletStocks(claims, prod)))
 }
 
 func (a *ProductHandler) GetBySKU(c echo.Context) error {
@@ -148,13 +214,6 @@ func (a *ProductHandler) Update(c echo.Context) (err error) {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	var Product domain.RequestProduct
 	err = c.Bind(&Product)
 	if err != nil {
@@ -198,13 +257,6 @@ func (a *ProductHandler) Store(c echo.Context) (err error) {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	var Product domain.RequestProduct
 	err = c.Bind(&Product)
 	if err != nil {
@@ -247,13 +299,6 @@ func (a *ProductHandler) Delete(c echo.Context) error {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
@@ -289,8 +334,10 @@ func getStatusCode(err error) int {
 		return http.StatusInternalServerError
 	case domain.ErrNotFound:
 		return http.StatusNotFound
-	case domain.ErrConflict:
+	case domain.ErrConflict, constants.ErrVariantInUse:
 		return http.StatusConflict
+	case constants.ErrVariantOptions:
+		return http.StatusUnprocessableEntity
 	default:
 		return http.StatusInternalServerError
 	}
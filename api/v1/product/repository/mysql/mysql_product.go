@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -87,6 +88,36 @@ func (m *mysqlProductRepository) Fetch(ctx context.Context, cursor string, num i
 	return
 }
 
+// FetchByCategory return the products of any of the categories
+func (m *mysqlProductRepository) FetchByCategory(ctx context.Context, cursor string, num int64, categoryIds []int64) (res []domain.Product, nextCursor string, err error) {
+	if len(categoryIds) == 0 {
+		return make([]domain.Product, 0), "", nil
+	}
+	query := `SELECT id,name, description, color, size,unit,image, category_id, update_at, create_at
+  						FROM products WHERE  create_at > ? and category_id IN (` + strings.Repeat(",?", len(categoryIds))[1:] + `)
+  						ORDER BY create_at LIMIT ? `
+
+	decodedCursor, err := repository.DecodeCursor(cursor)
+	if err != nil && cursor != "" {
+		return nil, "", domain.ErrBadParamInput
+	}
+
+	args := []interface{}{decodedCursor}
+	for _, id := range categoryIds {
+		args = append(args, id)
+	}
+	res, err = m.fetch(ctx, query, append(args, num)...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(res) == int(num) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	}
+
+	return
+}
+
 func (m *mysqlProductRepository) GetByID(ctx context.Context, id int64) (res domain.Product, err error) {
 
 	query := `SELECT id,name, description, color, size, unit,image, category_id, update_at, create_at
@@ -0,0 +1,213 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/api/transaction"
+	"mini_pos/domain"
+)
+
+type mysqlProductVariantRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlProductVariantRepository will create an object that represent the domain.ProductVariantRepository interface
+func NewMysqlProductVariantRepository(Conn *sql.DB) domain.ProductVariantRepository {
+	return &mysqlProductVariantRepository{Conn}
+}
+
+// FetchOptions return the option names of the product by position, their values are read from the variants
+func (m *mysqlProductVariantRepository) FetchOptions(ctx context.Context, productId int64) (res []domain.ProductOption, err error) {
+	query := `SELECT id, position, name FROM product_options WHERE product_id = ? ORDER BY position`
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, productId)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res = make([]domain.ProductOption, 0)
+	for rows.Next() {
+		t := domain.ProductOption{}
+		err = rows.Scan(&t.ID, &t.Position, &t.Name)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
+
+// ReplaceOptions store the options of the product in place of the previous ones, position follows the order
+func (m *mysqlProductVariantRepository) ReplaceOptions(ctx context.Context, productId int64, options []domain.ProductOption) (err error) {
+	conn := transaction.Conn(ctx, m.Conn)
+	_, err = conn.ExecContext(ctx, `DELETE FROM product_options WHERE product_id = ?`, productId)
+	if err != nil {
+		return
+	}
+
+	query := `INSERT  product_options SET product_id=?, position=?, name=?, update_at=? , create_at=?`
+	stmt, err := conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	for i := range options {
+		options[i].Position = i + 1
+		res, errExec := stmt.ExecContext(ctx, productId, options[i].Position, options[i].Name, now, now)
+		if errExec != nil {
+			return errExec
+		}
+		options[i].ID, err = res.LastInsertId()
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (m *mysqlProductVariantRepository) fetchVariants(ctx context.Context, query string, args ...interface{}) (result []domain.ProductVariant, err error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.ProductVariant, 0)
+	for rows.Next() {
+		t := domain.ProductVariant{}
+		options := make([]string, domain.MaxProductOptions)
+		err = rows.Scan(
+			&t.ID,
+			&t.ProductID,
+			&t.Name,
+			&options[0],
+			&options[1],
+			&options[2],
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		for len(options) > 0 && options[len(options)-1] == "" {
+			options = options[:len(options)-1]
+		}
+		t.Options = options
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+func (m *mysqlProductVariantRepository) FetchVariants(ctx context.Context, productId int64) ([]domain.ProductVariant, error) {
+	query := `SELECT id, product_id, name, option1, option2, option3, update_at, create_at
+  						FROM product_variants WHERE product_id = ? ORDER BY id`
+	return m.fetchVariants(ctx, query, productId)
+}
+
+func (m *mysqlProductVariantRepository) GetVariantByID(ctx context.Context, id int64) (res domain.ProductVariant, err error) {
+	query := `SELECT id, product_id, name, option1, option2, option3, update_at, create_at
+  						FROM product_variants WHERE id = ?`
+	list, err := m.fetchVariants(ctx, query, id)
+	if err != nil {
+		return domain.ProductVariant{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
+
+func (m *mysqlProductVariantRepository) StoreVariant(ctx context.Context, a *domain.ProductVariant) (err error) {
+	query := `INSERT  product_variants
+			  SET product_id=?, name=?, option1=?, option2=?, option3=?, update_at=? , create_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	options := make([]string, domain.MaxProductOptions)
+	copy(options, a.Options)
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, a.ProductID, a.Name, options[0], options[1], options[2], now, now)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	a.UpdatedAt = now
+	a.CreatedAt = now
+	return
+}
+
+func (m *mysqlProductVariantRepository) DeleteVariant(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM product_variants WHERE id = ? "
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAfected != 1 {
+		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", rowsAfected)
+		return
+	}
+
+	return
+}
+
+// FetchStocks return the outlet_products rows of every variant of the product
+func (m *mysqlProductVariantRepository) FetchStocks(ctx context.Context, productId int64) (res []domain.VariantStock, err error) {
+	query := `SELECT id, variant_id, outlet_id, sku, price, quantity, IFNULL(quantity_use,0)
+  						FROM outlet_products WHERE product_id = ? and variant_id is not null ORDER BY outlet_id, id`
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, productId)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res = make([]domain.VariantStock, 0)
+	for rows.Next() {
+		t := domain.VariantStock{}
+		err = rows.Scan(&t.ID, &t.VariantID, &t.OutletID, &t.Sku, &t.Price, &t.QuantityStock, &t.QuantityUse)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
@@ -11,14 +11,18 @@ import (
 type productUsecase struct {
 	productRepo    domain.ProductRepository
 	catRepo     domain.CategoryRepository
+	variantRepo    domain.ProductVariantRepository
+	transactor     domain.Transactor
 	contextTimeout time.Duration
 }
 
 // NewproductUsecase will create new an productUsecase object representation of domain.productUsecase interface
-func NewproductUsecase(a domain.ProductRepository, cat domain.CategoryRepository, timeout time.Duration) domain.ProductUsecase {
+func NewproductUsecase(a domain.ProductRepository, cat domain.CategoryRepository, variant domain.ProductVariantRepository, transactor domain.Transactor, timeout time.Duration) domain.ProductUsecase {
 	return &productUsecase{
 		productRepo:    a,
 		catRepo:     cat,
+		variantRepo:    variant,
+		transactor:     transactor,
 		contextTimeout: timeout,
 	}
 }
@@ -30,13 +34,7 @@ func (a *productUsecase) fillProductDetails(c context.Context, data []domain.Pro
 		if product.Category.ID != 0 {
 			cat, err := a.catRepo.GetByID(c, product.Category.ID)
 			if err == nil {
-				data[index].Category = domain.Category{
-					ID:          cat.ID,
-					Name:        cat.Name,
-					Description: cat.Description,
-					UpdatedAt:   cat.UpdatedAt,
-					CreatedAt:   cat.CreatedAt,
-				}
+				data[index].Category = cat
 			}
 		}
 	}
@@ -84,6 +82,10 @@ func (a *productUsecase) GetByID(c context.Context, id int64) (res domain.Produc
 	}
 	res.Category = resCategory
 
+	err = a.fillVariants(ctx, &res)
+	if err != nil {
+		return domain.Product{}, err
+	}
 	return
 }
 
@@ -115,7 +117,7 @@ func (a *productUsecase) Store(c context.Context, m *domain.RequestProduct) (err
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 	existedproduct, _ := a.GetByName(ctx, m.Name)
-	if existedproduct != (domain.Product{}) {
+	if existedproduct.ID != 0 {
 		return domain.ErrConflict
 	}
 	err = a.productRepo.Store(ctx, m)
@@ -129,7 +131,7 @@ func (a *productUsecase) Delete(c context.Context, id int64) (err error) {
 	if err != nil {
 		return
 	}
-	if existedproduct == (domain.Product{}) {
+	if existedproduct.ID == 0 {
 		return domain.ErrNotFound
 	}
 	return a.productRepo.Delete(ctx, id)
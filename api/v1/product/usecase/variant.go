@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"mini_pos/constants"
+	"mini_pos/domain"
+)
+
+// maxVariants keep the matrix of a product small enough to be stocked outlet by outlet
+const maxVariants = 100
+
+// fillVariants add the options and the variants of the product, with the stock of each variant in the outlets.
+// The values of an option are those of the variants, in the order they were generated
+func (a *productUsecase) fillVariants(ctx context.Context, p *domain.Product) (err error) {
+	options, err := a.variantRepo.FetchOptions(ctx, p.ID)
+	if err != nil || len(options) == 0 {
+		return
+	}
+	variants, err := a.variantRepo.FetchVariants(ctx, p.ID)
+	if err != nil {
+		return
+	}
+	stocks, err := a.variantRepo.FetchStocks(ctx, p.ID)
+	if err != nil {
+		return
+	}
+
+	index := make(map[int64]int, len(variants))
+	for i, v := range variants {
+		index[v.ID] = i
+		variants[i].Stocks = make([]domain.VariantStock, 0)
+		for pos := range options {
+			if pos < len(v.Options) && !contains(options[pos].Values, v.Options[pos]) {
+				options[pos].Values = append(options[pos].Values, v.Options[pos])
+			}
+		}
+	}
+	for _, st := range stocks {
+		if i, ok := index[st.VariantID]; ok {
+			variants[i].Stocks = append(variants[i].Stocks, st)
+		}
+	}
+	p.Options = options
+	p.Variants = variants
+	return
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOptions trim the names and values of the options, and refuse duplicates and a matrix too large
+func checkOptions(options []domain.ProductOption) error {
+	if len(options) > domain.MaxProductOptions {
+		return constants.ErrVariantOptions
+	}
+	total := 1
+	names := make(map[string]bool, len(options))
+	for i := range options {
+		options[i].Name = strings.TrimSpace(options[i].Name)
+		name := strings.ToLower(options[i].Name)
+		if name == "" || names[name] || len(options[i].Values) == 0 {
+			return constants.ErrVariantOptions
+		}
+		names[name] = true
+
+		values := make(map[string]bool, len(options[i].Values))
+		for j, v := range options[i].Values {
+			v = strings.TrimSpace(v)
+			if v == "" || values[strings.ToLower(v)] {
+				return constants.ErrVariantOptions
+			}
+			values[strings.ToLower(v)] = true
+			options[i].Values[j] = v
+		}
+		total *= len(options[i].Values)
+		if total > maxVariants {
+			return constants.ErrVariantOptions
+		}
+	}
+	return nil
+}
+
+// combinations return every combination of one value of each option, the last option varying first
+func combinations(options []domain.ProductOption) [][]string {
+	if len(options) == 0 {
+		return nil
+	}
+	res := [][]string{{}}
+	for _, o := range options {
+		next := make([][]string, 0, len(res)*len(o.Values))
+		for _, c := range res {
+			for _, v := range o.Values {
+				combination := append(append(make([]string, 0, len(c)+1), c...), v)
+				next = append(next, combination)
+			}
+		}
+		res = next
+	}
+	return res
+}
+
+// SetOptions replace the options of the product and generate a variant for each combination of their values.
+// The variants of a combination kept are left as they are with their stock, the others are removed unless an
+// outlet still stocks them. No options leave the product without variants
+func (a *productUsecase) SetOptions(c context.Context, id int64, options []domain.ProductOption) (res domain.Product, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	err = checkOptions(options)
+	if err != nil {
+		return
+	}
+	_, err = a.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+
+	err = a.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		existed, errTx := a.variantRepo.FetchVariants(ctx, id)
+		if errTx != nil {
+			return errTx
+		}
+		stocks, errTx := a.variantRepo.FetchStocks(ctx, id)
+		if errTx != nil {
+			return errTx
+		}
+		stocked := make(map[int64]bool, len(stocks))
+		for _, st := range stocks {
+			stocked[st.VariantID] = true
+		}
+
+		wanted := combinations(options)
+		keep := make(map[string]bool, len(existed))
+		for _, combination := range wanted {
+			keep[strings.Join(combination, "\x00")] = true
+		}
+		found := make(map[string]bool, len(existed))
+		for _, v := range existed {
+			key := strings.Join(v.Options, "\x00")
+			if keep[key] {
+				found[key] = true
+				continue
+			}
+			if stocked[v.ID] {
+				return constants.ErrVariantInUse
+			}
+			errTx = a.variantRepo.DeleteVariant(ctx, v.ID)
+			if errTx != nil {
+				return errTx
+			}
+		}
+		for _, combination := range wanted {
+			if found[strings.Join(combination, "\x00")] {
+				continue
+			}
+			errTx = a.variantRepo.StoreVariant(ctx, &domain.ProductVariant{
+				ProductID: id,
+				Name:      strings.Join(combination, " / "),
+				Options:   combination,
+			})
+			if errTx != nil {
+				return errTx
+			}
+		}
+		return a.variantRepo.ReplaceOptions(ctx, id, options)
+	})
+	if err != nil {
+		return
+	}
+	return a.GetByID(c, id)
+}
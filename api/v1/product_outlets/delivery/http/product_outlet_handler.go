@@ -33,12 +33,12 @@ func NewProductOutletsHandler(e *echo.Echo, us domain.ProductOutletsUsecase, cfg
 	ProductOutletsV1:= e.Group("")
 
 	ProductOutletsV1.Use(middlwr.JWTMiddleware(cfg))
-	ProductOutletsV1.GET("/product_outlets", handler.FetchProductOutlets)
-	ProductOutletsV1.POST("/product_outlets", handler.Store)
-	ProductOutletsV1.PUT("/product_outlets", handler.Update)
-	ProductOutletsV1.PUT("/product_outlets/stock", handler.UpdateStock)
-	ProductOutletsV1.GET("/product_outlets/:id", handler.GetByID)
-	ProductOutletsV1.DELETE("/product_outlets/:id", handler.Delete)
+	ProductOutletsV1.GET("/product_outlets", handler.FetchProductOutlets, middlwr.Permission(domain.PermProductOutletRead))
+	ProductOutletsV1.POST("/product_outlets", handler.Store, middlwr.Permission(domain.PermProductOutletWrite))
+	ProductOutletsV1.PUT("/product_outlets", handler.Update, middlwr.Permission(domain.PermProductOutletWrite))
+	ProductOutletsV1.PUT("/product_outlets/stock", handler.UpdateStock, middlwr.Permission(domain.PermStockUpdate))
+	ProductOutletsV1.GET("/product_outlets/:id", handler.GetByID, middlwr.Permission(domain.PermProductOutletRead))
+	ProductOutletsV1.DELETE("/product_outlets/:id", handler.Delete, middlwr.Permission(domain.PermProductOutletWrite))
 }
 
 // FetchProductOutlets will fetch the ProductOutlets based on given params
@@ -60,6 +60,9 @@ func (a *ProductOutletsHandler) FetchProductOutlets(c echo.Context) error {
 			Data:    nil,
 		}))
 	}
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
+	}
 	numS := c.QueryParam("num")
 	num, _ := strconv.Atoi(numS)
 	cursor := c.QueryParam("cursor")
@@ -110,6 +113,9 @@ func (a *ProductOutletsHandler) GetByID(c echo.Context) error {
 			Data:    nil,
 		}))
 	}
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
+	}
 
 	prod, err := a.AUsecase.GetByID(ctx, id, int64(outlet))
 	if err != nil {
@@ -168,13 +174,6 @@ func (a *ProductOutletsHandler) Update(c echo.Context) (err error) {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	var ProductOutlets domain.RequestProductOutlets
 	err = c.Bind(&ProductOutlets)
 	if err != nil {
@@ -194,8 +193,11 @@ func (a *ProductOutletsHandler) Update(c echo.Context) (err error) {
 		}))
 	}
 
+	if !claims.HasOutlet(ProductOutlets.OutletID) {
+		return middlwr.OutletForbidden(c)
+	}
+
 	ctx := c.Request().Context()
-	ProductOutlets.OutletID = int64(claims.RoleID)
 	err = a.AUsecase.Update(ctx, &ProductOutlets)
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
@@ -238,6 +240,10 @@ func (a *ProductOutletsHandler) UpdateStock(c echo.Context) (err error) {
 		}))
 	}
 
+	if !claims.HasOutlet(int64(ProductOutlets.OutletID)) {
+		return middlwr.OutletForbidden(c)
+	}
+
 	ctx := c.Request().Context()
 	err = a.AUsecase.UpdateStock(ctx, ProductOutlets)
 	if err != nil {
@@ -261,13 +267,6 @@ func (a *ProductOutletsHandler) Store(c echo.Context) (err error) {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	var ProductOutlets domain.RequestProductOutlets
 	err = c.Bind(&ProductOutlets)
 	if err != nil {
@@ -287,6 +286,10 @@ func (a *ProductOutletsHandler) Store(c echo.Context) (err error) {
 		}))
 	}
 
+	if !claims.HasOutlet(ProductOutlets.OutletID) {
+		return middlwr.OutletForbidden(c)
+	}
+
 	ctx := c.Request().Context()
 	err = a.AUsecase.Store(ctx, &ProductOutlets)
 	if err != nil {
@@ -310,13 +313,6 @@ func (a *ProductOutletsHandler) Delete(c echo.Context) error {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
@@ -326,10 +322,16 @@ func (a *ProductOutletsHandler) Delete(c echo.Context) error {
 		}))
 	}
 
+	// without an outlet param the product is looked up in every outlet
+	outlet, _ := strconv.Atoi(c.QueryParam("outlet"))
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
+	}
+
 	id := int64(idP)
 	ctx := c.Request().Context()
 
-	err = a.AUsecase.Delete(ctx, id, int64(claims.RoleID))
+	err = a.AUsecase.Delete(ctx, id, int64(outlet))
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
 			Code:    getStatusCode(err),
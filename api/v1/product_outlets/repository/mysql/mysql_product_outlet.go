@@ -3,13 +3,14 @@ package mysql
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"mini_pos/api/v1/product_outlets/repository"
+	"mini_pos/api/transaction"
+	"mini_pos/constants"
 	"mini_pos/domain"
 )
 
@@ -23,7 +24,7 @@ func NewMysqlProductOutletsRepository(Conn *sql.DB) domain.ProductOutletsReposit
 }
 
 func (m *mysqlProductOutletsRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.ProductOutlets, err error) {
-	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
@@ -42,6 +43,7 @@ func (m *mysqlProductOutletsRepository) fetch(ctx context.Context, query string,
 		productID := int64(0)
 		outletID := int64(0)
 		supplierID := int64(0)
+		variantID := sql.NullInt64{}
 		err = rows.Scan(
 			&t.ID,
 			&productID,
@@ -51,6 +53,7 @@ func (m *mysqlProductOutletsRepository) fetch(ctx context.Context, query string,
 			&t.QuantityUse,
 			&outletID,
 			&supplierID,
+			&variantID,
 			&t.UpdatedAt,
 			&t.CreatedAt,
 		)
@@ -68,6 +71,7 @@ func (m *mysqlProductOutletsRepository) fetch(ctx context.Context, query string,
 		t.Supplier =domain.Supplier{
 			ID: supplierID,
 		}
+		t.VariantID = variantID.Int64
 		result = append(result, t)
 	}
 
@@ -80,7 +84,7 @@ func (m *mysqlProductOutletsRepository) Fetch(ctx context.Context, cursor string
 		outletQ = fmt.Sprintf("outlet_id=%d and",outlet)
 	}
 	fmt.Println(outletQ)
-	query := fmt.Sprintf(`SELECT id,product_id, sku,price, quantity,quantity_use,outlet_id,supplier_id, update_at, create_at
+	query := fmt.Sprintf(`SELECT id,product_id, sku,price, quantity,quantity_use,outlet_id,supplier_id, variant_id, update_at, create_at
   						FROM outlet_products WHERE %s create_at > ? ORDER BY create_at LIMIT ? `,outletQ)
 
 	decodedCursor, err := repository.DecodeCursor(cursor)
@@ -105,7 +109,7 @@ func (m *mysqlProductOutletsRepository) GetByID(ctx context.Context, id int64, o
 	if outlet !=0 {
 		outletQ = fmt.Sprintf("and outlet_id=%d",outlet)
 	}
-	query := fmt.Sprintf( `SELECT id,product_id, sku,price, quantity,quantity_use,outlet_id,supplier_id, update_at, create_at
+	query := fmt.Sprintf( `SELECT id,product_id, sku,price, quantity,quantity_use,outlet_id,supplier_id, variant_id, update_at, create_at
   						FROM outlet_products WHERE ID = ? %s`,outletQ)
 
 	list, err := m.fetch(ctx, query, id)
@@ -123,7 +127,7 @@ func (m *mysqlProductOutletsRepository) GetByID(ctx context.Context, id int64, o
 }
 
 func (m *mysqlProductOutletsRepository) GetBySku(ctx context.Context, sku string) (res domain.ProductOutlets, err error) {
-	query := `SELECT id,product_id, sku,price, quantity,quantity_use,outlet_id,supplier_id, update_at, create_at
+	query := `SELECT id,product_id, sku,price, quantity,quantity_use,outlet_id,supplier_id, variant_id, update_at, create_at
   						FROM outlet_products WHERE sku = ?`
 
 	list, err := m.fetch(ctx, query, sku)
@@ -140,16 +144,17 @@ func (m *mysqlProductOutletsRepository) GetBySku(ctx context.Context, sku string
 }
 
 func (m *mysqlProductOutletsRepository) Store(ctx context.Context, a *domain.RequestProductOutlets) (err error) {
-	query := `INSERT  product_outlets 
-			  SET sku=? ,  price=?, quantity=?, outlet_id=?, supplier_id=?,
+	query := `INSERT  outlet_products 
+			  SET product_id=?, sku=? ,  price=?, quantity=?, outlet_id=?, supplier_id=?, variant_id=?,
 			  update_at=? , create_at=?`
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
 
 	res, err := stmt.ExecContext(ctx,
-		a.Sku,  a.Price, a.QuantityStock,a.OutletID,a.SupplierID, time.Now(), time.Now())
+		a.ProductID, a.Sku,  a.Price, a.QuantityStock,a.OutletID,a.SupplierID,
+		sql.NullInt64{Int64: a.VariantID, Valid: a.VariantID != 0}, time.Now(), time.Now())
 	if err != nil {
 		return
 	}
@@ -164,7 +169,7 @@ func (m *mysqlProductOutletsRepository) Store(ctx context.Context, a *domain.Req
 func (m *mysqlProductOutletsRepository) Delete(ctx context.Context, id int64) (err error) {
 	query := "DELETE FROM outlet_products WHERE id = ? "
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
@@ -197,7 +202,7 @@ func (m *mysqlProductOutletsRepository) Update(ctx context.Context, a *domain.Re
 			  update_at=?
 			 WHERE ID = ? %s`,outletQ)
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
@@ -229,7 +234,7 @@ func (m *mysqlProductOutletsRepository) UpdateStock(ctx context.Context, stock d
 			  update_at=?
 			 WHERE id = ? %s`,outletQ)
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
@@ -240,7 +245,7 @@ func (m *mysqlProductOutletsRepository) UpdateStock(ctx context.Context, stock d
 	}
 
 	if st.QuantityStock >= *st.QuantityUse {
-		err = errors.New("error: Quantity out of stock")
+		err = constants.ErrQuantityOutOfStock
 		return
 	}
 
@@ -259,4 +264,59 @@ func (m *mysqlProductOutletsRepository) UpdateStock(ctx context.Context, stock d
 	}
 
 	return
-}
\ No newline at end of file
+}
+// ReserveStock add qty to quantity_use of the product in the outlet only when enough stock is left
+func (m *mysqlProductOutletsRepository) ReserveStock(ctx context.Context, productId int64, outletId int64, qty float64) (err error) {
+	query := `UPDATE outlet_products 
+			SET  quantity_use= IFNULL(quantity_use,0)+?,
+			  update_at=?
+			 WHERE product_id = ? and outlet_id = ? and quantity - IFNULL(quantity_use,0) >= ?
+			 ORDER BY id LIMIT 1`
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, qty, time.Now(), productId, outletId, qty)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		return constants.ErrQuantityOutOfStock
+	}
+
+	return
+}
+
+// ReleaseStock give qty back from quantity_use of the product in the outlet, used when goods are returned
+func (m *mysqlProductOutletsRepository) ReleaseStock(ctx context.Context, productId int64, outletId int64, qty float64) (err error) {
+	query := `UPDATE outlet_products 
+			SET  quantity_use= IFNULL(quantity_use,0)-?,
+			  update_at=?
+			 WHERE product_id = ? and outlet_id = ? and IFNULL(quantity_use,0) >= ?
+			 ORDER BY id LIMIT 1`
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, qty, time.Now(), productId, outletId, qty)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		return constants.ErrProductNotInOutlet
+	}
+
+	return
+}
@@ -11,16 +11,18 @@ import (
 type ProductOutletsUsecase struct {
 	ProductOutletsRepo    domain.ProductOutletsRepository
 	productRepo    domain.ProductRepository
+	variantRepo    domain.ProductVariantRepository
 	outletRepo     domain.OutletRepository
 	supRepo domain.SupplierRepository
 	contextTimeout time.Duration
 }
 
 // NewProductOutletsUsecase will create new an ProductOutletsUsecase object representation of domain.ProductOutletsUsecase interface
-func NewProductOutletsUsecase(a domain.ProductOutletsRepository, prod domain.ProductRepository,out domain.OutletRepository, sup domain.SupplierRepository, timeout time.Duration) domain.ProductOutletsUsecase {
+func NewProductOutletsUsecase(a domain.ProductOutletsRepository, prod domain.ProductRepository, variant domain.ProductVariantRepository, out domain.OutletRepository, sup domain.SupplierRepository, timeout time.Duration) domain.ProductOutletsUsecase {
 	return &ProductOutletsUsecase{
 		ProductOutletsRepo:    a,
 		productRepo:     prod,
+		variantRepo:     variant,
 		outletRepo:     out,
 		supRepo: sup,
 		contextTimeout: timeout,
@@ -148,13 +150,30 @@ func (a *ProductOutletsUsecase) GetBySku(c context.Context, sku string) (res dom
 	return
 }
 
+// Store add the product to the outlet under a new SKU, a variant gets its own SKU, price and stock
 func (a *ProductOutletsUsecase) Store(c context.Context, m *domain.RequestProductOutlets) (err error) {
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 	existedProductOutlets, _ := a.GetBySku(ctx, m.Sku)
-	if existedProductOutlets != (domain.ProductOutlets{}) {
+	if existedProductOutlets.ID != 0 {
 		return domain.ErrConflict
 	}
+	if m.VariantID != 0 {
+		variant, errVariant := a.variantRepo.GetVariantByID(ctx, m.VariantID)
+		if errVariant != nil {
+			return errVariant
+		}
+		if m.ProductID == 0 {
+			m.ProductID = variant.ProductID
+		}
+		if variant.ProductID != m.ProductID {
+			return constants.ErrVariantProduct
+		}
+	}
+	_, err = a.productRepo.GetByID(ctx, m.ProductID)
+	if err != nil {
+		return
+	}
 	err = a.ProductOutletsRepo.Store(ctx, m)
 	return
 }
@@ -166,7 +185,7 @@ func (a *ProductOutletsUsecase) Delete(c context.Context, id int64, outlet int64
 	if err != nil {
 		return
 	}
-	if existedProductOutlets == (domain.ProductOutlets{}) {
+	if existedProductOutlets.ID == 0 {
 		return domain.ErrNotFound
 	}
 	return a.ProductOutletsRepo.Delete(ctx, id)
@@ -34,15 +34,15 @@ func NewPurchaseHandler(e *echo.Echo, us domain.PurchaseUsecase, cfg config.Conf
 	PurchaseV1:= e.Group("")
 
 	PurchaseV1.Use(middlwr.JWTMiddleware(cfg))
-	PurchaseV1.GET("/purchases", handler.FetchPurchase)
-	PurchaseV1.GET("/purchases/supplier", handler.GetBySuppliers)
-	PurchaseV1.GET("/purchases/supplier/:id", handler.GetBySupplierID)
-	PurchaseV1.GET("/purchases/product", handler.GetByProducts)
-	PurchaseV1.GET("/purchases/product/:id", handler.GetByProductID)
-	PurchaseV1.POST("/purchases", handler.Store)
-	PurchaseV1.PUT("/purchases", handler.Update)
-	PurchaseV1.GET("/purchases/:id", handler.GetByID)
-	PurchaseV1.DELETE("/purchases/:id", handler.Delete)
+	PurchaseV1.GET("/purchases", handler.FetchPurchase, middlwr.Permission(domain.PermPurchaseRead))
+	PurchaseV1.GET("/purchases/supplier", handler.GetBySuppliers, middlwr.Permission(domain.PermPurchaseRead))
+	PurchaseV1.GET("/purchases/supplier/:id", handler.GetBySupplierID, middlwr.Permission(domain.PermPurchaseRead))
+	PurchaseV1.GET("/purchases/product", handler.GetByProducts, middlwr.Permission(domain.PermPurchaseRead))
+	PurchaseV1.GET("/purchases/product/:id", handler.GetByProductID, middlwr.Permission(domain.PermPurchaseRead))
+	PurchaseV1.POST("/purchases", handler.Store, middlwr.Permission(domain.PermPurchaseWrite))
+	PurchaseV1.PUT("/purchases", handler.Update, middlwr.Permission(domain.PermPurchaseWrite))
+	PurchaseV1.GET("/purchases/:id", handler.GetByID, middlwr.Permission(domain.PermPurchaseRead))
+	PurchaseV1.DELETE("/purchases/:id", handler.Delete, middlwr.Permission(domain.PermPurchaseWrite))
 }
 
 // FetchPurchase will fetch the Purchase based on given params
@@ -55,20 +55,17 @@ func (a *PurchaseHandler) FetchPurchase(c echo.Context) error {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrTokenAlreadyExpired.Error(),
-			Data:    nil,
-		}))
+	// without an outlet param the purchases of every outlet are listed
+	outlet, _ := strconv.Atoi(c.QueryParam("outlet"))
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
 	}
-
 	numS := c.QueryParam("num")
 	num, _ := strconv.Atoi(numS)
 	cursor := c.QueryParam("cursor")
 	ctx := c.Request().Context()
 
-	listAr, nextCursor, err := a.AUsecase.Fetch(ctx, cursor, int64(num))
+	listAr, nextCursor, err := a.AUsecase.Fetch(ctx, cursor, int64(num), int64(outlet))
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
 			Code:    getStatusCode(err),
@@ -92,14 +89,6 @@ func (a *PurchaseHandler) GetByID(c echo.Context) error {
 		}))
 	}
 
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrTokenAlreadyExpired.Error(),
-			Data:    nil,
-		}))
-	}
-
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
@@ -120,6 +109,9 @@ func (a *PurchaseHandler) GetByID(c echo.Context) error {
 			Data:    nil,
 		}))
 	}
+	if !claims.HasOutlet(prod.Outlet.ID) {
+		return middlwr.OutletForbidden(c)
+	}
 
 	return c.JSON(common.NewSuccessResponse(prod))
 }
@@ -135,20 +127,16 @@ func (a *PurchaseHandler) GetBySupplierID(c echo.Context) error {
 		}))
 	}
 
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrTokenAlreadyExpired.Error(),
-			Data:    nil,
-		}))
-	}
-
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		idP=0
 	}
 	fmt.Println(idP)
 	id := int64(idP)
+	// the report covers every outlet
+	if !claims.HasOutlet(0) {
+		return middlwr.OutletForbidden(c)
+	}
 	ctx := c.Request().Context()
 
 	prod, err := a.AUsecase.GetBySupplierID(ctx, id)
@@ -174,12 +162,9 @@ func (a *PurchaseHandler) GetBySuppliers(c echo.Context) error {
 		}))
 	}
 
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrTokenAlreadyExpired.Error(),
-			Data:    nil,
-		}))
+	// the report covers every outlet
+	if !claims.HasOutlet(0) {
+		return middlwr.OutletForbidden(c)
 	}
 	ctx := c.Request().Context()
 
@@ -206,19 +191,15 @@ func (a *PurchaseHandler) GetByProductID(c echo.Context) error {
 		}))
 	}
 
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrTokenAlreadyExpired.Error(),
-			Data:    nil,
-		}))
-	}
-
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		idP=0
 	}
 	id := int64(idP)
+	// the report covers every outlet
+	if !claims.HasOutlet(0) {
+		return middlwr.OutletForbidden(c)
+	}
 	ctx := c.Request().Context()
 
 	prod, err := a.AUsecase.GetByProductID(ctx, id)
@@ -244,12 +225,9 @@ func (a *PurchaseHandler) GetByProducts(c echo.Context) error {
 		}))
 	}
 
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrTokenAlreadyExpired.Error(),
-			Data:    nil,
-		}))
+	// the report covers every outlet
+	if !claims.HasOutlet(0) {
+		return middlwr.OutletForbidden(c)
 	}
 	ctx := c.Request().Context()
 
@@ -284,13 +262,6 @@ func (a *PurchaseHandler) Update(c echo.Context) (err error) {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	var Purchase domain.RequestPurchase
 	err = c.Bind(&Purchase)
 	if err != nil {
@@ -311,7 +282,18 @@ func (a *PurchaseHandler) Update(c echo.Context) (err error) {
 	}
 
 	ctx := c.Request().Context()
-	Purchase.OutletID = int64(claims.RoleID)
+	existed, err := a.AUsecase.GetByID(ctx, Purchase.ID)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(existed.Outlet.ID) || !claims.HasOutlet(Purchase.OutletID) {
+		return middlwr.OutletForbidden(c)
+	}
+
 	err = a.AUsecase.Update(ctx, &Purchase)
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
@@ -334,13 +316,6 @@ func (a *PurchaseHandler) Store(c echo.Context) (err error) {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	var Purchase domain.RequestPurchase
 	err = c.Bind(&Purchase)
 	if err != nil {
@@ -360,6 +335,10 @@ func (a *PurchaseHandler) Store(c echo.Context) (err error) {
 		}))
 	}
 
+	if !claims.HasOutlet(Purchase.OutletID) {
+		return middlwr.OutletForbidden(c)
+	}
+
 	ctx := c.Request().Context()
 	err = a.AUsecase.Store(ctx, &Purchase)
 	if err != nil {
@@ -383,13 +362,6 @@ func (a *PurchaseHandler) Delete(c echo.Context) error {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
@@ -402,6 +374,18 @@ func (a *PurchaseHandler) Delete(c echo.Context) error {
 	id := int64(idP)
 	ctx := c.Request().Context()
 
+	existed, err := a.AUsecase.GetByID(ctx, id)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(existed.Outlet.ID) {
+		return middlwr.OutletForbidden(c)
+	}
+
 	err = a.AUsecase.Delete(ctx, id)
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
@@ -79,17 +79,17 @@ func (m *mysqlPurchaseRepository) fetch(ctx context.Context, query string, args
 	return result, nil
 }
 
-func (m *mysqlPurchaseRepository) Fetch(ctx context.Context, cursor string, num int64) (res []domain.Purchase, nextCursor string, err error) {
+func (m *mysqlPurchaseRepository) Fetch(ctx context.Context, cursor string, num int64, outlet int64) (res []domain.Purchase, nextCursor string, err error) {
 
 	query := `SELECT id,product_id, cost_price, quantity, total_cost_price, discount, tax, category_id, outlet_id,supplier_id, update_at, create_at
-  						FROM purchases WHERE create_at > ? ORDER BY create_at LIMIT ? `
+  						FROM purchases WHERE (? = 0 or outlet_id = ?) and create_at > ? ORDER BY create_at LIMIT ? `
 
 	decodedCursor, err := repository.DecodeCursor(cursor)
 	if err != nil && cursor != "" {
 		return nil, "", domain.ErrBadParamInput
 	}
 
-	res, err = m.fetch(ctx, query, decodedCursor, num)
+	res, err = m.fetch(ctx, query, outlet, outlet, decodedCursor, num)
 	if err != nil {
 		return nil, "", err
 	}
@@ -68,7 +68,7 @@ func (a *PurchaseUsecase) fillPurchaseDetails(c context.Context, data []domain.P
 }
 
 
-func (a *PurchaseUsecase) Fetch(c context.Context, cursor string, num int64) (res []domain.Purchase, nextCursor string, err error) {
+func (a *PurchaseUsecase) Fetch(c context.Context, cursor string, num int64, outlet int64) (res []domain.Purchase, nextCursor string, err error) {
 	if num == 0 {
 		num = 10
 	}
@@ -76,7 +76,7 @@ func (a *PurchaseUsecase) Fetch(c context.Context, cursor string, num int64) (re
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 
-	res, nextCursor, err = a.PurchaseRepo.Fetch(ctx, cursor, num)
+	res, nextCursor, err = a.PurchaseRepo.Fetch(ctx, cursor, num, outlet)
 	if err != nil {
 		return nil, "", err
 	}
@@ -167,7 +167,7 @@ func (a *PurchaseUsecase) Store(c context.Context, m *domain.RequestPurchase) (e
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 	existedPurchase, _ := a.GetByID(ctx, m.ID)
-	if existedPurchase != (domain.Purchase{}) {
+	if existedPurchase.ID != 0 {
 		return domain.ErrConflict
 	}
 	err = a.PurchaseRepo.Store(ctx, m)
@@ -181,7 +181,7 @@ func (a *PurchaseUsecase) Delete(c context.Context, id int64) (err error) {
 	if err != nil {
 		return
 	}
-	if existedPurchase == (domain.Purchase{}) {
+	if existedPurchase.ID == 0 {
 		return domain.ErrNotFound
 	}
 	return a.PurchaseRepo.Delete(ctx, id)
@@ -0,0 +1,226 @@
+package http
+
+import (
+	"fmt"
+	"mini_pos/api/common"
+	middlwr "mini_pos/api/middleware"
+	"mini_pos/config"
+	"mini_pos/constants"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"mini_pos/domain"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// ReceiptHandler  represent the httphandler for Receipt
+type ReceiptHandler struct {
+	AUsecase domain.ReceiptUsecase
+}
+
+// contentTypes of the receipt formats
+var contentTypes = map[domain.ReceiptFormat]string{
+	domain.ReceiptFormatText:   echo.MIMETextPlainCharsetUTF8,
+	domain.ReceiptFormatEscPos: echo.MIMEOctetStream,
+	domain.ReceiptFormatPDF:    "application/pdf",
+}
+
+// NewReceiptHandler will initialize the receipts/ resources endpoint
+func NewReceiptHandler(e *echo.Echo, us domain.ReceiptUsecase, cfg config.Config) {
+	handler := &ReceiptHandler{
+		AUsecase: us,
+	}
+	ReceiptV1:= e.Group("")
+
+	ReceiptV1.Use(middlwr.JWTMiddleware(cfg))
+	ReceiptV1.GET("/receipts/order/:id", handler.Render, middlwr.Permission(domain.PermSell, domain.PermReceiptReprint))
+	ReceiptV1.GET("/receipts/template/:outlet", handler.GetTemplate, middlwr.Permission(domain.PermReceiptTemplate))
+	ReceiptV1.PUT("/receipts/template/:outlet", handler.UpdateTemplate, middlwr.Permission(domain.PermReceiptTemplate))
+}
+
+func isRequestValid(m *domain.ReceiptTemplate) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(m)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Render will render the receipt of a paid order in the format param (text, escpos or pdf)
+func (a *ReceiptHandler) Render(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+	format := domain.ReceiptFormat(c.QueryParam("format"))
+	if format == "" {
+		format = domain.ReceiptFormatText
+	}
+
+	// a supervisor may reprint the receipt of any cashier
+	userId := int64(claims.ID)
+	if claims.Can(domain.PermReceiptReprint) {
+		userId = 0
+	}
+	ctx := c.Request().Context()
+
+	// the receipt of an order is only printed by a user of its outlet
+	outlet, err := a.AUsecase.OrderOutlet(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(outlet) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	res, err := a.AUsecase.Render(ctx, int64(idP), userId, format)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	if format == domain.ReceiptFormatPDF {
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`inline; filename="receipt-%d.pdf"`, idP))
+	}
+	return c.Blob(http.StatusOK, contentTypes[format], res)
+}
+
+// GetTemplate will get the receipt template of an outlet
+func (a *ReceiptHandler) GetTemplate(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	outlet, err := strconv.Atoi(c.Param("outlet"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
+	}
+	ctx := c.Request().Context()
+
+	tpl, err := a.AUsecase.GetTemplate(ctx, int64(outlet))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(tpl))
+}
+
+// UpdateTemplate will set the receipt template of an outlet by given request body
+func (a *ReceiptHandler) UpdateTemplate(c echo.Context) (err error) {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	outlet, err := strconv.Atoi(c.Param("outlet"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+	var tpl domain.ReceiptTemplate
+	err = c.Bind(&tpl)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&tpl); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	tpl.OutletID = int64(outlet)
+	ctx := c.Request().Context()
+	err = a.AUsecase.UpdateTemplate(ctx, &tpl)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(tpl))
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrConflict:
+		return http.StatusConflict
+	case constants.ErrOrderNotPaid:
+		return http.StatusConflict
+	case constants.ErrReceiptFormatInvalid, constants.ErrReceiptTemplateInvalid:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,25 @@
+package render
+
+import (
+	"bytes"
+)
+
+// ESC/POS commands understood by most thermal receipt printers
+var (
+	escInit = []byte{0x1B, 0x40}       // ESC @ reset the printer
+	escFeed = []byte{0x1B, 0x64, 0x04} // ESC d 4 feed 4 lines so the paper clears the cutter
+	escCut  = []byte{0x1D, 0x56, 0x01} // GS V 1 partial cut
+)
+
+// EscPos wrap a text receipt with the printer commands, lines are sent as they are with LF endings
+func EscPos(text []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(escInit)
+	buf.Write(bytes.ReplaceAll(text, []byte("\r\n"), []byte("\n")))
+	if !bytes.HasSuffix(text, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	buf.Write(escFeed)
+	buf.Write(escCut)
+	return buf.Bytes()
+}
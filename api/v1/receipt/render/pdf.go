@@ -0,0 +1,61 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	pdfFontSize   = 9
+	pdfCharWidth  = 5.4 // Courier glyphs are 600/1000 of the font size wide
+	pdfLineHeight = 11
+	pdfMargin     = 14
+)
+
+// PDF lay a text receipt out on a single page as wide as the receipt, using the built in Courier font
+// so no font has to be embedded
+func PDF(text []byte, width int) []byte {
+	if width == 0 {
+		width = DefaultWidth
+	}
+	lines := strings.Split(strings.TrimRight(string(text), "\n"), "\n")
+	pageWidth := float64(width)*pdfCharWidth + 2*pdfMargin
+	pageHeight := float64(len(lines)*pdfLineHeight + 2*pdfMargin)
+
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %.2f Td\n", pdfFontSize, pdfLineHeight, pdfMargin, pageHeight-pdfMargin)
+	for _, l := range lines {
+		fmt.Fprintf(&content, "(%s) '\n", pdfEscape(l))
+	}
+	content.WriteString("ET\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>", pageWidth, pageHeight),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// pdfEscape escape the characters that end or break a PDF string
+func pdfEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`, "\r", "")
+	return r.Replace(s)
+}
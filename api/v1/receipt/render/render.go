@@ -0,0 +1,131 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"mini_pos/domain"
+)
+
+// DefaultWidth is the line width of a 58mm thermal printer
+const DefaultWidth = 32
+
+// DefaultTemplate is the layout used by an outlet without its own receipt body
+const DefaultTemplate = `{{center .Outlet.Name}}
+{{center .Outlet.Address}}
+{{if .Outlet.Phone}}{{center .Outlet.Phone}}
+{{end}}{{if .Outlet.TaxID}}{{center (printf "Tax ID %s" .Outlet.TaxID)}}
+{{end}}{{if .Header}}{{center .Header}}
+{{end}}{{line}}
+{{columns (printf "Order #%d" .OrderID) (date .Date)}}
+{{line}}
+{{range .Lines}}{{.Name}}
+{{columns (printf "  %s x %s" (qty .Qty) (money .Price)) (money .Total)}}
+{{if gt .Discount 0.0}}{{columns "  Discount" (printf "-%s" (money .Discount))}}
+{{end}}{{if gt .Tax 0.0}}{{columns (printf "  Tax %s%%" (qty .TaxRate)) (money .Tax)}}
+{{end}}{{end}}{{line}}
+{{columns "Subtotal" (money .Subtotal)}}
+{{if gt .Discount 0.0}}{{columns "Discount" (printf "-%s" (money .Discount))}}
+{{end}}{{columns "Tax" (money .Tax)}}
+{{columns "TOTAL" (money .Total)}}
+{{line}}
+{{range .Tenders}}{{columns (tender .) (money .Paid)}}
+{{end}}{{columns "Change" (money .Change)}}
+{{if .Footer}}{{line}}
+{{center .Footer}}
+{{end}}`
+
+// Text render the receipt with the outlet template as plain text, the header and footer of the outlet
+// are printed when the template has none
+func Text(tpl domain.ReceiptTemplate, r domain.Receipt) ([]byte, error) {
+	width := tpl.Width
+	if width == 0 {
+		width = DefaultWidth
+	}
+	body := tpl.Body
+	if body == "" {
+		body = DefaultTemplate
+	}
+	r.Header = tpl.Header
+	if r.Header == "" {
+		r.Header = r.Outlet.ReceiptHeader
+	}
+	r.Footer = tpl.Footer
+	if r.Footer == "" {
+		r.Footer = r.Outlet.ReceiptFooter
+	}
+	r.Width = width
+
+	t, err := template.New("receipt").Funcs(funcs(width)).Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = t.Execute(&buf, r)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func funcs(width int) template.FuncMap {
+	return template.FuncMap{
+		"line": func() string {
+			return strings.Repeat("-", width)
+		},
+		"center": func(s string) string {
+			s = cut(s, width)
+			return strings.Repeat(" ", (width-len(s))/2) + s
+		},
+		"columns": func(left string, right string) string {
+			right = cut(right, width)
+			left = cut(left, width-len(right)-1)
+			return left + strings.Repeat(" ", width-len(left)-len(right)) + right
+		},
+		"money": money,
+		"qty": func(v float64) string {
+			return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+		},
+		"date": func(t time.Time) string {
+			return t.Format("02/01/06 15:04")
+		},
+		"tender": func(p domain.Payment) string {
+			if p.Reference == "" {
+				return strings.ToUpper(string(p.TypePayment))
+			}
+			return fmt.Sprintf("%s %s", strings.ToUpper(string(p.TypePayment)), p.Reference)
+		},
+	}
+}
+
+// money format an amount with thousand separators and 2 decimals
+func money(v float64) string {
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	s := fmt.Sprintf("%.2f", domain.RoundMoney(v))
+	integer, decimals := s[:len(s)-3], s[len(s)-3:]
+	var out []byte
+	for i := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			out = append(out, ',')
+		}
+		out = append(out, integer[i])
+	}
+	return sign + string(out) + decimals
+}
+
+func cut(s string, n int) string {
+	if n < 0 {
+		n = 0
+	}
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
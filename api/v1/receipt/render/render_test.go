@@ -0,0 +1,95 @@
+package render_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"mini_pos/api/v1/receipt/render"
+	"mini_pos/domain"
+)
+
+func receipt() domain.Receipt {
+	bill := domain.OrderBill{
+		OrderID: 14,
+		TaxRate: 10,
+		Lines: []domain.BillLine{
+			{Name: "Samsung A3", Qty: 2, Price: 50000, Discount: 5000},
+			{Name: "Samsung A7", Qty: 1, Price: 65000, TaxRate: 5},
+		},
+	}
+	bill.Calculate()
+	return domain.Receipt{
+		PaymentResult: domain.PaymentResult{
+			OrderBill: bill,
+			Tenders: []domain.Payment{
+				{TypePayment: domain.PaymentMethodCard, Reference: "4411", Paid: 100000, Amount: 100000},
+				{TypePayment: domain.PaymentMethodCash, Paid: 80000, Change: 750, Amount: 79250},
+			},
+			Paid:   179250,
+			Change: 750,
+			IsPaid: true,
+		},
+		Outlet: domain.Outlet{Name: "Outlet 1", Address: "Jl. ABCD berarti"},
+		Date:   time.Date(2021, 11, 14, 19, 10, 0, 0, time.UTC),
+	}
+}
+
+func TestTextDefaultTemplate(t *testing.T) {
+	out, err := render.Text(domain.ReceiptTemplate{Footer: "Thank you"}, receipt())
+	assert.NoError(t, err)
+
+	text := string(out)
+	for _, l := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		assert.LessOrEqual(t, len(l), render.DefaultWidth, l)
+	}
+	assert.Contains(t, text, "Order #14")
+	assert.Contains(t, text, "TOTAL                 179,250.00")
+	assert.Contains(t, text, "CARD 4411")
+	assert.Contains(t, text, "Change                    750.00")
+	assert.Contains(t, text, "Thank you")
+}
+
+func TestTextOutletTemplate(t *testing.T) {
+	out, err := render.Text(domain.ReceiptTemplate{Width: 40, Body: `{{.Outlet.Name}} {{money .Total}}`}, receipt())
+	assert.NoError(t, err)
+	assert.Equal(t, "Outlet 1 179,250.00", string(out))
+
+	_, err = render.Text(domain.ReceiptTemplate{Body: `{{.Outlet.Name`}, receipt())
+	assert.Error(t, err)
+	_, err = render.Text(domain.ReceiptTemplate{Body: `{{.Cashier}}`}, receipt())
+	assert.Error(t, err)
+}
+
+func TestEscPos(t *testing.T) {
+	out := render.EscPos([]byte("a\nb"))
+	assert.True(t, bytes.HasPrefix(out, []byte{0x1B, 0x40, 'a', '\n', 'b', '\n'}))
+	assert.True(t, bytes.HasSuffix(out, []byte{0x1D, 0x56, 0x01}))
+}
+
+func TestPDF(t *testing.T) {
+	out := render.PDF([]byte("Total (paid)\n"), 32)
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4")))
+	assert.Contains(t, string(out), `(Total \(paid\)) '`)
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+}
+
+func TestTextOutletDetails(t *testing.T) {
+	r := receipt()
+	r.Outlet.Phone = "021-555-0101"
+	r.Outlet.TaxID = "01.234.567.8"
+	r.Outlet.ReceiptFooter = "See you soon"
+	out, err := render.Text(domain.ReceiptTemplate{}, r)
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), "021-555-0101")
+	assert.Contains(t, string(out), "Tax ID 01.234.567.8")
+	assert.Contains(t, string(out), "See you soon")
+
+	// the footer of the template comes first
+	out, err = render.Text(domain.ReceiptTemplate{Footer: "Thank you"}, r)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(out), "See you soon")
+}
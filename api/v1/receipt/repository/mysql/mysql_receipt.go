@@ -0,0 +1,68 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"mini_pos/domain"
+)
+
+type mysqlReceiptRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlReceiptRepository will create an object that represent the Receipt.Repository interface
+func NewMysqlReceiptRepository(Conn *sql.DB) domain.ReceiptRepository {
+	return &mysqlReceiptRepository{Conn}
+}
+
+func (m *mysqlReceiptRepository) GetTemplate(ctx context.Context, outletId int64) (res domain.ReceiptTemplate, err error) {
+	query := `SELECT id, outlet_id, header, footer, width, body, update_at, create_at
+  						FROM receipt_templates WHERE outlet_id = ?`
+
+	header := sql.NullString{}
+	footer := sql.NullString{}
+	body := sql.NullString{}
+	err = m.Conn.QueryRowContext(ctx, query, outletId).Scan(
+		&res.ID,
+		&res.OutletID,
+		&header,
+		&footer,
+		&res.Width,
+		&body,
+		&res.UpdatedAt,
+		&res.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return domain.ReceiptTemplate{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.ReceiptTemplate{}, err
+	}
+	res.Header = header.String
+	res.Footer = footer.String
+	res.Body = body.String
+	return
+}
+
+// StoreTemplate create the template of the outlet or replace the existing one
+func (m *mysqlReceiptRepository) StoreTemplate(ctx context.Context, a *domain.ReceiptTemplate) (err error) {
+	query := `INSERT  receipt_templates 
+			  SET outlet_id=?, header=?, footer=?, width=?, body=?, update_at=?, create_at=?
+			  ON DUPLICATE KEY UPDATE header=VALUES(header), footer=VALUES(footer), width=VALUES(width),
+			  body=VALUES(body), update_at=VALUES(update_at)`
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	_, err = stmt.ExecContext(ctx, a.OutletID, a.Header, a.Footer, a.Width,
+		sql.NullString{String: a.Body, Valid: a.Body != ""}, now, now)
+	if err != nil {
+		return
+	}
+	a.UpdatedAt = now
+	return
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"mini_pos/api/v1/receipt/render"
+	"mini_pos/constants"
+	"mini_pos/domain"
+)
+
+type ReceiptUsecase struct {
+	ReceiptRepo    domain.ReceiptRepository
+	OrderRepo    domain.OrderRepository
+	OrderItemRepo    domain.OrderItemRepository
+	PaymentRepo    domain.PaymentRepository
+	OutletRepo    domain.OutletRepository
+	contextTimeout time.Duration
+}
+
+// NewReceiptUsecase will create new an ReceiptUsecase object representation of domain.ReceiptUsecase interface
+func NewReceiptUsecase(a domain.ReceiptRepository, order domain.OrderRepository, item domain.OrderItemRepository, payment domain.PaymentRepository, outlet domain.OutletRepository, timeout time.Duration) domain.ReceiptUsecase {
+	return &ReceiptUsecase{
+		ReceiptRepo:    a,
+		OrderRepo:    order,
+		OrderItemRepo:    item,
+		PaymentRepo:    payment,
+		OutletRepo:    outlet,
+		contextTimeout: timeout,
+	}
+}
+
+// template return the outlet template, or the built in layout when the outlet has none
+func (a *ReceiptUsecase) template(ctx context.Context, outletId int64) (domain.ReceiptTemplate, error) {
+	tpl, err := a.ReceiptRepo.GetTemplate(ctx, outletId)
+	if err == domain.ErrNotFound {
+		return domain.ReceiptTemplate{
+			OutletID: outletId,
+			Width:    render.DefaultWidth,
+		}, nil
+	}
+	return tpl, err
+}
+
+// Render build the receipt of a paid order, userId is the cashier owning the order or empty for a supervisor reprint
+func (a *ReceiptUsecase) Render(c context.Context, orderId int64, userId int64, format domain.ReceiptFormat) (res []byte, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if !format.Valid() {
+		return nil, constants.ErrReceiptFormatInvalid
+	}
+
+	order, err := a.OrderRepo.GetAnyByID(ctx, orderId)
+	if err != nil {
+		return
+	}
+	if userId != 0 && order.UserID != userId {
+		return nil, domain.ErrNotFound
+	}
+	if order.IsCheckout != 1 {
+		return nil, constants.ErrOrderNotPaid
+	}
+
+	outlet, err := a.OutletRepo.GetByID(ctx, order.OutletID)
+	if err != nil {
+		return
+	}
+	lines, err := a.OrderItemRepo.FetchBillLines(ctx, order.ID, order.OutletID)
+	if err != nil {
+		return
+	}
+	bill := domain.OrderBill{
+		OrderID: order.ID,
+		Lines:   lines,
+		TaxRate: order.Tax,
+	}
+	bill.Calculate()
+	tenders, err := a.PaymentRepo.FetchByOrderID(ctx, order.ID)
+	if err != nil {
+		return
+	}
+
+	receipt := domain.Receipt{
+		PaymentResult: domain.PaymentResult{
+			OrderBill: bill,
+			Tenders:   tenders,
+			IsPaid:    true,
+		},
+		Outlet: outlet,
+		Order:  order,
+		Date:   order.UpdatedAt,
+	}
+	// the receipt is dated in the time zone of the outlet
+	if loc, errLoc := time.LoadLocation(outlet.Timezone); outlet.Timezone != "" && errLoc == nil {
+		receipt.Date = receipt.Date.In(loc)
+	}
+	for _, t := range tenders {
+		receipt.Paid += t.Amount
+		receipt.Change += t.Change
+	}
+	receipt.Paid = domain.RoundMoney(receipt.Paid)
+	receipt.Change = domain.RoundMoney(receipt.Change)
+
+	tpl, err := a.template(ctx, order.OutletID)
+	if err != nil {
+		return
+	}
+	text, err := render.Text(tpl, receipt)
+	if err != nil {
+		return nil, constants.ErrReceiptTemplateInvalid
+	}
+
+	switch format {
+	case domain.ReceiptFormatEscPos:
+		return render.EscPos(text), nil
+	case domain.ReceiptFormatPDF:
+		return render.PDF(text, tpl.Width), nil
+	}
+	return text, nil
+}
+
+// OrderOutlet return the outlet of the order, for the handler to check it against the token
+func (a *ReceiptUsecase) OrderOutlet(c context.Context, orderId int64) (int64, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	order, err := a.OrderRepo.GetAnyByID(ctx, orderId)
+	if err != nil {
+		return 0, err
+	}
+	return order.OutletID, nil
+}
+
+func (a *ReceiptUsecase) GetTemplate(c context.Context, outletId int64) (res domain.ReceiptTemplate, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	_, err = a.OutletRepo.GetByID(ctx, outletId)
+	if err != nil {
+		return
+	}
+	res, err = a.template(ctx, outletId)
+	if err != nil {
+		return
+	}
+	if res.Body == "" {
+		res.Body = render.DefaultTemplate
+	}
+	return
+}
+
+func (a *ReceiptUsecase) UpdateTemplate(c context.Context, m *domain.ReceiptTemplate) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	_, err = a.OutletRepo.GetByID(ctx, m.OutletID)
+	if err != nil {
+		return
+	}
+	if m.Width == 0 {
+		m.Width = render.DefaultWidth
+	}
+	// the built in layout is kept as an empty body so it follows later changes of the default
+	if m.Body == render.DefaultTemplate {
+		m.Body = ""
+	}
+	// render an empty receipt so unknown fields are caught now and not at the till
+	if _, err = render.Text(*m, domain.Receipt{}); err != nil {
+		return constants.ErrReceiptTemplateInvalid
+	}
+	return a.ReceiptRepo.StoreTemplate(ctx, m)
+}
@@ -0,0 +1,255 @@
+package http
+
+import (
+	"mini_pos/api/common"
+	middlwr "mini_pos/api/middleware"
+	"mini_pos/config"
+	"mini_pos/constants"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"mini_pos/domain"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// RefundHandler  represent the httphandler for Refund
+type RefundHandler struct {
+	AUsecase domain.RefundUsecase
+}
+
+// NewRefundHandler will initialize the refunds/ resources endpoint
+func NewRefundHandler(e *echo.Echo, us domain.RefundUsecase, cfg config.Config) {
+	handler := &RefundHandler{
+		AUsecase: us,
+	}
+	RefundV1:= e.Group("")
+
+	RefundV1.Use(middlwr.JWTMiddleware(cfg))
+	RefundV1.GET("/refunds", handler.FetchRefund, middlwr.Permission(domain.PermRefundManage))
+	RefundV1.POST("/refunds", handler.Refund, middlwr.Permission(domain.PermRefundManage))
+	RefundV1.GET("/refunds/:id", handler.GetByID, middlwr.Permission(domain.PermRefundManage))
+	RefundV1.GET("/refunds/order/:id", handler.FetchByOrderID, middlwr.Permission(domain.PermRefundManage))
+}
+
+func isRequestValid(m *domain.RequestRefund) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(m)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FetchRefund will fetch the Refund based on given params
+func (a *RefundHandler) FetchRefund(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	// outlet 0 lists the refunds of every outlet, only for a holder of every outlet
+	outlet, _ := strconv.Atoi(c.QueryParam("outlet"))
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
+	}
+	cursor := c.QueryParam("cursor")
+	ctx := c.Request().Context()
+
+	listAr, nextCursor, err := a.AUsecase.Fetch(ctx, cursor, int64(num), int64(outlet))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(common.NewSuccessResponse(listAr))
+}
+
+// GetByID will get Refund by given id
+func (a *RefundHandler) GetByID(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+
+	refund, err := a.AUsecase.GetByID(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	outlet, err := a.AUsecase.OrderOutlet(ctx, refund.OrderID)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(outlet) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	return c.JSON(common.NewSuccessResponse(refund))
+}
+
+// FetchByOrderID will get the refunds made against an order
+func (a *RefundHandler) FetchByOrderID(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+
+	outlet, err := a.AUsecase.OrderOutlet(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(outlet) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	list, err := a.AUsecase.FetchByOrderID(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(list))
+}
+
+// Refund will return goods of a paid order by given request body, supervisor only
+func (a *RefundHandler) Refund(c echo.Context) (err error) {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	var refund domain.RequestRefund
+	err = c.Bind(&refund)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&refund); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+
+	// only a supervisor of the order's outlet gives its goods back
+	outlet, err := a.AUsecase.OrderOutlet(ctx, refund.OrderID)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(outlet) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	refund.UserID = int64(claims.ID)
+	refund.ActorID = int64(claims.ActorID)
+	res, err := a.AUsecase.Refund(ctx, &refund)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(res))
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrConflict:
+		return http.StatusConflict
+	case domain.ErrBadParamInput:
+		return http.StatusBadRequest
+	case constants.ErrDataNotFound:
+		return http.StatusNotFound
+	case constants.ErrOrderNotPaid, constants.ErrProductNotInOutlet, constants.ErrShiftNotOpen:
+		return http.StatusConflict
+	case constants.ErrRefundEmpty, constants.ErrRefundQtyExceed, constants.ErrRefundExceedPayment:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
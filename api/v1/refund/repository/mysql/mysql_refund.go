@@ -0,0 +1,288 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/api/transaction"
+	"mini_pos/api/v1/refund/repository"
+	"mini_pos/domain"
+)
+
+type mysqlRefundRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlRefundRepository will create an object that represent the Refund.Repository interface
+func NewMysqlRefundRepository(Conn *sql.DB) domain.RefundRepository {
+	return &mysqlRefundRepository{Conn}
+}
+
+func (m *mysqlRefundRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Refund, err error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Refund, 0)
+	for rows.Next() {
+		t := domain.Refund{}
+		err = rows.Scan(
+			&t.ID,
+			&t.OrderID,
+			&t.UserID,
+			&t.Reason,
+			&t.Amount,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, nil
+}
+
+// Fetch return the refunds of the orders of the outlet, or of every outlet for the outlet 0
+func (m *mysqlRefundRepository) Fetch(ctx context.Context, cursor string, num int64, outletId int64) (res []domain.Refund, nextCursor string, err error) {
+	query := `SELECT refunds.id, refunds.order_id, refunds.user_id, refunds.reason, refunds.amount, refunds.update_at, refunds.create_at
+  						FROM refunds JOIN orders ON orders.id = refunds.order_id
+  						WHERE refunds.create_at > ? and (? = 0 or orders.outlet_id = ?) ORDER BY refunds.create_at LIMIT ? `
+
+	decodedCursor, err := repository.DecodeCursor(cursor)
+	if err != nil && cursor != "" {
+		return nil, "", domain.ErrBadParamInput
+	}
+
+	res, err = m.fetch(ctx, query, decodedCursor, outletId, outletId, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(res) == int(num) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	}
+
+	return
+}
+
+func (m *mysqlRefundRepository) GetByID(ctx context.Context, id int64) (res domain.Refund, err error) {
+	query := `SELECT id, order_id, user_id, reason, amount, update_at, create_at
+  						FROM refunds WHERE ID = ? `
+
+	list, err := m.fetch(ctx, query, id)
+	if err != nil {
+		return domain.Refund{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+
+	return
+}
+
+func (m *mysqlRefundRepository) FetchByOrderID(ctx context.Context, orderId int64) (res []domain.Refund, err error) {
+	query := `SELECT id, order_id, user_id, reason, amount, update_at, create_at
+  						FROM refunds WHERE order_id = ? ORDER BY id`
+
+	return m.fetch(ctx, query, orderId)
+}
+
+func (m *mysqlRefundRepository) FetchItems(ctx context.Context, refundId int64) (res []domain.RefundItem, err error) {
+	query := `SELECT id, refund_id, order_item_id, product_id, qty, amount, update_at, create_at
+  						FROM refundItem WHERE refund_id = ? ORDER BY id`
+
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, refundId)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	res = make([]domain.RefundItem, 0)
+	for rows.Next() {
+		t := domain.RefundItem{}
+		err = rows.Scan(
+			&t.ID,
+			&t.RefundID,
+			&t.OrderItemID,
+			&t.ProductID,
+			&t.Qty,
+			&t.Amount,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, t)
+	}
+
+	return
+}
+
+// FetchPayments return the tenders the refund was given back on, with their type
+func (m *mysqlRefundRepository) FetchPayments(ctx context.Context, refundId int64) (res []domain.RefundPayment, err error) {
+	query := `SELECT refund_payments.id, refund_payments.refund_id, refund_payments.payment_id, payments.type_payment,
+				refund_payments.amount, IFNULL(refund_payments.shift_id,0), refund_payments.update_at, refund_payments.create_at
+  						FROM refund_payments join payments on payments.id = refund_payments.payment_id
+  						WHERE refund_payments.refund_id = ? ORDER BY refund_payments.id`
+
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, refundId)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	res = make([]domain.RefundPayment, 0)
+	for rows.Next() {
+		t := domain.RefundPayment{}
+		err = rows.Scan(
+			&t.ID,
+			&t.RefundID,
+			&t.PaymentID,
+			&t.TypePayment,
+			&t.Amount,
+			&t.ShiftID,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, t)
+	}
+
+	return
+}
+
+// Returned return the quantity of an order line already given back and the amount refunded for it
+func (m *mysqlRefundRepository) Returned(ctx context.Context, orderItemId int64) (qty float64, amount float64, err error) {
+	query := `SELECT IFNULL(SUM(qty),0), IFNULL(SUM(amount),0) FROM refundItem WHERE order_item_id = ?`
+
+	err = transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, orderItemId).Scan(&qty, &amount)
+	return
+}
+
+// RefundedAmount return the amount already given back on a payments row
+func (m *mysqlRefundRepository) RefundedAmount(ctx context.Context, paymentId int64) (amount float64, err error) {
+	query := `SELECT IFNULL(SUM(amount),0) FROM refund_payments WHERE payment_id = ?`
+
+	err = transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, paymentId).Scan(&amount)
+	return
+}
+
+func (m *mysqlRefundRepository) Store(ctx context.Context, a *domain.Refund) (err error) {
+	query := `INSERT  refunds 
+			  SET order_id=?, user_id=?, actor_id=?, reason=?, amount=?,
+			  update_at=? , create_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx,
+		a.OrderID, a.UserID, nullID(a.ActorID), a.Reason, a.Amount, now, now)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	return
+}
+
+func (m *mysqlRefundRepository) StoreItem(ctx context.Context, a *domain.RefundItem) (err error) {
+	query := `INSERT  refundItem 
+			  SET refund_id=?, order_item_id=?, product_id=?, qty=?, amount=?,
+			  update_at=? , create_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx,
+		a.RefundID, a.OrderItemID, a.ProductID, a.Qty, a.Amount, now, now)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	return
+}
+
+func (m *mysqlRefundRepository) StorePayment(ctx context.Context, a *domain.RefundPayment) (err error) {
+	query := `INSERT  refund_payments 
+			  SET refund_id=?, payment_id=?, amount=?, shift_id=?,
+			  update_at=? , create_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx,
+		a.RefundID, a.PaymentID, a.Amount, sql.NullInt64{Int64: a.ShiftID, Valid: a.ShiftID != 0}, now, now)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	return
+}
+
+// nullID stores an empty foreign key as NULL instead of 0
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
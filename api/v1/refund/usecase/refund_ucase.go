@@ -0,0 +1,331 @@
+package usecase
+
+import (
+	"context"
+	"mini_pos/constants"
+	"time"
+
+	"mini_pos/domain"
+)
+
+type RefundUsecase struct {
+	RefundRepo    domain.RefundRepository
+	OrderRepo    domain.OrderRepository
+	OrderItemRepo    domain.OrderItemRepository
+	PaymentRepo    domain.PaymentRepository
+	ProductOutletsRepo    domain.ProductOutletsRepository
+	ShiftRepo    domain.ShiftRepository
+	Transactor    domain.Transactor
+	contextTimeout time.Duration
+}
+
+// NewRefundUsecase will create new an RefundUsecase object representation of domain.RefundUsecase interface
+func NewRefundUsecase(a domain.RefundRepository, order domain.OrderRepository, item domain.OrderItemRepository, payment domain.PaymentRepository, prodOutlet domain.ProductOutletsRepository, shift domain.ShiftRepository, tx domain.Transactor, timeout time.Duration) domain.RefundUsecase {
+	return &RefundUsecase{
+		RefundRepo:    a,
+		OrderRepo:    order,
+		OrderItemRepo:    item,
+		PaymentRepo:    payment,
+		ProductOutletsRepo:    prodOutlet,
+		ShiftRepo:    shift,
+		Transactor:    tx,
+		contextTimeout: timeout,
+	}
+}
+
+func (a *RefundUsecase) fillRefundItems(c context.Context, data []domain.Refund) ([]domain.Refund, error) {
+	for index, refund := range data {
+		items, err := a.RefundRepo.FetchItems(c, refund.ID)
+		if err != nil {
+			return nil, err
+		}
+		data[index].Items = items
+		data[index].Payments, err = a.RefundRepo.FetchPayments(c, refund.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func (a *RefundUsecase) Fetch(c context.Context, cursor string, num int64, outletId int64) (res []domain.Refund, nextCursor string, err error) {
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, nextCursor, err = a.RefundRepo.Fetch(ctx, cursor, num, outletId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, err = a.fillRefundItems(ctx, res)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(res) <= 0 {
+		return nil, "", constants.ErrDataNotFound
+	}
+	return
+}
+
+func (a *RefundUsecase) GetByID(c context.Context, id int64) (res domain.Refund, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err = a.RefundRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	res.Items, err = a.RefundRepo.FetchItems(ctx, id)
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	res.Payments, err = a.RefundRepo.FetchPayments(ctx, id)
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	return
+}
+
+func (a *RefundUsecase) FetchByOrderID(c context.Context, orderId int64) (res []domain.Refund, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err = a.RefundRepo.FetchByOrderID(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	res, err = a.fillRefundItems(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) <= 0 {
+		return nil, constants.ErrDataNotFound
+	}
+	return
+}
+
+// OrderOutlet return the outlet of the order, for the handler to check it against the token
+func (a *RefundUsecase) OrderOutlet(c context.Context, orderId int64) (int64, error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	order, err := a.OrderRepo.GetAnyByID(ctx, orderId)
+	if err != nil {
+		return 0, err
+	}
+	return order.OutletID, nil
+}
+
+// Refund return goods of a paid order, the refund, its lines and the restock succeed or fail together
+func (a *RefundUsecase) Refund(c context.Context, m *domain.RequestRefund) (res domain.Refund, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	err = a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// serialize refunds of the same order so a line cannot be returned twice
+		err := a.OrderRepo.Lock(ctx, m.OrderID)
+		if err != nil {
+			return err
+		}
+		order, err := a.OrderRepo.GetAnyByID(ctx, m.OrderID)
+		if err != nil {
+			return err
+		}
+		if order.IsCheckout != 1 {
+			return constants.ErrOrderNotPaid
+		}
+
+		lines, err := a.OrderItemRepo.FetchBillLines(ctx, order.ID, order.OutletID)
+		if err != nil {
+			return err
+		}
+		bill := domain.OrderBill{
+			OrderID: order.ID,
+			Lines:   lines,
+			TaxRate: order.Tax,
+		}
+		bill.Calculate()
+
+		items, err := a.returnItems(ctx, bill, m.Items)
+		if err != nil {
+			return err
+		}
+
+		res = domain.Refund{
+			OrderID: order.ID,
+			UserID:  m.UserID,
+			ActorID: m.ActorID,
+			Reason:  m.Reason,
+		}
+		for _, item := range items {
+			res.Amount += item.Amount
+		}
+		res.Amount = domain.RoundMoney(res.Amount)
+
+		payments, err := a.splitPayment(ctx, order.ID, m.PaymentID, res.Amount)
+		if err != nil {
+			return err
+		}
+		err = a.cashShift(ctx, payments, m.UserID, order.OutletID)
+		if err != nil {
+			return err
+		}
+
+		err = a.RefundRepo.Store(ctx, &res)
+		if err != nil {
+			return err
+		}
+		for i := range payments {
+			payments[i].RefundID = res.ID
+			err = a.RefundRepo.StorePayment(ctx, &payments[i])
+			if err != nil {
+				return err
+			}
+		}
+		res.Payments = payments
+		for i := range items {
+			items[i].RefundID = res.ID
+			err = a.RefundRepo.StoreItem(ctx, &items[i])
+			if err != nil {
+				return err
+			}
+			err = a.ProductOutletsRepo.ReleaseStock(ctx, items[i].ProductID, order.OutletID, items[i].Qty)
+			if err != nil {
+				return err
+			}
+		}
+		res.Items = items
+		return nil
+	})
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	return
+}
+
+// returnItems price the requested quantities, every quantity not returned yet when nothing is requested
+func (a *RefundUsecase) returnItems(ctx context.Context, bill domain.OrderBill, req []domain.RequestRefundItem) (res []domain.RefundItem, err error) {
+	requested := make(map[int64]float64)
+	for _, r := range req {
+		requested[r.OrderItemID] += r.Qty
+	}
+
+	for _, line := range bill.Lines {
+		qty, ok := requested[line.OrderItemID]
+		if len(req) > 0 && !ok {
+			continue
+		}
+		delete(requested, line.OrderItemID)
+
+		returned, refunded, err := a.RefundRepo.Returned(ctx, line.OrderItemID)
+		if err != nil {
+			return nil, err
+		}
+		left := line.Qty - returned
+		if len(req) == 0 {
+			qty = left
+		}
+		if qty > left {
+			return nil, constants.ErrRefundQtyExceed
+		}
+		if qty <= 0 {
+			continue
+		}
+		res = append(res, domain.RefundItem{
+			OrderItemID: line.OrderItemID,
+			ProductID:   line.ProductID,
+			Qty:         qty,
+			Amount:      bill.Refundable(line, qty, returned, refunded),
+		})
+	}
+
+	// a requested line that does not belong to the order
+	if len(requested) > 0 {
+		return nil, domain.ErrNotFound
+	}
+	if len(res) == 0 {
+		return nil, constants.ErrRefundEmpty
+	}
+	return
+}
+
+// splitPayment spread the amount over the tenders of the order still holding money, the requested tender first,
+// then the cash tenders and then the others in the order they were taken
+func (a *RefundUsecase) splitPayment(ctx context.Context, orderId int64, paymentId int64, amount float64) (res []domain.RefundPayment, err error) {
+	tenders, err := a.PaymentRepo.FetchByOrderID(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := make([]domain.Payment, 0, len(tenders))
+	for _, t := range tenders {
+		if t.ID == paymentId {
+			ordered = append(ordered, t)
+		}
+	}
+	if paymentId != 0 && len(ordered) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	for _, t := range tenders {
+		if t.ID != paymentId && t.TypePayment == domain.PaymentMethodCash {
+			ordered = append(ordered, t)
+		}
+	}
+	for _, t := range tenders {
+		if t.ID != paymentId && t.TypePayment != domain.PaymentMethodCash {
+			ordered = append(ordered, t)
+		}
+	}
+
+	left := amount
+	for _, t := range ordered {
+		if left <= 0 {
+			break
+		}
+		refunded, err := a.RefundRepo.RefundedAmount(ctx, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		available := domain.RoundMoney(t.Amount - refunded)
+		if available <= 0 {
+			continue
+		}
+		part := left
+		if available < part {
+			part = available
+		}
+		res = append(res, domain.RefundPayment{
+			PaymentID:   t.ID,
+			TypePayment: t.TypePayment,
+			Amount:      part,
+		})
+		left = domain.RoundMoney(left - part)
+	}
+	if left > 0 {
+		return nil, constants.ErrRefundExceedPayment
+	}
+	return
+}
+
+// cashShift take the cash parts of the refund from the open shift of the user in the outlet,
+// so the drawer of that shift expects the cash handed back
+func (a *RefundUsecase) cashShift(ctx context.Context, payments []domain.RefundPayment, userId int64, outletId int64) error {
+	for i := range payments {
+		if payments[i].TypePayment != domain.PaymentMethodCash {
+			continue
+		}
+		shift, err := a.ShiftRepo.GetOpen(ctx, userId, outletId)
+		if err == domain.ErrNotFound {
+			return constants.ErrShiftNotOpen
+		}
+		if err != nil {
+			return err
+		}
+		payments[i].ShiftID = shift.ID
+	}
+	return nil
+}
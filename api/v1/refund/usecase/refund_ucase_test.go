@@ -0,0 +1,97 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"mini_pos/api/v1/refund/usecase"
+	"mini_pos/constants"
+	"mini_pos/domain"
+	"mini_pos/domain/fakes"
+)
+
+// newRefundUsecase sell two T-Shirts of 50000 on the paid order 1 of the outlet 1, settled half by card then
+// half in cash, and three caps for 200.00 by card on the order 2, while the shift 4 of the user 2 is open
+// in the outlet 1
+func newRefundUsecase() (domain.RefundUsecase, *fakes.RefundRepository) {
+	repo := &fakes.RefundRepository{}
+	orders := &fakes.OrderRepository{Orders: map[int64]domain.Order{
+		1: {ID: 1, OutletID: 1, IsCheckout: 1},
+		2: {ID: 2, OutletID: 1, IsCheckout: 1},
+	}}
+	items := &fakes.OrderItemRepository{Lines: map[int64][]domain.BillLine{
+		1: {{OrderItemID: 5, ProductID: 3, Name: "T-Shirt", Qty: 2, Price: 50000}},
+		2: {{OrderItemID: 6, ProductID: 4, Name: "Cap", Qty: 3, Price: 70, Discount: 10}},
+	}}
+	payments := &fakes.PaymentRepository{Payments: []domain.Payment{
+		{ID: 10, Order: 1, TypePayment: domain.PaymentMethodCard, Amount: 50000},
+		{ID: 11, Order: 1, TypePayment: domain.PaymentMethodCash, Amount: 50000},
+		{ID: 12, Order: 2, TypePayment: domain.PaymentMethodCard, Amount: 200},
+	}}
+	shifts := &fakes.ShiftRepository{Shifts: []domain.Shift{{ID: 4, UserID: 2, OutletID: 1, Status: domain.ShiftOpen}}}
+	u := usecase.NewRefundUsecase(repo, orders, items, payments, &fakes.ProductOutletsRepository{}, shifts,
+		fakes.Transactor{}, time.Second)
+	return u, repo
+}
+
+func TestRefundSplitPayment(t *testing.T) {
+	ctx := context.Background()
+	u, _ := newRefundUsecase()
+
+	// cash is only handed back from an open drawer
+	_, err := u.Refund(ctx, &domain.RequestRefund{OrderID: 1, UserID: 3, Reason: "damaged"})
+	assert.Equal(t, constants.ErrShiftNotOpen, err)
+
+	res, err := u.Refund(ctx, &domain.RequestRefund{OrderID: 1, UserID: 2, Reason: "damaged"})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(100000), res.Amount)
+	// the cash is given back first from the open shift, the card covers the rest
+	assert.Equal(t, []domain.RefundPayment{
+		{ID: 1, RefundID: 1, PaymentID: 11, TypePayment: domain.PaymentMethodCash, Amount: 50000, ShiftID: 4},
+		{ID: 2, RefundID: 1, PaymentID: 10, TypePayment: domain.PaymentMethodCard, Amount: 50000},
+	}, res.Payments)
+
+	_, err = u.Refund(ctx, &domain.RequestRefund{OrderID: 1, UserID: 2, Reason: "damaged"})
+	assert.Equal(t, constants.ErrRefundEmpty, err)
+}
+
+func TestRefundRequestedPaymentFirst(t *testing.T) {
+	ctx := context.Background()
+	u, repo := newRefundUsecase()
+
+	req := &domain.RequestRefund{OrderID: 1, PaymentID: 10, UserID: 2, ActorID: 1, Reason: "damaged",
+		Items: []domain.RequestRefundItem{{OrderItemID: 5, Qty: 1}}}
+	res, err := u.Refund(ctx, req)
+	assert.NoError(t, err)
+	assert.Len(t, res.Payments, 1)
+	assert.Equal(t, int64(10), res.Payments[0].PaymentID)
+	// made with an impersonation token, the support staff is kept with the refund
+	assert.Equal(t, int64(1), repo.Refunds[0].ActorID)
+
+	req.PaymentID = 99
+	_, err = u.Refund(ctx, req)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestRefundOneUnitAtATime(t *testing.T) {
+	ctx := context.Background()
+	u, _ := newRefundUsecase()
+
+	// the first returns are prorated and rounded, the last one gives back what is left of the line
+	var total float64
+	for _, expected := range []float64{66.67, 66.67, 66.66} {
+		res, err := u.Refund(ctx, &domain.RequestRefund{OrderID: 2, UserID: 2, Reason: "damaged",
+			Items: []domain.RequestRefundItem{{OrderItemID: 6, Qty: 1}}})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, res.Amount)
+		total += res.Amount
+	}
+	assert.Equal(t, float64(200), domain.RoundMoney(total))
+
+	_, err := u.Refund(ctx, &domain.RequestRefund{OrderID: 2, UserID: 2, Reason: "damaged",
+		Items: []domain.RequestRefundItem{{OrderItemID: 6, Qty: 1}}})
+	assert.Equal(t, constants.ErrRefundQtyExceed, err)
+}
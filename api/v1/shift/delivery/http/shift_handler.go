@@ -0,0 +1,335 @@
+package http
+
+import (
+	"mini_pos/api/common"
+	middlwr "mini_pos/api/middleware"
+	"mini_pos/config"
+	"mini_pos/constants"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"mini_pos/domain"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// ShiftHandler  represent the httphandler for Shift
+type ShiftHandler struct {
+	AUsecase domain.ShiftUsecase
+}
+
+// NewShiftHandler will initialize the shifts/ resources endpoint
+func NewShiftHandler(e *echo.Echo, us domain.ShiftUsecase, cfg config.Config) {
+	handler := &ShiftHandler{
+		AUsecase: us,
+	}
+	ShiftV1:= e.Group("")
+
+	ShiftV1.Use(middlwr.JWTMiddleware(cfg))
+	ShiftV1.GET("/shifts", handler.FetchShift, middlwr.Permission(domain.PermShiftManage))
+	ShiftV1.GET("/shifts/current", handler.Current, middlwr.Permission(domain.PermSell))
+	ShiftV1.POST("/shifts/open", handler.Open, middlwr.Permission(domain.PermSell))
+	ShiftV1.POST("/shifts/:id/movements", handler.AddMovement, middlwr.Permission(domain.PermSell))
+	ShiftV1.GET("/shifts/:id/report", handler.Report, middlwr.Permission(domain.PermSell, domain.PermShiftManage))
+	ShiftV1.POST("/shifts/:id/close", handler.Close, middlwr.Permission(domain.PermSell, domain.PermShiftManage))
+}
+
+func isRequestValid(m interface{}) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(m)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ownerID return the user a shift must belong to, a supervisor may work on the shift of any cashier
+func ownerID(claims *middlwr.JwtCustomClaims) int64 {
+	if claims.Can(domain.PermShiftManage) {
+		return 0
+	}
+	return int64(claims.ID)
+}
+
+// FetchShift will fetch the shifts of an outlet, super admin only
+func (a *ShiftHandler) FetchShift(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	outlet, _ := strconv.Atoi(c.QueryParam("outlet"))
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
+	}
+	cursor := c.QueryParam("cursor")
+	ctx := c.Request().Context()
+
+	listAr, nextCursor, err := a.AUsecase.Fetch(ctx, cursor, int64(num), int64(outlet))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(common.NewSuccessResponse(listAr))
+}
+
+// Current will get the open shift of the logged in cashier in the outlet param
+func (a *ShiftHandler) Current(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	outlet, err := strconv.Atoi(c.QueryParam("outlet"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: constants.ErrParamsIsNotInvalid.Error(),
+			Data:    nil,
+		}))
+	}
+	if !claims.HasOutlet(int64(outlet)) {
+		return middlwr.OutletForbidden(c)
+	}
+	ctx := c.Request().Context()
+
+	shift, err := a.AUsecase.Current(ctx, int64(claims.ID), int64(outlet))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(shift))
+}
+
+// Open will open a shift for the logged in cashier with the opening float of the drawer
+func (a *ShiftHandler) Open(c echo.Context) (err error) {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	var req domain.RequestOpenShift
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&req); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	if !claims.HasOutlet(req.OutletID) {
+		return middlwr.OutletForbidden(c)
+	}
+
+	ctx := c.Request().Context()
+	req.UserID = int64(claims.ID)
+	req.ActorID = int64(claims.ActorID)
+	shift, err := a.AUsecase.Open(ctx, &req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(shift))
+}
+
+// AddMovement will record cash put in or taken out of the drawer of an open shift
+func (a *ShiftHandler) AddMovement(c echo.Context) (err error) {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+	var movement domain.ShiftMovement
+	err = c.Bind(&movement)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&movement); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	movement.ShiftID = int64(idP)
+	movement.UserID = int64(claims.ID)
+	movement.ActorID = int64(claims.ActorID)
+	err = a.AUsecase.AddMovement(ctx, &movement)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(movement))
+}
+
+// Report will get the X report of an open shift or the Z report of a closed one
+func (a *ShiftHandler) Report(c echo.Context) error {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+	ctx := c.Request().Context()
+
+	report, err := a.AUsecase.Report(ctx, int64(idP), ownerID(claims))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(report))
+}
+
+// Close will close a shift with the counted cash of the drawer and return its Z report
+func (a *ShiftHandler) Close(c echo.Context) (err error) {
+	claims := middlwr.GetTokenFromContext(c)
+	if claims == nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnauthorized,
+			Message: constants.ErrTokenAlreadyExpired.Error(),
+			Data:    nil,
+		}))
+	}
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusNotFound,
+			Message: constants.ErrDataNotFound.Error(),
+			Data:    nil,
+		}))
+	}
+	var req domain.RequestCloseShift
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&req); !ok {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	ctx := c.Request().Context()
+	req.ID = int64(idP)
+	req.UserID = ownerID(claims)
+	report, err := a.AUsecase.Close(ctx, &req)
+	if err != nil {
+		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
+			Code:    getStatusCode(err),
+			Message: err.Error(),
+			Data:    nil,
+		}))
+	}
+
+	return c.JSON(common.NewSuccessResponse(report))
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrConflict:
+		return http.StatusConflict
+	case domain.ErrBadParamInput:
+		return http.StatusBadRequest
+	case constants.ErrDataNotFound:
+		return http.StatusNotFound
+	case constants.ErrShiftNotOpen, constants.ErrShiftAlreadyOpen:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,321 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/api/transaction"
+	"mini_pos/api/v1/shift/repository"
+	"mini_pos/constants"
+	"mini_pos/domain"
+)
+
+type mysqlShiftRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlShiftRepository will create an object that represent the Shift.Repository interface
+func NewMysqlShiftRepository(Conn *sql.DB) domain.ShiftRepository {
+	return &mysqlShiftRepository{Conn}
+}
+
+func (m *mysqlShiftRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Shift, err error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Shift, 0)
+	for rows.Next() {
+		t := domain.Shift{}
+		expected := sql.NullFloat64{}
+		counted := sql.NullFloat64{}
+		note := sql.NullString{}
+		closedAt := sql.NullTime{}
+		err = rows.Scan(
+			&t.ID,
+			&t.UserID,
+			&t.OutletID,
+			&t.Status,
+			&t.OpeningFloat,
+			&expected,
+			&counted,
+			&note,
+			&t.OpenedAt,
+			&closedAt,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		if expected.Valid {
+			t.ExpectedCash = &expected.Float64
+		}
+		if counted.Valid {
+			t.CountedCash = &counted.Float64
+		}
+		if closedAt.Valid {
+			t.ClosedAt = &closedAt.Time
+		}
+		t.Note = note.String
+		result = append(result, t)
+	}
+
+	return result, nil
+}
+
+func (m *mysqlShiftRepository) Fetch(ctx context.Context, cursor string, num int64, outletId int64) (res []domain.Shift, nextCursor string, err error) {
+	query := `SELECT id, user_id, outlet_id, status, opening_float, expected_cash, counted_cash, note, opened_at, closed_at,
+				update_at, create_at
+  						FROM shifts WHERE (? = 0 or outlet_id = ?) and create_at > ? ORDER BY create_at LIMIT ? `
+
+	decodedCursor, err := repository.DecodeCursor(cursor)
+	if err != nil && cursor != "" {
+		return nil, "", domain.ErrBadParamInput
+	}
+
+	res, err = m.fetch(ctx, query, outletId, outletId, decodedCursor, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(res) == int(num) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	}
+
+	return
+}
+
+func (m *mysqlShiftRepository) GetByID(ctx context.Context, id int64) (res domain.Shift, err error) {
+	query := `SELECT id, user_id, outlet_id, status, opening_float, expected_cash, counted_cash, note, opened_at, closed_at,
+				update_at, create_at
+  						FROM shifts WHERE ID = ? `
+
+	list, err := m.fetch(ctx, query, id)
+	if err != nil {
+		return domain.Shift{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+
+	return
+}
+
+// GetOpen return the open shift of the cashier in the outlet
+func (m *mysqlShiftRepository) GetOpen(ctx context.Context, userId int64, outletId int64) (res domain.Shift, err error) {
+	query := `SELECT id, user_id, outlet_id, status, opening_float, expected_cash, counted_cash, note, opened_at, closed_at,
+				update_at, create_at
+  						FROM shifts WHERE user_id = ? and outlet_id = ? and status='open' ORDER BY opened_at DESC LIMIT 1`
+
+	list, err := m.fetch(ctx, query, userId, outletId)
+	if err != nil {
+		return domain.Shift{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+
+	return
+}
+
+// errDuplicateEntry is the mysql error of an insert breaking a unique index
+const errDuplicateEntry = 1062
+
+// Store open the shift, the shifts_one_open index refuses a second open shift of the cashier in the outlet
+func (m *mysqlShiftRepository) Store(ctx context.Context, a *domain.Shift) (err error) {
+	query := `INSERT  shifts 
+			  SET user_id=?, actor_id=?, outlet_id=?, status='open', opening_float=?, opened_at=?,
+			  update_at=? , create_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, a.UserID, nullID(a.ActorID), a.OutletID, a.OpeningFloat, now, now, now)
+	if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == errDuplicateEntry {
+		return constants.ErrShiftAlreadyOpen
+	}
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	a.Status = domain.ShiftOpen
+	a.OpenedAt = now
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	return
+}
+
+// Close keep the counted and expected cash of an open shift, it fails when the shift was already closed
+func (m *mysqlShiftRepository) Close(ctx context.Context, a *domain.Shift) (err error) {
+	query := `UPDATE shifts 
+			SET status='closed', expected_cash=?, counted_cash=?, note=?, closed_at=?, update_at=?
+			 WHERE ID = ? and status='open'`
+
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, a.ExpectedCash, a.CountedCash, a.Note, now, now, a.ID)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		return constants.ErrShiftNotOpen
+	}
+	a.Status = domain.ShiftClosed
+	a.ClosedAt = &now
+	a.UpdatedAt = now
+	return
+}
+
+func (m *mysqlShiftRepository) StoreMovement(ctx context.Context, a *domain.ShiftMovement) (err error) {
+	query := `INSERT  shift_movements 
+			  SET shift_id=?, user_id=?, actor_id=?, type=?, amount=?, reason=?,
+			  update_at=? , create_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, a.ShiftID, a.UserID, nullID(a.ActorID), a.Type, a.Amount, a.Reason, now, now)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	return
+}
+
+func (m *mysqlShiftRepository) FetchMovements(ctx context.Context, shiftId int64) (res []domain.ShiftMovement, err error) {
+	query := `SELECT id, shift_id, user_id, type, amount, reason, update_at, create_at
+  						FROM shift_movements WHERE shift_id = ? ORDER BY id`
+
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, shiftId)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	res = make([]domain.ShiftMovement, 0)
+	for rows.Next() {
+		t := domain.ShiftMovement{}
+		err = rows.Scan(
+			&t.ID,
+			&t.ShiftID,
+			&t.UserID,
+			&t.Type,
+			&t.Amount,
+			&t.Reason,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, t)
+	}
+
+	return
+}
+
+// FetchTenders sum the payments taken during the shift per method, with the number of orders they settle
+func (m *mysqlShiftRepository) FetchTenders(ctx context.Context, shiftId int64) (res []domain.ShiftTender, orders int, err error) {
+	query := `SELECT type_payment, COUNT(id), IFNULL(SUM(amount),0)
+  						FROM payments WHERE shift_id = ? GROUP BY type_payment ORDER BY type_payment`
+
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, shiftId)
+	if err != nil {
+		logrus.Error(err)
+		return nil, 0, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	res = make([]domain.ShiftTender, 0)
+	for rows.Next() {
+		t := domain.ShiftTender{}
+		err = rows.Scan(
+			&t.TypePayment,
+			&t.Count,
+			&t.Amount,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, 0, err
+		}
+		res = append(res, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	query = `SELECT COUNT(DISTINCT order_id) FROM payments WHERE shift_id = ?`
+	err = transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, shiftId).Scan(&orders)
+	return
+}
+
+// CashRefunded sum the cash given back from the drawer of the shift by refunds
+func (m *mysqlShiftRepository) CashRefunded(ctx context.Context, shiftId int64) (amount float64, err error) {
+	query := `SELECT IFNULL(SUM(amount),0) FROM refund_payments WHERE shift_id = ?`
+
+	err = transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, shiftId).Scan(&amount)
+	return
+}
+
+// nullID stores an empty foreign key as NULL instead of 0
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
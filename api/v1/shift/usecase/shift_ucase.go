@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"mini_pos/constants"
+	"time"
+
+	"mini_pos/domain"
+)
+
+type ShiftUsecase struct {
+	ShiftRepo    domain.ShiftRepository
+	Transactor    domain.Transactor
+	contextTimeout time.Duration
+}
+
+// NewShiftUsecase will create new an ShiftUsecase object representation of domain.ShiftUsecase interface
+func NewShiftUsecase(a domain.ShiftRepository, tx domain.Transactor, timeout time.Duration) domain.ShiftUsecase {
+	return &ShiftUsecase{
+		ShiftRepo:    a,
+		Transactor:    tx,
+		contextTimeout: timeout,
+	}
+}
+
+func (a *ShiftUsecase) Fetch(c context.Context, cursor string, num int64, outletId int64) (res []domain.Shift, nextCursor string, err error) {
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, nextCursor, err = a.ShiftRepo.Fetch(ctx, cursor, num, outletId)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(res) <= 0 {
+		return nil, "", constants.ErrDataNotFound
+	}
+	return
+}
+
+func (a *ShiftUsecase) Current(c context.Context, userId int64, outletId int64) (res domain.Shift, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err = a.ShiftRepo.GetOpen(ctx, userId, outletId)
+	if err == domain.ErrNotFound {
+		return domain.Shift{}, constants.ErrShiftNotOpen
+	}
+	return
+}
+
+// Open start a shift of the cashier, the repository refuses a second open shift when two opens race
+func (a *ShiftUsecase) Open(c context.Context, m *domain.RequestOpenShift) (res domain.Shift, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	_, err = a.ShiftRepo.GetOpen(ctx, m.UserID, m.OutletID)
+	if err == nil {
+		return domain.Shift{}, constants.ErrShiftAlreadyOpen
+	}
+	if err != domain.ErrNotFound {
+		return
+	}
+
+	res = domain.Shift{
+		UserID:       m.UserID,
+		ActorID:      m.ActorID,
+		OutletID:     m.OutletID,
+		OpeningFloat: domain.RoundMoney(m.OpeningFloat),
+	}
+	err = a.ShiftRepo.Store(ctx, &res)
+	if err != nil {
+		return domain.Shift{}, err
+	}
+	return
+}
+
+// getOwnedShift return the shift when it belongs to the cashier, userId is empty for a supervisor
+func (a *ShiftUsecase) getOwnedShift(ctx context.Context, id int64, userId int64) (res domain.Shift, err error) {
+	res, err = a.ShiftRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	if userId != 0 && res.UserID != userId {
+		return domain.Shift{}, domain.ErrNotFound
+	}
+	return
+}
+
+func (a *ShiftUsecase) AddMovement(c context.Context, m *domain.ShiftMovement) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	shift, err := a.getOwnedShift(ctx, m.ShiftID, m.UserID)
+	if err != nil {
+		return
+	}
+	if shift.Status != domain.ShiftOpen {
+		return constants.ErrShiftNotOpen
+	}
+	m.Amount = domain.RoundMoney(m.Amount)
+	return a.ShiftRepo.StoreMovement(ctx, m)
+}
+
+// report reconcile the shift against the payments, the cash refunds and the movements recorded during it
+func (a *ShiftUsecase) report(ctx context.Context, shift domain.Shift) (res domain.ShiftReport, err error) {
+	res = domain.ShiftReport{
+		Shift: shift,
+	}
+	res.Tenders, res.Orders, err = a.ShiftRepo.FetchTenders(ctx, shift.ID)
+	if err != nil {
+		return domain.ShiftReport{}, err
+	}
+	res.CashRefunds, err = a.ShiftRepo.CashRefunded(ctx, shift.ID)
+	if err != nil {
+		return domain.ShiftReport{}, err
+	}
+	res.Movements, err = a.ShiftRepo.FetchMovements(ctx, shift.ID)
+	if err != nil {
+		return domain.ShiftReport{}, err
+	}
+	res.Reconcile()
+	return
+}
+
+// Report return the X report of an open shift, or the Z report of a closed one
+func (a *ShiftUsecase) Report(c context.Context, id int64, userId int64) (res domain.ShiftReport, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	shift, err := a.getOwnedShift(ctx, id, userId)
+	if err != nil {
+		return
+	}
+	return a.report(ctx, shift)
+}
+
+// Close count the drawer and close the shift, the returned Z report keeps the expected cash of the close
+func (a *ShiftUsecase) Close(c context.Context, m *domain.RequestCloseShift) (res domain.ShiftReport, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	err = a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		shift, err := a.getOwnedShift(ctx, m.ID, m.UserID)
+		if err != nil {
+			return err
+		}
+		if shift.Status != domain.ShiftOpen {
+			return constants.ErrShiftNotOpen
+		}
+
+		res, err = a.report(ctx, shift)
+		if err != nil {
+			return err
+		}
+		counted := domain.RoundMoney(m.CountedCash)
+		shift.ExpectedCash = &res.ExpectedCash
+		shift.CountedCash = &counted
+		shift.Note = m.Note
+		err = a.ShiftRepo.Close(ctx, &shift)
+		if err != nil {
+			return err
+		}
+
+		res.Shift = shift
+		res.Reconcile()
+		return nil
+	})
+	if err != nil {
+		return domain.ShiftReport{}, err
+	}
+	return
+}
@@ -33,11 +33,11 @@ func NewSupplierHandler(e *echo.Echo, us domain.SupplierUsecase, cfg config.Conf
 	SupplierV1:= e.Group("")
 
 	SupplierV1.Use(middlwr.JWTMiddleware(cfg))
-	SupplierV1.GET("/suppliers", handler.FetchSupplier)
-	SupplierV1.POST("/suppliers", handler.Store)
-	SupplierV1.PUT("/suppliers", handler.Update)
-	SupplierV1.GET("/suppliers/:id", handler.GetByID)
-	SupplierV1.DELETE("/suppliers/:id", handler.Delete)
+	SupplierV1.GET("/suppliers", handler.FetchSupplier, middlwr.Permission(domain.PermSupplierRead))
+	SupplierV1.POST("/suppliers", handler.Store, middlwr.Permission(domain.PermSupplierWrite))
+	SupplierV1.PUT("/suppliers", handler.Update, middlwr.Permission(domain.PermSupplierWrite))
+	SupplierV1.GET("/suppliers/:id", handler.GetByID, middlwr.Permission(domain.PermSupplierRead))
+	SupplierV1.DELETE("/suppliers/:id", handler.Delete, middlwr.Permission(domain.PermSupplierWrite))
 }
 
 // FetchSupplier will fetch the Supplier based on given params
@@ -50,13 +50,6 @@ func (a *SupplierHandler) FetchSupplier(c echo.Context) error {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	numS := c.QueryParam("num")
 	num, _ := strconv.Atoi(numS)
 	cursor := c.QueryParam("cursor")
@@ -85,13 +78,6 @@ func (a *SupplierHandler) GetByID(c echo.Context) error {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
@@ -122,13 +108,6 @@ func (a *SupplierHandler) GetByName(c echo.Context) error {
 		}))
 	}
 
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	sku := c.Param("name")
 
 	ctx := c.Request().Context()
@@ -160,13 +139,6 @@ func (a *SupplierHandler) Update(c echo.Context) (err error) {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	var Supplier domain.Supplier
 	err = c.Bind(&Supplier)
 	if err != nil {
@@ -198,13 +170,6 @@ func (a *SupplierHandler) Store(c echo.Context) (err error) {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	var Supplier domain.Supplier
 	err = c.Bind(&Supplier)
 	if err != nil {
@@ -235,13 +200,6 @@ func (a *SupplierHandler) Delete(c echo.Context) error {
 			Data:    nil,
 		}))
 	}
-	if claims.RoleID != 1 {
-		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
-			Code:    http.StatusUnauthorized,
-			Message: constants.ErrNotAuthorized.Error(),
-			Data:    nil,
-		}))
-	}
 	idP, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		return c.JSON(common.NewErrorResponse(common.ControllerResponse{
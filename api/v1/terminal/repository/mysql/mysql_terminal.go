@@ -0,0 +1,169 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/api/transaction"
+	"mini_pos/api/v1/terminal/repository"
+	"mini_pos/domain"
+)
+
+type mysqlTerminalRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlTerminalRepository will create an object that represent the domain.TerminalRepository interface
+func NewMysqlTerminalRepository(Conn *sql.DB) domain.TerminalRepository {
+	return &mysqlTerminalRepository{Conn}
+}
+
+func (m *mysqlTerminalRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Terminal, err error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Terminal, 0)
+	for rows.Next() {
+		t := domain.Terminal{}
+		lastSeenAt := sql.NullTime{}
+		err = rows.Scan(
+			&t.ID,
+			&t.OutletID,
+			&t.Name,
+			&t.Status,
+			&t.CreatedBy,
+			&lastSeenAt,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		if lastSeenAt.Valid {
+			t.LastSeenAt = &lastSeenAt.Time
+		}
+		result = append(result, t)
+	}
+
+	return result, nil
+}
+
+func (m *mysqlTerminalRepository) Fetch(ctx context.Context, cursor string, num int64, outletId int64) (res []domain.Terminal, nextCursor string, err error) {
+	query := `SELECT id, outlet_id, name, status, created_by, last_seen_at, update_at, create_at
+  						FROM terminals WHERE create_at > ? and (? = 0 or outlet_id = ?) ORDER BY create_at LIMIT ? `
+
+	decodedCursor, err := repository.DecodeCursor(cursor)
+	if err != nil && cursor != "" {
+		return nil, "", domain.ErrBadParamInput
+	}
+
+	res, err = m.fetch(ctx, query, decodedCursor, outletId, outletId, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(res) == int(num) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	}
+
+	return
+}
+
+func (m *mysqlTerminalRepository) getOne(ctx context.Context, query string, args ...interface{}) (res domain.Terminal, err error) {
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return domain.Terminal{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+
+	return
+}
+
+func (m *mysqlTerminalRepository) GetByID(ctx context.Context, id int64) (res domain.Terminal, err error) {
+	query := `SELECT id, outlet_id, name, status, created_by, last_seen_at, update_at, create_at
+  						FROM terminals WHERE ID = ? `
+	return m.getOne(ctx, query, id)
+}
+
+// GetByToken return the terminal of a device token, only the sha256 of the token is stored
+func (m *mysqlTerminalRepository) GetByToken(ctx context.Context, tokenHash string) (res domain.Terminal, err error) {
+	query := `SELECT id, outlet_id, name, status, created_by, last_seen_at, update_at, create_at
+  						FROM terminals WHERE token_hash = ? `
+	return m.getOne(ctx, query, tokenHash)
+}
+
+func (m *mysqlTerminalRepository) Store(ctx context.Context, a *domain.Terminal, tokenHash string) (err error) {
+	query := `INSERT  terminals 
+			  SET outlet_id=?, name=?, token_hash=?, status=?, created_by=?, update_at=? , create_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, a.OutletID, a.Name, tokenHash, a.Status, a.CreatedBy, now, now)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	a.UpdatedAt = now
+	a.CreatedAt = now
+	return
+}
+
+func (m *mysqlTerminalRepository) SetStatus(ctx context.Context, id int64, status string) (err error) {
+	query := `UPDATE terminals SET status=?, update_at=? WHERE ID = ?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, status, time.Now(), id)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+	}
+	return
+}
+
+// Touch record the last time a cashier switched on the terminal
+func (m *mysqlTerminalRepository) Touch(ctx context.Context, id int64) (err error) {
+	query := `UPDATE terminals SET last_seen_at=? WHERE ID = ?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	_, err = stmt.ExecContext(ctx, time.Now(), id)
+	return
+}
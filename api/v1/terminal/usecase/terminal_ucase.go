@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"mini_pos/constants"
+	"mini_pos/domain"
+	"mini_pos/utils"
+)
+
+type TerminalUsecase struct {
+	TerminalRepo   domain.TerminalRepository
+	OutletRepo     domain.OutletRepository
+	SessionRepo    domain.SessionRepository
+	contextTimeout time.Duration
+}
+
+// NewTerminalUsecase will create new an TerminalUsecase object representation of domain.TerminalUsecase interface
+func NewTerminalUsecase(a domain.TerminalRepository, outlet domain.OutletRepository, session domain.SessionRepository, timeout time.Duration) domain.TerminalUsecase {
+	return &TerminalUsecase{
+		TerminalRepo:   a,
+		OutletRepo:     outlet,
+		SessionRepo:    session,
+		contextTimeout: timeout,
+	}
+}
+
+func (a *TerminalUsecase) Fetch(c context.Context, cursor string, num int64, outletId int64) (res []domain.Terminal, nextCursor string, err error) {
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, nextCursor, err = a.TerminalRepo.Fetch(ctx, cursor, num, outletId)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(res) <= 0 {
+		return nil, "", constants.ErrDataNotFound
+	}
+	return
+}
+
+func (a *TerminalUsecase) GetByID(c context.Context, id int64) (res domain.Terminal, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.TerminalRepo.GetByID(ctx, id)
+}
+
+// Register trust a new terminal for the outlet, the device token is answered once and only its hash is kept
+func (a *TerminalUsecase) Register(c context.Context, m *domain.Terminal) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	_, err = a.OutletRepo.GetByID(ctx, m.OutletID)
+	if err != nil {
+		return
+	}
+
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		return
+	}
+	m.Status = domain.TerminalActive
+	err = a.TerminalRepo.Store(ctx, m, utils.HashToken(token))
+	if err != nil {
+		return
+	}
+	m.Token = token
+	return
+}
+
+// Revoke stop trusting the terminal, the cashiers can no longer switch on it and the PIN tokens
+// already issued on it are rejected. Revoking again only makes sure those tokens are rejected
+func (a *TerminalUsecase) Revoke(c context.Context, id int64) (res domain.Terminal, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, err = a.TerminalRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	if res.Status != domain.TerminalRevoked {
+		err = a.TerminalRepo.SetStatus(ctx, id, domain.TerminalRevoked)
+		if err != nil {
+			return domain.Terminal{}, err
+		}
+		res.Status = domain.TerminalRevoked
+	}
+	err = a.SessionRepo.RevokeTerminal(ctx, id)
+	if err != nil {
+		return domain.Terminal{}, err
+	}
+	return
+}
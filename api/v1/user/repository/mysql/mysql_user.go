@@ -0,0 +1,276 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/api/transaction"
+	"mini_pos/api/v1/user/repository"
+	"mini_pos/constants"
+	"mini_pos/domain"
+)
+
+// errRowReferenced is the mysql error of a delete restricted by a foreign key
+const errRowReferenced = 1451
+
+type mysqlUserRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlUserRepository will create an object that represent the domain.UserRepository interface
+func NewMysqlUserRepository(Conn *sql.DB) domain.UserRepository {
+	return &mysqlUserRepository{Conn}
+}
+
+func (m *mysqlUserRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.UserAccount, err error) {
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.UserAccount, 0)
+	for rows.Next() {
+		t := domain.UserAccount{}
+		role := sql.NullString{}
+		updatedAt := sql.NullTime{}
+		createdAt := sql.NullTime{}
+		err = rows.Scan(
+			&t.ID,
+			&t.Name,
+			&t.UserName,
+			&t.Email,
+			&t.RoleID,
+			&role,
+			&t.Status,
+			&updatedAt,
+			&createdAt,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		t.Role = role.String
+		if updatedAt.Valid {
+			t.UpdatedAt = &updatedAt.Time
+		}
+		t.CreatedAt = createdAt.Time
+		result = append(result, t)
+	}
+
+	return result, nil
+}
+
+func (m *mysqlUserRepository) Fetch(ctx context.Context, cursor string, num int64, filter domain.UserFilter) (res []domain.UserAccount, nextCursor string, err error) {
+	query := `SELECT users.id, users.fullname, users.username, users.email, users.role_id, roles.name, users.status, users.updated_at, users.created_at
+  						FROM users left join roles on roles.id = users.role_id
+  						WHERE users.created_at > ?
+  						and (? = '' or users.fullname like ? or users.username like ? or users.email like ?)
+  						and (? = 0 or users.role_id = ?) and (? = '' or users.status = ?)
+  						ORDER BY users.created_at LIMIT ? `
+
+	decodedCursor, err := repository.DecodeCursor(cursor)
+	if err != nil && cursor != "" {
+		return nil, "", domain.ErrBadParamInput
+	}
+
+	search := "%" + filter.Search + "%"
+	res, err = m.fetch(ctx, query, decodedCursor, filter.Search, search, search, search,
+		filter.RoleID, filter.RoleID, filter.Status, filter.Status, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(res) == int(num) {
+		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	}
+
+	return
+}
+
+func (m *mysqlUserRepository) GetByID(ctx context.Context, id int) (res domain.UserAccount, err error) {
+	query := `SELECT users.id, users.fullname, users.username, users.email, users.role_id, roles.name, users.status, users.updated_at, users.created_at
+  						FROM users left join roles on roles.id = users.role_id WHERE users.id = ? `
+
+	list, err := m.fetch(ctx, query, id)
+	if err != nil {
+		return domain.UserAccount{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+
+	return
+}
+
+// GetByUserNameOrEmail return the users holding the username or the email, both are unique
+func (m *mysqlUserRepository) GetByUserNameOrEmail(ctx context.Context, username string, email string) (res []domain.UserAccount, err error) {
+	query := `SELECT users.id, users.fullname, users.username, users.email, users.role_id, roles.name, users.status, users.updated_at, users.created_at
+  						FROM users left join roles on roles.id = users.role_id WHERE users.username = ? or users.email = ? `
+
+	return m.fetch(ctx, query, username, email)
+}
+
+func (m *mysqlUserRepository) Store(ctx context.Context, a *domain.UserAccount, password string) (err error) {
+	query := `INSERT  users 
+			  SET fullname=?, username=?, email=?, password=?, role_id=?, status=?, created_at=?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	a.CreatedAt = time.Now()
+	res, err := stmt.ExecContext(ctx, a.Name, a.UserName, a.Email, password, a.RoleID, a.Status, a.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = int(lastID)
+	return
+}
+
+func (m *mysqlUserRepository) Update(ctx context.Context, a *domain.RequestUpdateUser) (err error) {
+	query := `UPDATE users SET fullname=?, username=?, email=?, updated_at=? WHERE id = ?`
+	return m.exec(ctx, query, a.Name, a.UserName, a.Email, time.Now(), a.ID)
+}
+
+func (m *mysqlUserRepository) SetRole(ctx context.Context, id int, roleId int) (err error) {
+	query := `UPDATE users SET role_id=?, updated_at=? WHERE id = ?`
+	return m.exec(ctx, query, roleId, time.Now(), id)
+}
+
+func (m *mysqlUserRepository) SetStatus(ctx context.Context, id int, status string) (err error) {
+	query := `UPDATE users SET status=?, updated_at=? WHERE id = ?`
+	return m.exec(ctx, query, status, time.Now(), id)
+}
+
+func (m *mysqlUserRepository) SetPassword(ctx context.Context, id int, password string) (err error) {
+	query := `UPDATE users SET password=?, updated_at=? WHERE id = ?`
+	return m.exec(ctx, query, password, time.Now(), id)
+}
+
+// exec run an update of a single user, a missing user is ErrNotFound
+func (m *mysqlUserRepository) exec(ctx context.Context, query string, args ...interface{}) (err error) {
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	res, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		return domain.ErrNotFound
+	}
+	if affect != 1 {
+		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", affect)
+	}
+	return
+}
+
+func (m *mysqlUserRepository) RoleExists(ctx context.Context, roleId int) (bool, error) {
+	query := `SELECT id FROM roles WHERE id = ?`
+
+	var id int
+	err := transaction.Conn(ctx, m.Conn).QueryRowContext(ctx, query, roleId).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (m *mysqlUserRepository) FetchOutlets(ctx context.Context, id int) (res []int64, err error) {
+	query := `SELECT outlet_id FROM user_outlets WHERE user_id = ? ORDER BY outlet_id`
+
+	rows, err := transaction.Conn(ctx, m.Conn).QueryContext(ctx, query, id)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	res = make([]int64, 0)
+	for rows.Next() {
+		var outletID int64
+		err = rows.Scan(&outletID)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, outletID)
+	}
+	return
+}
+
+// AssignOutlet add the outlet to the user, assigning it twice keeps a single row
+func (m *mysqlUserRepository) AssignOutlet(ctx context.Context, id int, outletId int64) (err error) {
+	query := `INSERT INTO user_outlets (user_id, outlet_id)
+			SELECT ?, ? FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM user_outlets WHERE user_id = ? and outlet_id = ?)`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	_, err = stmt.ExecContext(ctx, id, outletId, id, outletId)
+	return
+}
+
+func (m *mysqlUserRepository) UnassignOutlet(ctx context.Context, id int, outletId int64) (err error) {
+	query := `DELETE FROM user_outlets WHERE user_id = ? and outlet_id = ?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	_, err = stmt.ExecContext(ctx, id, outletId)
+	return
+}
+
+// Delete remove the user with its outlets, a user with sales or shifts is kept and ErrUserInUse is returned
+func (m *mysqlUserRepository) Delete(ctx context.Context, id int) (err error) {
+	query := `DELETE FROM user_outlets WHERE user_id = ?`
+	stmt, err := transaction.Conn(ctx, m.Conn).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	_, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	err = m.exec(ctx, `DELETE FROM users WHERE id = ?`, id)
+	if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == errRowReferenced {
+		return constants.ErrUserInUse
+	}
+	return
+}
@@ -0,0 +1,52 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	_userRepo "mini_pos/api/v1/user/repository/mysql"
+	"mini_pos/constants"
+	"mini_pos/domain"
+)
+
+func TestFetch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "fullname", "username", "email", "role_id", "name", "status", "updated_at", "created_at"}).
+		AddRow(7, "Admin", "fachry45", "admin@admin.com", 1, "super admin", "active", nil, time.Now())
+
+	mock.ExpectQuery("SELECT (.+) FROM users").
+		WithArgs(sqlmock.AnyArg(), "adm", "%adm%", "%adm%", "%adm%", 0, 0, "active", "active", int64(1)).
+		WillReturnRows(rows)
+
+	a := _userRepo.NewMysqlUserRepository(db)
+	list, nextCursor, err := a.Fetch(context.TODO(), "", 1, domain.UserFilter{Search: "adm", Status: domain.UserActive})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, nextCursor)
+	assert.Len(t, list, 1)
+	assert.Equal(t, "super admin", list[0].Role)
+	assert.Nil(t, list[0].UpdatedAt)
+}
+
+func TestDeleteInUse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectPrepare("DELETE FROM user_outlets").ExpectExec().WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectPrepare("DELETE FROM users").ExpectExec().WithArgs(7).
+		WillReturnError(&mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row"})
+
+	a := _userRepo.NewMysqlUserRepository(db)
+	err = a.Delete(context.TODO(), 7)
+	assert.Equal(t, constants.ErrUserInUse, err)
+}
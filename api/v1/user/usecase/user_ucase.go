@@ -0,0 +1,475 @@
+package usecase
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mini_pos/config"
+	"mini_pos/constants"
+	"mini_pos/domain"
+	"mini_pos/utils"
+)
+
+// setPasswordLifeTime is how long the link mailed on invite or on a reset by the admin stays valid
+const setPasswordLifeTime = 72 * time.Hour
+
+type UserUsecase struct {
+	UserRepo       domain.UserRepository
+	OutletRepo     domain.OutletRepository
+	SessionRepo    domain.SessionRepository
+	OTPRepo        domain.OTPRepository
+	Mailer         domain.Mailer
+	Transactor     domain.Transactor
+	cfg            config.Config
+	contextTimeout time.Duration
+}
+
+// NewUserUsecase will create new an UserUsecase object representation of domain.UserUsecase interface
+func NewUserUsecase(u domain.UserRepository, outlet domain.OutletRepository, session domain.SessionRepository,
+	otp domain.OTPRepository, mailer domain.Mailer, tx domain.Transactor, cfg config.Config, timeout time.Duration) domain.UserUsecase {
+	return &UserUsecase{
+		UserRepo:       u,
+		OutletRepo:     outlet,
+		SessionRepo:    session,
+		OTPRepo:        otp,
+		Mailer:         mailer,
+		Transactor:     tx,
+		cfg:            cfg,
+		contextTimeout: timeout,
+	}
+}
+
+func (a *UserUsecase) Fetch(c context.Context, cursor string, num int64, filter domain.UserFilter) (res []domain.UserAccount, nextCursor string, err error) {
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	res, nextCursor, err = a.UserRepo.Fetch(ctx, cursor, num, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(res) <= 0 {
+		return nil, "", constants.ErrDataNotFound
+	}
+	for i := range res {
+		res[i].Outlets, err = a.UserRepo.FetchOutlets(ctx, res[i].ID)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	return
+}
+
+// getUser return the user with its outlets
+func (a *UserUsecase) getUser(ctx context.Context, id int) (res domain.UserAccount, err error) {
+	res, err = a.UserRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	res.Outlets, err = a.UserRepo.FetchOutlets(ctx, id)
+	if err != nil {
+		return domain.UserAccount{}, err
+	}
+	return
+}
+
+func (a *UserUsecase) GetByID(c context.Context, id int) (res domain.UserAccount, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.getUser(ctx, id)
+}
+
+// checkTaken return ErrConflict when another user already holds the username or the email
+func (a *UserUsecase) checkTaken(ctx context.Context, id int, username string, email string) error {
+	list, err := a.UserRepo.GetByUserNameOrEmail(ctx, username, email)
+	if err != nil {
+		return err
+	}
+	for _, u := range list {
+		if u.ID != id {
+			return domain.ErrConflict
+		}
+	}
+	return nil
+}
+
+func (a *UserUsecase) checkRole(ctx context.Context, roleId int) error {
+	ok, err := a.UserRepo.RoleExists(ctx, roleId)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return constants.ErrRoleNotFound
+	}
+	return nil
+}
+
+func (a *UserUsecase) checkOutlets(ctx context.Context, outlets []int64) error {
+	for _, id := range outlets {
+		_, err := a.OutletRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unusablePassword return the hash of a random password nobody knows, the user sets its own from the mailed link
+func unusablePassword() (string, error) {
+	password, err := utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	return utils.HashPassword(password)
+}
+
+// mailSetPassword mail a link to set the password, the token is the reset token of /auth/password/reset
+func (a *UserUsecase) mailSetPassword(ctx context.Context, user domain.UserAccount, intro string) (err error) {
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		return
+	}
+	err = a.OTPRepo.StoreResetToken(ctx, token, user.ID, setPasswordLifeTime)
+	if err != nil {
+		return
+	}
+
+	link := ""
+	if a.cfg.ResetPassword.UserLink != "" {
+		link = a.cfg.ResetPassword.UserLink + "?token=" + url.QueryEscape(token)
+	}
+	return a.Mailer.Send(ctx, user.Email, "Set Password", constants.MailSetPasswordTemplate, domain.SetPasswordMail{
+		Name:          user.Name,
+		Intro:         intro,
+		Link:          link,
+		Token:         token,
+		LogoURL:       constants.DESILogoURL,
+		ExpiresInHour: int(setPasswordLifeTime / time.Hour),
+	})
+}
+
+// Invite create an active user in its role and outlets, and mail it the link to set its password.
+// A failed mail does not undo the user, the admin can send the link again with a password reset
+func (a *UserUsecase) Invite(c context.Context, m *domain.RequestInviteUser) (res domain.UserAccount, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	err = a.checkRole(ctx, m.RoleID)
+	if err != nil {
+		return
+	}
+	err = a.checkOutlets(ctx, m.Outlets)
+	if err != nil {
+		return
+	}
+	err = a.checkTaken(ctx, 0, m.UserName, m.Email)
+	if err != nil {
+		return
+	}
+	password, err := unusablePassword()
+	if err != nil {
+		return
+	}
+
+	user := domain.UserAccount{
+		Name:     m.Name,
+		UserName: m.UserName,
+		Email:    m.Email,
+		RoleID:   m.RoleID,
+		Status:   domain.UserActive,
+	}
+	err = a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := a.UserRepo.Store(ctx, &user, password)
+		if err != nil {
+			return err
+		}
+		for _, outletId := range m.Outlets {
+			err = a.UserRepo.AssignOutlet(ctx, user.ID, outletId)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.UserAccount{}, err
+	}
+
+	errMail := a.mailSetPassword(ctx, user, "An account was created for you, set your password to start using it.")
+	if errMail != nil {
+		logrus.Error(errMail)
+	}
+	return a.getUser(ctx, user.ID)
+}
+
+func (a *UserUsecase) Update(c context.Context, m *domain.RequestUpdateUser) (res domain.UserAccount, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	_, err = a.UserRepo.GetByID(ctx, m.ID)
+	if err != nil {
+		return
+	}
+	err = a.checkTaken(ctx, m.ID, m.UserName, m.Email)
+	if err != nil {
+		return
+	}
+	err = a.UserRepo.Update(ctx, m)
+	if err != nil {
+		return
+	}
+	return a.getUser(ctx, m.ID)
+}
+
+// SetRole move the user to another role, its sessions are ended so the permissions of the old role
+// stop working at once and the new ones are in the token of its next login
+func (a *UserUsecase) SetRole(c context.Context, id int, roleId int) (res domain.UserAccount, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	user, err := a.UserRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	err = a.checkRole(ctx, roleId)
+	if err != nil {
+		return
+	}
+	err = a.UserRepo.SetRole(ctx, id, roleId)
+	if err != nil {
+		return
+	}
+	if user.RoleID != roleId {
+		err = a.SessionRepo.RevokeUser(ctx, id, time.Now())
+		if err != nil {
+			return
+		}
+	}
+	return a.getUser(ctx, id)
+}
+
+// SetStatus suspend or reactivate the user, the sessions of a suspended user are ended at once.
+// A pending user goes through Approve instead
+func (a *UserUsecase) SetStatus(c context.Context, id int, status string) (res domain.UserAccount, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	user, err := a.UserRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	if user.Status == domain.UserPending {
+		return domain.UserAccount{}, constants.ErrUserPending
+	}
+	err = a.UserRepo.SetStatus(ctx, id, status)
+	if err != nil {
+		return
+	}
+	if status == domain.UserSuspended {
+		err = a.SessionRepo.RevokeUser(ctx, id, time.Now())
+		if err != nil {
+			return
+		}
+	}
+	return a.getUser(ctx, id)
+}
+
+// ResetPassword lock the user out of its current password and sessions, and mail it the link to set a new one
+func (a *UserUsecase) ResetPassword(c context.Context, id int) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	user, err := a.UserRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	password, err := unusablePassword()
+	if err != nil {
+		return
+	}
+	err = a.UserRepo.SetPassword(ctx, id, password)
+	if err != nil {
+		return
+	}
+	err = a.SessionRepo.RevokeUser(ctx, id, time.Now())
+	if err != nil {
+		return
+	}
+	return a.mailSetPassword(ctx, user, "Your password was reset by an administrator, set a new password to keep using your account.")
+}
+
+// SetOutlets replace the outlets of the user, its sessions are ended when an outlet is taken away
+// so the outlet stops working at once. The outlets added come in the token of its next login or refresh
+func (a *UserUsecase) SetOutlets(c context.Context, id int, outlets []int64) (res domain.UserAccount, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	_, err = a.UserRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	err = a.checkOutlets(ctx, outlets)
+	if err != nil {
+		return
+	}
+
+	removed := false
+	err = a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := a.UserRepo.FetchOutlets(ctx, id)
+		if err != nil {
+			return err
+		}
+		keep := make(map[int64]bool)
+		for _, outletId := range outlets {
+			keep[outletId] = true
+			err = a.UserRepo.AssignOutlet(ctx, id, outletId)
+			if err != nil {
+				return err
+			}
+		}
+		for _, outletId := range current {
+			if keep[outletId] {
+				continue
+			}
+			err = a.UserRepo.UnassignOutlet(ctx, id, outletId)
+			if err != nil {
+				return err
+			}
+			removed = true
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.UserAccount{}, err
+	}
+	if removed {
+		err = a.SessionRepo.RevokeUser(ctx, id, time.Now())
+		if err != nil {
+			return
+		}
+	}
+	return a.getUser(ctx, id)
+}
+
+func (a *UserUsecase) AssignOutlet(c context.Context, id int, outletId int64) (res domain.UserAccount, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	_, err = a.UserRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	err = a.checkOutlets(ctx, []int64{outletId})
+	if err != nil {
+		return
+	}
+	err = a.UserRepo.AssignOutlet(ctx, id, outletId)
+	if err != nil {
+		return
+	}
+	return a.getUser(ctx, id)
+}
+
+// UnassignOutlet take an outlet away from the user, its sessions are ended so the outlet stops working at once
+func (a *UserUsecase) UnassignOutlet(c context.Context, id int, outletId int64) (res domain.UserAccount, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	_, err = a.UserRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	err = a.UserRepo.UnassignOutlet(ctx, id, outletId)
+	if err != nil {
+		return
+	}
+	err = a.SessionRepo.RevokeUser(ctx, id, time.Now())
+	if err != nil {
+		return
+	}
+	return a.getUser(ctx, id)
+}
+
+// Delete remove a user that never worked, its sessions are ended as well
+func (a *UserUsecase) Delete(c context.Context, id int) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	err = a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return a.UserRepo.Delete(ctx, id)
+	})
+	if err != nil {
+		return
+	}
+	return a.SessionRepo.RevokeUser(ctx, id, time.Now())
+}
+
+// getPending return the user when it still waits for approval
+func (a *UserUsecase) getPending(ctx context.Context, id int) (res domain.UserAccount, err error) {
+	res, err = a.UserRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	if res.Status != domain.UserPending {
+		return domain.UserAccount{}, constants.ErrUserNotPending
+	}
+	return
+}
+
+// Approve activate a user that signed up itself into a role and outlets
+func (a *UserUsecase) Approve(c context.Context, id int, m *domain.RequestApproveUser) (res domain.UserAccount, err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	err = a.checkRole(ctx, m.RoleID)
+	if err != nil {
+		return
+	}
+	err = a.checkOutlets(ctx, m.Outlets)
+	if err != nil {
+		return
+	}
+
+	err = a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := a.getPending(ctx, id)
+		if err != nil {
+			return err
+		}
+		err = a.UserRepo.SetRole(ctx, id, m.RoleID)
+		if err != nil {
+			return err
+		}
+		for _, outletId := range m.Outlets {
+			err = a.UserRepo.AssignOutlet(ctx, id, outletId)
+			if err != nil {
+				return err
+			}
+		}
+		return a.UserRepo.SetStatus(ctx, id, domain.UserActive)
+	})
+	if err != nil {
+		return domain.UserAccount{}, err
+	}
+	return a.getUser(ctx, id)
+}
+
+// Reject remove a user that signed up itself before it was ever approved
+func (a *UserUsecase) Reject(c context.Context, id int) (err error) {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	return a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := a.getPending(ctx, id)
+		if err != nil {
+			return err
+		}
+		return a.UserRepo.Delete(ctx, id)
+	})
+}
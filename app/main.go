@@ -7,6 +7,7 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 
 	"log"
+	"mini_pos/api/transaction"
 	"mini_pos/config"
 	"net/url"
 	"os"
@@ -14,19 +15,48 @@ import (
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/gomodule/redigo/redis"
 	"github.com/labstack/echo/v4"
 
+	_cache "mini_pos/api/cache"
+	_mailer "mini_pos/api/mailer"
+	middlwr "mini_pos/api/middleware"
+
 
 	_authHttpDelivery "mini_pos/api/v1/auth/delivery/http"
 	_authRepo "mini_pos/api/v1/auth/repository/mysql"
+	_sessionRepo "mini_pos/api/v1/auth/repository/cache"
 	_authUcase "mini_pos/api/v1/auth/usecase"
 
+	_auditHttpDelivery "mini_pos/api/v1/audit/delivery/http"
+	_auditRepo "mini_pos/api/v1/audit/repository/mysql"
+	_auditUcase "mini_pos/api/v1/audit/usecase"
+
+	_apiKeyHttpDelivery "mini_pos/api/v1/api_key/delivery/http"
+	_apiKeyRepo "mini_pos/api/v1/api_key/repository/mysql"
+	_apiKeyUcase "mini_pos/api/v1/api_key/usecase"
+
+	_terminalHttpDelivery "mini_pos/api/v1/terminal/delivery/http"
+	_terminalRepo "mini_pos/api/v1/terminal/repository/mysql"
+	_terminalUcase "mini_pos/api/v1/terminal/usecase"
+
+	_userHttpDelivery "mini_pos/api/v1/user/delivery/http"
+	_userRepo "mini_pos/api/v1/user/repository/mysql"
+	_userUcase "mini_pos/api/v1/user/usecase"
+
 	_productHttpDelivery "mini_pos/api/v1/product/delivery/http"
 	_productRepo "mini_pos/api/v1/product/repository/mysql"
 	_productUcase "mini_pos/api/v1/product/usecase"
 
+	_barcodeHttpDelivery "mini_pos/api/v1/barcode/delivery/http"
+	_barcodeRepo "mini_pos/api/v1/barcode/repository/mysql"
+	_barcodeUcase "mini_pos/api/v1/barcode/usecase"
+	_categoryHttpDelivery "mini_pos/api/v1/category/delivery/http"
 	_categoryRepo "mini_pos/api/v1/category/repository/mysql"
+	_categoryUcase "mini_pos/api/v1/category/usecase"
+	_outletHttpDelivery "mini_pos/api/v1/outlet/delivery/http"
 	_outletRepo "mini_pos/api/v1/outlet/repository/mysql"
+	_outletUcase "mini_pos/api/v1/outlet/usecase"
 
 	_supplierHttpDelivery "mini_pos/api/v1/supplier/delivery/http"
 	_supplierRepo "mini_pos/api/v1/supplier/repository/mysql"
@@ -53,6 +83,17 @@ import (
 	_paymentRepo "mini_pos/api/v1/payment/repository/mysql"
 	_paymentUcase "mini_pos/api/v1/payment/usecase"
 
+	_refundHttpDelivery "mini_pos/api/v1/refund/delivery/http"
+	_refundRepo "mini_pos/api/v1/refund/repository/mysql"
+	_refundUcase "mini_pos/api/v1/refund/usecase"
+
+	_receiptHttpDelivery "mini_pos/api/v1/receipt/delivery/http"
+	_receiptRepo "mini_pos/api/v1/receipt/repository/mysql"
+	_receiptUcase "mini_pos/api/v1/receipt/usecase"
+	_shiftHttpDelivery "mini_pos/api/v1/shift/delivery/http"
+	_shiftRepo "mini_pos/api/v1/shift/repository/mysql"
+	_shiftUcase "mini_pos/api/v1/shift/usecase"
+
 	_mediaHttpDelivery "mini_pos/api/v1/media/delivery/http"
 	_mediaUcase "mini_pos/api/v1/media/usecase"
 )
@@ -83,6 +124,16 @@ func dbConnection(cfg *config.DBConfig) *sql.DB{
 	return dbConn
 }
 
+func redisPool(cfg *config.RedisServer) *redis.Pool {
+	return &redis.Pool{
+		MaxIdle:     cfg.MaxIdle,
+		IdleTimeout: time.Duration(cfg.Timeout) * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", cfg.Addr, redis.DialPassword(cfg.Password))
+		},
+	}
+}
+
 func main() {
 	cfg := config.InitConfig()
 
@@ -93,6 +144,8 @@ func main() {
 	}
 
 	e := echo.New()
+	// the client IP counts the failed logins, X-Forwarded-For is trusted only from a proxy on a private network
+	e.IPExtractor = echo.ExtractIPFromXFFHeader()
 
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{ "http://localhost:9090"},
@@ -103,22 +156,61 @@ func main() {
 		Format: "method=${method}, uri=${uri}, status=${status}\n",
 	}))
 	timeoutContext := time.Duration(cfg.Server.WriteTimeout) * time.Second
+	transactor := transaction.NewSqlTransactor(connection)
+	cache := _cache.NewRedisCache(redisPool(&cfg.Redis))
+
+	keys, err := middlwr.NewKeySet(cfg.JWTConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	middlwr.SetKeySet(keys)
 
 	//Auth
 	authRepo := _authRepo.NewMysqlAuthRepository(connection)
-	auth := _authUcase.NewAuthUseCase(authRepo, timeoutContext)
+	sessionRepo := _sessionRepo.NewCacheSessionRepository(cache)
+	otpRepo := _sessionRepo.NewCacheOTPRepository(cache)
+	mailer := _mailer.NewSMTPMailer(cfg.Mailer)
+	attemptRepo := _sessionRepo.NewCacheLoginAttemptRepository(cache)
+	twoFactorRepo := _sessionRepo.NewCacheTwoFactorRepository(cache)
+	terminalRepo := _terminalRepo.NewMysqlTerminalRepository(connection)
+	auditRepo := _auditRepo.NewMysqlAuditRepository(connection)
+	auth := _authUcase.NewAuthUseCase(authRepo, sessionRepo, otpRepo, attemptRepo, twoFactorRepo, terminalRepo, auditRepo, mailer, cfg, timeoutContext)
+	middlwr.SetRevocationList(auth)
 	_authHttpDelivery.NewAuthHandler(e, auth, cfg)
 
+	audit := _auditUcase.NewAuditUsecase(auditRepo, timeoutContext)
+	middlwr.SetAuditor(audit)
+	_auditHttpDelivery.NewAuditHandler(e, audit, cfg)
+
 	supplierRepo := _supplierRepo.NewMysqlSupplierRepository(connection)
 	supplier := _supplierUcase.NewSupplierUsecase(supplierRepo,  timeoutContext)
 	_supplierHttpDelivery.NewSupplierHandler(e, supplier, cfg)
 
 	categoryRepo := _categoryRepo.NewMysqlCategoryRepository(connection)
 	outletRepo := _outletRepo.NewMysqlOutletRepository(connection)
+	outlet := _outletUcase.NewOutletUsecase(outletRepo, sessionRepo, timeoutContext)
+	_outletHttpDelivery.NewOutletHandler(e, outlet, cfg)
+
+	userRepo := _userRepo.NewMysqlUserRepository(connection)
+	user := _userUcase.NewUserUsecase(userRepo, outletRepo, sessionRepo, otpRepo, mailer, transactor, cfg, timeoutContext)
+	_userHttpDelivery.NewUserHandler(e, user, cfg)
+
+	terminal := _terminalUcase.NewTerminalUsecase(terminalRepo, outletRepo, sessionRepo, timeoutContext)
+	_terminalHttpDelivery.NewTerminalHandler(e, terminal, cfg)
+
+	apiKeyRepo := _apiKeyRepo.NewMysqlApiKeyRepository(connection)
+	apiKey := _apiKeyUcase.NewApiKeyUsecase(apiKeyRepo, outletRepo, userRepo, transactor, timeoutContext)
+	middlwr.SetApiKeys(apiKey)
+	_apiKeyHttpDelivery.NewApiKeyHandler(e, apiKey, cfg)
+
 	productRepo := _productRepo.NewMysqlProductRepository(connection)
-	product := _productUcase.NewproductUsecase(productRepo, categoryRepo, timeoutContext)
+	productVariantRepo := _productRepo.NewMysqlProductVariantRepository(connection)
+	product := _productUcase.NewproductUsecase(productRepo, categoryRepo, productVariantRepo, transactor, timeoutContext)
 	_productHttpDelivery.NewProductHandler(e, product, cfg)
 
+	category := _categoryUcase.NewCategoryUsecase(categoryRepo, productRepo, outletRepo, timeoutContext)
+	_categoryHttpDelivery.NewCategoryHandler(e, category, cfg)
+
 	customerRepo := _customerRepo.NewMysqlCustomerRepository(connection)
 	customer := _customerUcase.NewCustomerUsecase(customerRepo,  timeoutContext)
 	_customerHttpDelivery.NewCustomerHandler(e, customer, cfg)
@@ -128,18 +220,34 @@ func main() {
 	_purchaseHttpDelivery.NewPurchaseHandler(e, purchase, cfg)
 
 	productOutletRepo := _product_outletRepo.NewMysqlProductOutletsRepository(connection)
-	productOutlet := _product_outletUcase.NewProductOutletsUsecase(productOutletRepo, productRepo, outletRepo, supplierRepo, timeoutContext)
+	productOutlet := _product_outletUcase.NewProductOutletsUsecase(productOutletRepo, productRepo, productVariantRepo, outletRepo, supplierRepo, timeoutContext)
 	_product_outletHttpDelivery.NewProductOutletsHandler(e, productOutlet, cfg)
 
+	barcodeRepo := _barcodeRepo.NewMysqlBarcodeRepository(connection)
+	barcode := _barcodeUcase.NewBarcodeUsecase(barcodeRepo, productOutletRepo, productRepo, timeoutContext)
+	_barcodeHttpDelivery.NewBarcodeHandler(e, barcode, cfg)
+
 	orderRepo := _orderRepo.NewMysqlOrderRepository(connection)
 	orderItemRepo := _orderItemRepo.NewMysqlOrderItemRepository(connection)
-	orderItem := _orderItemUcase.NewOrderItemUsecase(orderItemRepo, orderRepo, productRepo, timeoutContext)
+	paymentRepo := _paymentRepo.NewMysqlPaymentRepository(connection)
+	orderItem := _orderItemUcase.NewOrderItemUsecase(orderItemRepo, orderRepo, productRepo, paymentRepo, timeoutContext)
 	_orderItemHttpDelivery.NewOrderItemHandler(e, orderItem, cfg)
 
-	paymentRepo := _paymentRepo.NewMysqlPaymentRepository(connection)
-	payment := _paymentUcase.NewPaymentUsecase(orderRepo, paymentRepo,  timeoutContext)
+	shiftRepo := _shiftRepo.NewMysqlShiftRepository(connection)
+	shift := _shiftUcase.NewShiftUsecase(shiftRepo, transactor, timeoutContext)
+	_shiftHttpDelivery.NewShiftHandler(e, shift, cfg)
+
+	payment := _paymentUcase.NewPaymentUsecase(orderRepo, orderItemRepo, productOutletRepo, paymentRepo, shiftRepo, transactor, timeoutContext)
 	_paymentHttpDelivery.NewPaymentHandler(e, payment, cfg)
 
+	refundRepo := _refundRepo.NewMysqlRefundRepository(connection)
+	refund := _refundUcase.NewRefundUsecase(refundRepo, orderRepo, orderItemRepo, paymentRepo, productOutletRepo, shiftRepo, transactor, timeoutContext)
+	_refundHttpDelivery.NewRefundHandler(e, refund, cfg)
+
+	receiptRepo := _receiptRepo.NewMysqlReceiptRepository(connection)
+	receipt := _receiptUcase.NewReceiptUsecase(receiptRepo, orderRepo, orderItemRepo, paymentRepo, outletRepo, timeoutContext)
+	_receiptHttpDelivery.NewReceiptHandler(e, receipt, cfg)
+
 	media := _mediaUcase.NewCustomerUsecase(cfg,  timeoutContext)
 	_mediaHttpDelivery.NewCustomerHandler(e, media, cfg)
 
@@ -19,13 +19,16 @@ type ServerConfig struct {
 	ReadTimeout     int
 	GraceFulTimeout int
 	Registration    bool
+	AdminTwoFactor  bool // super admins cannot login without a TOTP code
 	Debug 			bool
 }
 
+// ImpersonationConfig let the holders of the user.impersonate permission act as another user,
+// impersonation is disabled while Password is empty
 type ImpersonationConfig struct {
-	Password string
-	Admin    bool
-	User     bool
+	Password string // asked on every impersonation on top of the token of the support staff
+	Admin    bool   // super admins can be impersonated
+	User     bool   // the other users can be impersonated
 }
 
 type DBConfig struct {
@@ -62,6 +65,7 @@ type Config struct {
 	ResetPassword     ResetPassword
 	NoSQL 			  NoSQLConfig
 	BNIConfig		  BNIConfig
+	Impersonation     ImpersonationConfig
 }
 
 type NoSQLConfig struct {
@@ -79,11 +83,23 @@ type RedisServer struct {
 	MaxIdle  int
 }
 
-// JWTConfig is JWT configuration object
+// JWTConfig is JWT configuration object, the tokens are signed with HS256 and Secret until Keys are configured
 type JWTConfig struct {
 	Issuer            string
+	Audience          string
 	Secret            string
 	TokenLifeTimeHour int
+	RefreshTokenLifeTimeHour int
+	SigningKey        string // id of the key of Keys the new tokens are signed with
+	Keys              []JWTKey
+}
+
+// JWTKey is a RS256 or EdDSA key in PEM files, a key without private key only verifies the tokens signed before a rotation
+type JWTKey struct {
+	ID             string
+	Algorithm      string
+	PrivateKeyFile string
+	PublicKeyFile  string
 }
 
 type Mailer struct {
@@ -21,6 +21,7 @@ const (
 	MailLogoTemplate          = "templates/layout/Logo.html"
 	MailFooterTemplate        = "templates/layout/Footer.html"
 	MailResetPasswordTemplate = "templates/page/ResetPassword.html"
+	MailSetPasswordTemplate   = "templates/page/SetPassword.html"
 	DESILogoURL               = "https://desi-test.s3.ap-southeast-1.amazonaws.com/logo/608f1a20a23878e1368c563e.png"
 )
 
@@ -15,6 +15,24 @@ var (
 	ErrPhoneIsRequired          = errors.New("HandPhone is required")
 	ErrPasswordAlreadyTaken     = errors.New("New Password and Old Password should not be same")
 	ErrEmailAndPasswordNotMatch = errors.New("Email or Password Not Match")
+	ErrPinNotMatch              = errors.New("Username or PIN Not Match")
+	ErrPinFormat                = errors.New("PIN should contains 4 to 6 digits.")
+	ErrTerminalInvalid          = errors.New("Terminal is not registered or has been revoked")
+	ErrApiKeyInvalid            = errors.New("API key is invalid, expired or has been revoked")
+	ErrApiKeyScope              = errors.New("error: API keys cannot be granted an unknown permission or the management of users, terminals and API keys")
+	ErrLoginLocked              = errors.New("Too many failed logins, please try again later")
+	ErrImpersonationDisabled    = errors.New("error: impersonation is disabled")
+	ErrImpersonationPassword    = errors.New("error: impersonation password doesn't match")
+	ErrImpersonationForbidden   = errors.New("error: you cannot impersonate this user")
+	ErrImpersonating            = errors.New("error: this action cannot be taken while impersonating a user")
+	ErrImpersonationAudit       = errors.New("error: the write cannot be audited, it was not made")
+	ErrTwoFactorCodeRequired    = errors.New("Authentication code is required")
+	ErrTwoFactorCodeNotMatch    = errors.New("Authentication code doesn't match")
+	ErrTwoFactorChallenge       = errors.New("Login challenge is invalid or has been expired, please login again")
+	ErrTwoFactorRequired        = errors.New("This account requires two-factor authentication")
+	ErrTwoFactorEnabled         = errors.New("Two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled      = errors.New("Two-factor authentication is not enabled")
+	ErrTwoFactorSetupMissing    = errors.New("Two-factor setup is missing or has been expired, please start it again")
 	ErrKeyIsNotInvalidType      = errors.New("key is of invalid type")
 	ErrTokenIsRequired          = errors.New("Token is required")
 	ErrPasswordNotMatch         = errors.New("Password doesn't match")
@@ -30,14 +48,52 @@ var (
 	ErrEmailNotFound            = errors.New("Email not found")
 	ErrOldPasswordNotMatch      = errors.New("Old Password not match")
 	ErrTokenAlreadyExpired      = errors.New("Token Already Expired")
+	ErrTokenRevoked             = errors.New("Token has been revoked, please login again")
+	ErrRefreshTokenInvalid      = errors.New("Refresh token is invalid or has been expired")
 	ErrOTPIsRequired            = errors.New("OTP is required")
 	ErrOTPNotMatch              = errors.New("OTP doesn't match")
+	ErrOTPTooManyAttempts       = errors.New("OTP has been tried too many times, please ask for a new one")
+	ErrOTPResendLimit           = errors.New("Too many OTP requests, please try again later")
+	ErrResetTokenInvalid        = errors.New("Reset token is invalid or has been expired")
 	ErrValidation               = errors.New("Error Validation Request")
 	ErrUserInactive             = errors.New("This account is at the moment suspended.")
+	ErrUserPending              = errors.New("This account is waiting for the approval of an administrator.")
+	ErrUserNotPending           = errors.New("error: user is not waiting for approval")
+	ErrRegistrationClosed       = errors.New("error: registration is closed")
+	ErrUserInUse                = errors.New("error: user has sales or shifts recorded, suspend it instead")
+	ErrUserSelf                 = errors.New("error: you cannot suspend or delete your own account")
+	ErrRoleNotFound             = errors.New("error: role not found")
 	ErrUnchangedPassword        = errors.New("Please change your password first")
 	ErrInvalidPassword          = errors.New("Password should contains upper case character, lower case characters, a digit and a special character.")
 	ErrUserNameFormat          = errors.New("Username Format not valid, just aphabet,number,.,_")
 	ErrDataNotFound            = errors.New("Data not found")
 	ErrNotAuthorized          = errors.New("user not authorized")
+	ErrForbidden              = errors.New("error: you do not have permission to access this resource")
+	ErrOutletForbidden        = errors.New("error: you are not assigned to this outlet")
+	ErrOutletTimezone         = errors.New("error: outlet timezone must be an IANA time zone such as Asia/Jakarta")
 	ErrParamsIsNotInvalid      = errors.New("parameter outlet is of invalid type")
+	ErrQuantityOutOfStock      = errors.New("error: Quantity out of stock")
+	ErrOrderNotOpen            = errors.New("error: order is not open or already checked out")
+	ErrOrderEmpty              = errors.New("error: cannot checkout order empty")
+	ErrProductNotInOutlet      = errors.New("error: product is not sold in this outlet")
+	ErrCategoryParent          = errors.New("error: parent category must be global or of the same outlet, and not the category itself or one of its subcategories")
+	ErrCategoryInUse           = errors.New("error: category still has subcategories or products")
+	ErrVariantOptions          = errors.New("error: a product has up to 3 options with distinct names and values, and up to 100 variants")
+	ErrVariantInUse            = errors.New("error: variant is still stocked in an outlet")
+	ErrVariantProduct          = errors.New("error: variant is not a variant of this product")
+	ErrBarcodeInvalid          = errors.New("error: barcode is not a valid EAN-13, UPC-A or Code128, check its last digit")
+	ErrPaymentExceedTotal      = errors.New("error: non cash payment cannot exceed the amount due")
+	ErrPaymentMethodInvalid    = errors.New("error: payment method must be one of cash, card, e-wallet or transfer")
+	ErrPaymentReferenceRequired = errors.New("error: reference number is required for non cash payment")
+	ErrOrderHasPayment         = errors.New("error: order already has payments and cannot be changed")
+	ErrOrderItemVoided         = errors.New("error: order line is already voided")
+	ErrVoidReasonInvalid       = errors.New("error: void reason must be one of customer_cancel, wrong_item, price_change, damaged or other")
+	ErrReceiptFormatInvalid    = errors.New("error: receipt format must be one of text, escpos or pdf")
+	ErrReceiptTemplateInvalid  = errors.New("error: receipt template cannot be rendered")
+	ErrShiftNotOpen            = errors.New("error: shift is not open")
+	ErrShiftAlreadyOpen        = errors.New("error: a shift is already open for this cashier in this outlet")
+	ErrOrderNotPaid            = errors.New("error: order is not paid yet")
+	ErrRefundEmpty             = errors.New("error: nothing left to return on this order")
+	ErrRefundQtyExceed         = errors.New("error: returned quantity exceeds the quantity sold")
+	ErrRefundExceedPayment     = errors.New("error: refund exceeds the amount left on the payment")
 	)
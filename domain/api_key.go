@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ApiKey status, a revoked key is refused by the middleware
+const (
+	ApiKeyActive  = "active"
+	ApiKeyRevoked = "revoked"
+)
+
+// ApiKeyPrefix starts every API key, it tells a key apart from a JWT in the Authorization header
+const ApiKeyPrefix = "mpk_"
+
+// ApiKey let a machine integration call the API with the permissions and outlets it was granted
+type ApiKey struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name" validate:"required,max=100"`
+	Prefix      string     `json:"prefix"` // the start of the key, to tell the keys apart
+	Permissions []string   `json:"permissions" validate:"required,min=1"`
+	Outlets     []int64    `json:"outlets"`
+	Status      string     `json:"status"`
+	Key         string     `json:"key,omitempty"` // only answered on creation, the integration keeps it
+	CreatedBy   int64      `json:"created_by"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	LastUsedIP  string     `json:"last_used_ip"`
+	UpdatedAt   time.Time  `json:"update_at"`
+	CreatedAt   time.Time  `json:"create_at"`
+}
+
+// ApiKeyUsecase represent the ApiKey's usecases
+type ApiKeyUsecase interface {
+	Fetch(ctx context.Context, cursor string, num int64) ([]ApiKey, string, error) // super admin
+	GetByID(ctx context.Context, id int64) (ApiKey, error)                         // super admin
+	Create(ctx context.Context, m *ApiKey) error                                    // super admin
+	Revoke(ctx context.Context, id int64) (ApiKey, error)                           // super admin
+	Authenticate(ctx context.Context, key string, ip string) (ApiKey, error)
+}
+
+// ApiKeyRepository represent the ApiKey's repository contract
+type ApiKeyRepository interface {
+	Fetch(ctx context.Context, cursor string, num int64) (res []ApiKey, nextCursor string, err error)
+	GetByID(ctx context.Context, id int64) (ApiKey, error)
+	GetByHash(ctx context.Context, keyHash string) (ApiKey, error)
+	Store(ctx context.Context, m *ApiKey, keyHash string) error
+	SetStatus(ctx context.Context, id int64, status string) error
+	Touch(ctx context.Context, id int64, ip string, at time.Time) error
+}
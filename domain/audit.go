@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Audit actions
+const (
+	AuditImpersonate       = "impersonate"        // an impersonation token was issued
+	AuditImpersonateDenied = "impersonate.denied" // an impersonation was refused, Detail holds why
+	AuditWrite             = "write"              // a write made with an impersonation token, logged before it is made
+)
+
+// AuditLog record what a support staff did while acting as another user, ActorID is the real user
+// and UserID the impersonated one
+type AuditLog struct {
+	ID        int64     `json:"id"`
+	ActorID   int       `json:"actor_id"`
+	UserID    int       `json:"user_id"`
+	Action    string    `json:"action"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Status    int       `json:"status"`
+	IP        string    `json:"ip"`
+	Detail    string    `json:"detail"`
+	CreatedAt time.Time `json:"create_at"`
+}
+
+// AuditUsecase represent the AuditLog's usecases
+type AuditUsecase interface {
+	Fetch(ctx context.Context, cursor string, num int64, actorId int, userId int) ([]AuditLog, string, error) // super admin
+	Record(ctx context.Context, m *AuditLog) error
+	SetStatus(ctx context.Context, id int64, status int) error
+}
+
+// AuditRepository represent the AuditLog's repository contract, actorId and userId 0 fetch every log
+type AuditRepository interface {
+	Fetch(ctx context.Context, cursor string, num int64, actorId int, userId int) (res []AuditLog, nextCursor string, err error)
+	Store(ctx context.Context, m *AuditLog) error
+	SetStatus(ctx context.Context, id int64, status int) error
+}
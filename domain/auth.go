@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
 // Auth ...
@@ -11,9 +12,58 @@ type UserLogin struct {
 	Password          string     `json:"password"`
 }
 
+// LoginAttempt count the failed logins of an account or of an IP, and the lockout they led to
+type LoginAttempt struct {
+	Failures          int     `json:"failures"`
+	LockedUntil          time.Time     `json:"locked_until"`
+}
+
+// RequestUnlock clear the failed logins of an account, of an IP or of both
+type RequestUnlock struct {
+	Email          string     `json:"email" validate:"omitempty,email"`
+	IP          string     `json:"ip" validate:"omitempty,ip"`
+}
+
 type UserResponse struct {
 	ID             int     `json:"id"`
 	Token          string     `json:"token"`
+	RefreshToken          string     `json:"refresh_token,omitempty"`
+	ExpiresAt          int64     `json:"expires_at"`
+	RecoveryCodes          []string     `json:"recovery_codes,omitempty"` // only answered on the login enrolling to 2FA
+}
+
+// RequestRefreshToken carry the refresh token to rotate on refresh, or to drop on logout
+type RequestRefreshToken struct {
+	RefreshToken          string     `json:"refresh_token" validate:"required"`
+}
+
+// RequestForgotPassword ask for an OTP to be mailed to the email of the account
+type RequestForgotPassword struct {
+	Email          string     `json:"email" validate:"required,email"`
+}
+
+// RequestVerifyOTP trade the mailed OTP for a reset token
+type RequestVerifyOTP struct {
+	Email          string     `json:"email" validate:"required,email"`
+	OTP          string     `json:"otp" validate:"required"`
+}
+
+// RequestResetPassword set the new password of the account the reset token was issued for
+type RequestResetPassword struct {
+	ResetToken          string     `json:"reset_token" validate:"required"`
+	Password          string     `json:"password" validate:"required"`
+	RePassword          string     `json:"rePassword" validate:"required"`
+}
+
+type ResetTokenResponse struct {
+	ResetToken          string     `json:"reset_token"`
+	ExpiresAt          int64     `json:"expires_at"`
+}
+
+// OTP is a one time password kept hashed with the count of wrong attempts made against it
+type OTP struct {
+	Hash          string     `json:"hash"`
+	ExpiresAt          time.Time     `json:"expires_at"`
 }
 
 type User struct {
@@ -22,7 +72,42 @@ type User struct {
 	UserName          string     `json:"username"`
 	Email             string     `json:"email"`
 	Password          string     `json:"password"`
+	Pin          string     `json:"-"`
+	TOTPSecret          string     `json:"-"`
+	RoleID          int     `json:"role_id"`
+	Status          string     `json:"status"`
+	Permissions          []string     `json:"permissions"`
+	Outlets          []int64     `json:"outlets"`
+}
+
+// Profile is the account of the token holder as shown to the user itself
+type Profile struct {
+	ID                int        `json:"id"`
+	Name              string     `json:"name"`
+	UserName          string     `json:"username"`
+	Email             string     `json:"email"`
 	RoleID          int     `json:"role_id"`
+	Status          string     `json:"status"`
+	TwoFactor          bool     `json:"two_factor"`
+	Permissions          []string     `json:"permissions"`
+	Outlets          []int64     `json:"outlets"`
+}
+
+type RequestUpdateProfile struct {
+	Name              string     `json:"name" validate:"required,max=150"`
+}
+
+type RequestChangePassword struct {
+	OldPassword          string     `json:"oldPassword"`
+	Password          string     `json:"password"`
+	RePassword          string     `json:"rePassword"`
+}
+
+// RequestImpersonate ask for a token acting as another user, the reason is kept in the audit log
+type RequestImpersonate struct {
+	UserID          int     `json:"user_id" validate:"required"`
+	Password          string     `json:"password" validate:"required"`
+	Reason          string     `json:"reason" validate:"required,max=255"`
 }
 
 type RegisterUser struct {
@@ -35,10 +120,80 @@ type RegisterUser struct {
 
 type AuthUseCase interface {
 	GetEmailUser(ctx context.Context, username string) (User, error)
+	Login(ctx context.Context, m UserLogin, ip string) (User, error)
+	Unlock(ctx context.Context, email string, ip string) error // super admin
+	PinLogin(ctx context.Context, m RequestPinLogin) (User, Terminal, error)
+	SetPin(ctx context.Context, userId int, m *RequestSetPin) error // all access
 	Register(ctx context.Context,register  RegisterUser) (res User,err error)
+	GetPermissions(ctx context.Context, roleId int) ([]string, error)
+	GetOutlets(ctx context.Context, userId int) ([]int64, error)
+	CreateRefreshToken(ctx context.Context, userId int, ttl time.Duration) (string, error)
+	Refresh(ctx context.Context, refreshToken string) (User, error)
+	Logout(ctx context.Context, userId int, tokenId string, expiresAt int64, refreshToken string) error
+	RevokeUser(ctx context.Context, userId int) error // super admin
+	IsRevoked(ctx context.Context, userId int, tokenId string, issuedAt int64) (bool, error)
+	IsTerminalRevoked(ctx context.Context, terminalId int64) (bool, error)
+	ForgotPassword(ctx context.Context, email string, ip string) error
+	VerifyOTP(ctx context.Context, email string, otp string) (ResetTokenResponse, error)
+	ResetPassword(ctx context.Context, resetToken string, password string) error
+	GetProfile(ctx context.Context, userId int) (Profile, error) // all access
+	UpdateProfile(ctx context.Context, userId int, m *RequestUpdateProfile) (Profile, error) // all access
+	ChangePassword(ctx context.Context, userId int, m *RequestChangePassword) (User, error) // all access
+	StartTwoFactor(ctx context.Context, user User) (TwoFactorChallenge, error)
+	VerifyTwoFactor(ctx context.Context, m RequestTwoFactorLogin) (User, []string, error)
+	SetupTwoFactorChallenge(ctx context.Context, challengeToken string) (TwoFactorSetup, error)
+	SetupTwoFactor(ctx context.Context, userId int) (TwoFactorSetup, error) // all access
+	ConfirmTwoFactor(ctx context.Context, userId int, code string) ([]string, error) // all access
+	DisableTwoFactor(ctx context.Context, userId int, m *RequestDisableTwoFactor) error // all access
+	RegenerateRecoveryCodes(ctx context.Context, userId int, code string) ([]string, error) // all access
+	Impersonate(ctx context.Context, actorId int, m RequestImpersonate, ip string) (User, error) // super admin
 }
 // AuthRepository represent the auth repository contract
 type AuthRepository interface {
 	GetEmailUser(ctx context.Context, username string) (User, error)
 	Register(ctx context.Context,register RegisterUser) (id int, err error)
+	FetchPermissions(ctx context.Context, roleId int) ([]string, error)
+	FetchOutlets(ctx context.Context, userId int) ([]int64, error)
+	GetUserByID(ctx context.Context, id int) (User, error)
+	GetUserByUserName(ctx context.Context, username string) (User, error)
+	SetPin(ctx context.Context, id int, pin string) error
+	UpdatePassword(ctx context.Context, id int, password string) error
+	UpdateProfile(ctx context.Context, id int, m *RequestUpdateProfile) error
+	SetTOTPSecret(ctx context.Context, id int, secret string) error // an empty secret turns 2FA off
+	ReplaceRecoveryCodes(ctx context.Context, userId int, hashes []string) error
+	UseRecoveryCode(ctx context.Context, userId int, hash string) (bool, error) // false when the code is unknown or used
 }
+
+// SessionRepository keep the refresh tokens and the revocation list of the access tokens
+type SessionRepository interface {
+	StoreRefreshToken(ctx context.Context, token string, userId int, ttl time.Duration) error
+	GetRefreshToken(ctx context.Context, token string) (userId int, issuedAt int64, err error) // issuedAt in milliseconds
+	ConsumeRefreshToken(ctx context.Context, token string) (userId int, issuedAt int64, err error) // get and delete at once, a token is consumed only once
+	DeleteRefreshToken(ctx context.Context, token string) error
+	RevokeToken(ctx context.Context, tokenId string, ttl time.Duration) error
+	IsTokenRevoked(ctx context.Context, tokenId string) (bool, error)
+	RevokeUser(ctx context.Context, userId int, at time.Time) error
+	GetUserRevokedAt(ctx context.Context, userId int) (int64, error) // in milliseconds, 0 when the sessions of the user were never revoked
+	RevokeTerminal(ctx context.Context, terminalId int64) error
+	IsTerminalRevoked(ctx context.Context, terminalId int64) (bool, error)
+}
+
+// LoginAttemptRepository keep the failed login counters, keyed by account or by IP
+type LoginAttemptRepository interface {
+	GetAttempt(ctx context.Context, key string) (LoginAttempt, error) // the zero LoginAttempt when nothing failed
+	AddFailure(ctx context.Context, key string, ttl time.Duration) (failures int, err error) // atomic, concurrent failures are all counted
+	Lock(ctx context.Context, key string, until time.Time) error
+	DeleteAttempt(ctx context.Context, key string) error
+}
+
+// OTPRepository keep the OTPs mailed for a purpose and the reset tokens they are traded for
+type OTPRepository interface {
+	StoreOTP(ctx context.Context, purpose string, email string, otp OTP) error
+	GetOTP(ctx context.Context, purpose string, email string) (OTP, error) // ErrNotFound when missing or expired
+	DeleteOTP(ctx context.Context, purpose string, email string) error
+	AddOTPAttempt(ctx context.Context, purpose string, email string, ttl time.Duration) (attempts int, err error) // atomic, parallel guesses are all counted
+	StoreResetToken(ctx context.Context, token string, userId int, ttl time.Duration) error
+	GetResetToken(ctx context.Context, token string) (userId int, err error)
+	DeleteResetToken(ctx context.Context, token string) error
+}
+
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Barcode symbologies
+const (
+	BarcodeEAN13   = "ean13"
+	BarcodeUPCA    = "upca"
+	BarcodeCode128 = "code128"
+)
+
+// InternalBarcodePrefix is the GS1 prefix left to the stores for their own numbering, no manufacturer uses it
+const InternalBarcodePrefix = "20"
+
+// Barcode is a code printed on the goods of an outlet product, an outlet product may carry several
+type Barcode struct {
+	ID              int64     `json:"id"`
+	OutletProductID int64     `json:"outlet_product_id"`
+	OutletID        int64     `json:"outlet_id"`
+	Code            string    `json:"code" validate:"required,max=48"`
+	Type            string    `json:"type" validate:"omitempty,oneof=ean13 upca code128"` // guessed from the code when empty
+	UpdatedAt       time.Time `json:"update_at"`
+	CreatedAt       time.Time `json:"create_at"`
+}
+
+// BarcodeScan is the outlet product a scanned code resolves to, with its price and stock
+type BarcodeScan struct {
+	Barcode Barcode        `json:"barcode"` // empty when the code was the SKU
+	Item    ProductOutlets `json:"item"`
+}
+
+// DetectType guess the symbology of a code given without one, 13 digits are an EAN-13 and 12 digits an UPC-A
+func (b *Barcode) DetectType() {
+	if b.Type != "" {
+		return
+	}
+	switch {
+	case isDigits(b.Code) && len(b.Code) == 13:
+		b.Type = BarcodeEAN13
+	case isDigits(b.Code) && len(b.Code) == 12:
+		b.Type = BarcodeUPCA
+	default:
+		b.Type = BarcodeCode128
+	}
+}
+
+// Valid return true if the code can be printed in its symbology, with the right check digit for EAN-13 and UPC-A.
+// The check symbol of Code128 is added by the printer and left out by the scanner, only its characters are checked
+func (b Barcode) Valid() bool {
+	switch b.Type {
+	case BarcodeEAN13, BarcodeUPCA:
+		size := 13
+		if b.Type == BarcodeUPCA {
+			size = 12
+		}
+		if len(b.Code) != size || !isDigits(b.Code) {
+			return false
+		}
+		return CheckDigit(b.Code[:size-1]) == int(b.Code[size-1]-'0')
+	case BarcodeCode128:
+		if len(b.Code) == 0 || len(b.Code) > 48 {
+			return false
+		}
+		for i := 0; i < len(b.Code); i++ {
+			if b.Code[i] < 32 || b.Code[i] > 126 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// Aliases return the codes a scanner may read for the same goods, an UPC-A is also read as an EAN-13 led by 0
+func (b Barcode) Aliases() []string {
+	switch {
+	case isDigits(b.Code) && len(b.Code) == 12:
+		return []string{b.Code, "0" + b.Code}
+	case isDigits(b.Code) && len(b.Code) == 13 && b.Code[0] == '0':
+		return []string{b.Code, b.Code[1:]}
+	}
+	return []string{b.Code}
+}
+
+// CheckDigit return the GS1 check digit of the digits of an EAN-13 or UPC-A without its last digit,
+// the rightmost digit weighs 3 and the weights alternate with 1 going left
+func CheckDigit(digits string) int {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
+
+// InternalEAN13 return the in-store EAN-13 of an outlet product, numbered by its id after InternalBarcodePrefix
+func InternalEAN13(outletProductId int64) string {
+	code := fmt.Sprintf("%s%010d", InternalBarcodePrefix, outletProductId)
+	return code + strconv.Itoa(CheckDigit(code))
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// BarcodeUsecase represent the Barcode's usecases, outletId 0 reaches every outlet
+type BarcodeUsecase interface {
+	Fetch(ctx context.Context, outletProductId int64, outletId int64) ([]Barcode, error)  // super admin
+	Store(ctx context.Context, m *Barcode, outletId int64) error                          // super admin
+	Generate(ctx context.Context, outletProductId int64, outletId int64) (Barcode, error) // super admin
+	Delete(ctx context.Context, id int64, outletId int64) error                           // super admin
+	Scan(ctx context.Context, code string, outletId int64) (BarcodeScan, error)           // all access
+}
+
+// BarcodeRepository represent the Barcode's repository contract
+type BarcodeRepository interface {
+	FetchByOutletProduct(ctx context.Context, outletProductId int64) ([]Barcode, error)
+	GetByID(ctx context.Context, id int64) (Barcode, error)
+	GetByCode(ctx context.Context, outletId int64, codes []string) (Barcode, error)
+	Store(ctx context.Context, m *Barcode) error
+	Delete(ctx context.Context, id int64) error
+}
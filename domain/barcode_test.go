@@ -0,0 +1,44 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"mini_pos/domain"
+)
+
+func TestBarcodeValid(t *testing.T) {
+	for _, c := range []struct {
+		code  string
+		typ   string
+		valid bool
+	}{
+		{"4006381333931", domain.BarcodeEAN13, true},
+		{"4006381333932", domain.BarcodeEAN13, false},
+		{"036000291452", domain.BarcodeUPCA, true},
+		{"036000291453", domain.BarcodeUPCA, false},
+		{"SKU-0042/A", domain.BarcodeCode128, true},
+		{"café", domain.BarcodeCode128, false},
+	} {
+		b := domain.Barcode{Code: c.code}
+		b.DetectType()
+		assert.Equal(t, c.typ, b.Type, c.code)
+		assert.Equal(t, c.valid, b.Valid(), c.code)
+	}
+
+	// an EAN-13 code given as Code128 is printed as Code128 without a check digit
+	assert.True(t, domain.Barcode{Code: "4006381333932", Type: domain.BarcodeCode128}.Valid())
+}
+
+func TestBarcodeAliases(t *testing.T) {
+	assert.Equal(t, []string{"036000291452", "0036000291452"}, domain.Barcode{Code: "036000291452"}.Aliases())
+	assert.Equal(t, []string{"0036000291452", "036000291452"}, domain.Barcode{Code: "0036000291452"}.Aliases())
+	assert.Equal(t, []string{"4006381333931"}, domain.Barcode{Code: "4006381333931"}.Aliases())
+}
+
+func TestInternalEAN13(t *testing.T) {
+	code := domain.InternalEAN13(42)
+	assert.Equal(t, "2000000000428", code)
+	assert.True(t, domain.Barcode{Code: code, Type: domain.BarcodeEAN13}.Valid())
+}
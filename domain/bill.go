@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"math"
+)
+
+// BillLine is an order line priced with the outlet price
+type BillLine struct {
+	OrderItemID   int64    `json:"order_item_id"`
+	ProductID   int64    `json:"product_id"`
+	Name   string    `json:"name"`
+	Qty   float64    `json:"qty"`
+	Price   float64    `json:"price"`
+	Discount   float64    `json:"discount"` // nominal discount for the whole line
+	TaxRate   float64    `json:"tax_rate"` // tax_apply percentage of the line
+	Tax   float64    `json:"tax"`
+	Total   float64    `json:"total"`
+}
+
+// OrderBill is the server side computed amount of an order
+type OrderBill struct {
+	OrderID   int64    `json:"order_id"`
+	Lines   []BillLine    `json:"lines"`
+	Subtotal   float64    `json:"subtotal"`
+	Discount   float64    `json:"discount"`
+	TaxRate   float64    `json:"tax_rate"` // order level tax percentage
+	Tax   float64    `json:"tax"`
+	Total   float64    `json:"total"`
+}
+
+// Calculate fill the line and order amounts, a line discount never makes a line negative
+func (b *OrderBill) Calculate() {
+	b.Subtotal, b.Discount, b.Tax = 0, 0, 0
+	net := float64(0)
+	for i := range b.Lines {
+		line := &b.Lines[i]
+		gross := RoundMoney(line.Qty * line.Price)
+		discount := math.Min(math.Max(line.Discount, 0), gross)
+		line.Tax = RoundMoney((gross - discount) * line.TaxRate / 100)
+		line.Total = RoundMoney(gross - discount + line.Tax)
+
+		b.Subtotal += gross
+		b.Discount += discount
+		b.Tax += line.Tax
+		net += gross - discount
+	}
+	b.Tax += RoundMoney(net * b.TaxRate / 100)
+
+	b.Subtotal = RoundMoney(b.Subtotal)
+	b.Discount = RoundMoney(b.Discount)
+	b.Tax = RoundMoney(b.Tax)
+	b.Total = RoundMoney(b.Subtotal - b.Discount + b.Tax)
+}
+
+// Refundable return the amount paid for qty units of the line, order level tax included. returned units of the
+// line were already given back for refunded: a return taking the last units gives back what is left of the line,
+// so the rounding of the earlier returns never adds up to more than was paid
+func (b OrderBill) Refundable(line BillLine, qty float64, returned float64, refunded float64) float64 {
+	if line.Qty <= 0 {
+		return 0
+	}
+	net := line.Total - line.Tax
+	paid := line.Total + net*b.TaxRate/100
+	if returned+qty >= line.Qty {
+		return math.Max(RoundMoney(RoundMoney(paid)-refunded), 0)
+	}
+	return RoundMoney(paid * qty / line.Qty)
+}
+
+// RoundMoney round an amount to 2 decimals
+func RoundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
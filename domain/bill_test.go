@@ -0,0 +1,61 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"mini_pos/domain"
+)
+
+func TestOrderBillCalculate(t *testing.T) {
+	bill := domain.OrderBill{
+		TaxRate: 10,
+		Lines: []domain.BillLine{
+			{Qty: 2, Price: 50000, Discount: 5000},
+			{Qty: 1, Price: 65000, TaxRate: 5},
+		},
+	}
+	bill.Calculate()
+
+	assert.Equal(t, float64(165000), bill.Subtotal)
+	assert.Equal(t, float64(5000), bill.Discount)
+	assert.Equal(t, float64(95000), bill.Lines[0].Total)
+	assert.Equal(t, float64(3250), bill.Lines[1].Tax)
+	// line tax 3250 + order tax 10% of 160000
+	assert.Equal(t, float64(19250), bill.Tax)
+	assert.Equal(t, float64(179250), bill.Total)
+}
+
+func TestOrderBillDiscountNeverNegative(t *testing.T) {
+	bill := domain.OrderBill{
+		Lines: []domain.BillLine{
+			{Qty: 1, Price: 1000, Discount: 5000},
+		},
+	}
+	bill.Calculate()
+
+	assert.Equal(t, float64(1000), bill.Discount)
+	assert.Equal(t, float64(0), bill.Total)
+}
+
+func TestOrderBillRefundable(t *testing.T) {
+	bill := domain.OrderBill{
+		TaxRate: 10,
+		Lines: []domain.BillLine{
+			{Qty: 2, Price: 50000, Discount: 5000},
+			{Qty: 1, Price: 65000, TaxRate: 5},
+		},
+	}
+	bill.Calculate()
+
+	assert.Equal(t, float64(52250), bill.Refundable(bill.Lines[0], 1, 0, 0))
+	// returning every line gives back the whole order total
+	full := bill.Refundable(bill.Lines[0], 2, 0, 0) + bill.Refundable(bill.Lines[1], 1, 0, 0)
+	assert.Equal(t, bill.Total, full)
+
+	// the last unit gives back what the earlier returns left of the line
+	line := domain.BillLine{Qty: 3, Total: 200}
+	assert.Equal(t, 66.67, domain.OrderBill{}.Refundable(line, 1, 0, 0))
+	assert.Equal(t, 66.66, domain.OrderBill{}.Refundable(line, 1, 2, 133.34))
+}
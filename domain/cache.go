@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Cache is the expiring key value store behind sessions, it is backed by redis and kept in memory for tests
+type Cache interface {
+	Set(ctx context.Context, key string, value string, ttl time.Duration) error // a ttl of 0 never expires
+	Get(ctx context.Context, key string) (string, error)                        // ErrNotFound when the key is missing or expired
+	Delete(ctx context.Context, key string) error
+	Take(ctx context.Context, key string) (string, error) // Get and Delete at once, only one of concurrent callers gets the value
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) // add one atomically, the key lives at least ttl from now
+}
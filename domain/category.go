@@ -5,16 +5,38 @@ import (
 	"time"
 )
 
-// Product ...
+// CategoryAnyParent fetch the categories whatever their parent, a parent 0 fetch the top level categories
+const CategoryAnyParent = -1
+
+// Category group the products, a category is either global and shared by every outlet or
+// specific to one outlet. A category may be nested under a parent of its outlet or a global one
 type Category struct {
-	ID        int64     `json:"id"`
-	Name     string    `json:"name" validate:"required"`
-	Description   string    `json:"description" `
-	UpdatedAt time.Time `json:"update_at"`
-	CreatedAt time.Time `json:"create_at"`
+	ID          int64     `json:"id"`
+	Name        string    `json:"name" validate:"required,max=150"`
+	Description string    `json:"description" validate:"max=255"`
+	OutletID    int64     `json:"outlet_id"` // 0 for a global category
+	ParentID    int64     `json:"parent_id"` // 0 for a top level category
+	UpdatedAt   time.Time `json:"update_at"`
+	CreatedAt   time.Time `json:"create_at"`
+}
+
+// CategoryUsecase represent the Category's usecases
+type CategoryUsecase interface {
+	Fetch(ctx context.Context, cursor string, num int64, outletId int64, parentId int64) ([]Category, string, error) // all access
+	GetByID(ctx context.Context, id int64) (Category, error)                                                        // all access
+	FetchProducts(ctx context.Context, id int64, outletId int64, cursor string, num int64) ([]Product, string, error) // all access
+	Store(ctx context.Context, m *Category) error                                                                   // super admin
+	Update(ctx context.Context, m *Category) error                                                                  // super admin
+	Delete(ctx context.Context, id int64) error                                                                     // super admin
 }
 
-// CategoryRepository represent the Product's repository contract
+// CategoryRepository represent the Category's repository contract
 type CategoryRepository interface {
+	Fetch(ctx context.Context, cursor string, num int64, outletId int64, parentId int64) (res []Category, nextCursor string, err error) // outlet 0 fetch every category
 	GetByID(ctx context.Context, id int64) (Category, error)
+	FetchChildren(ctx context.Context, id int64) ([]Category, error)
+	CountProducts(ctx context.Context, id int64) (int64, error)
+	Store(ctx context.Context, m *Category) error
+	Update(ctx context.Context, m *Category) error
+	Delete(ctx context.Context, id int64) error
 }
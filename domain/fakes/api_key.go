@@ -0,0 +1,33 @@
+package fakes
+
+import (
+	"context"
+	"time"
+
+	"mini_pos/domain"
+)
+
+// ApiKeyRepository keep the keys in memory by the hash of the key
+type ApiKeyRepository struct {
+	domain.ApiKeyRepository
+	Keys map[string]domain.ApiKey
+}
+
+func (m *ApiKeyRepository) GetByHash(ctx context.Context, keyHash string) (domain.ApiKey, error) {
+	res, ok := m.Keys[keyHash]
+	if !ok {
+		return domain.ApiKey{}, domain.ErrNotFound
+	}
+	return res, nil
+}
+
+func (m *ApiKeyRepository) Touch(ctx context.Context, id int64, ip string, at time.Time) error {
+	for hash, key := range m.Keys {
+		if key.ID == id {
+			key.LastUsedAt = &at
+			key.LastUsedIP = ip
+			m.Keys[hash] = key
+		}
+	}
+	return nil
+}
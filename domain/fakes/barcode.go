@@ -0,0 +1,31 @@
+package fakes
+
+import (
+	"context"
+
+	"mini_pos/domain"
+)
+
+// BarcodeRepository keep the barcodes in memory
+type BarcodeRepository struct {
+	domain.BarcodeRepository
+	Barcodes []domain.Barcode
+}
+
+// GetByCode return the first barcode of the outlet matching one of the codes, in the order of the codes
+func (m *BarcodeRepository) GetByCode(ctx context.Context, outletId int64, codes []string) (domain.Barcode, error) {
+	for _, code := range codes {
+		for _, b := range m.Barcodes {
+			if b.OutletID == outletId && b.Code == code {
+				return b, nil
+			}
+		}
+	}
+	return domain.Barcode{}, domain.ErrNotFound
+}
+
+func (m *BarcodeRepository) Store(ctx context.Context, a *domain.Barcode) error {
+	a.ID = int64(len(m.Barcodes) + 1)
+	m.Barcodes = append(m.Barcodes, *a)
+	return nil
+}
@@ -0,0 +1,50 @@
+package fakes
+
+import (
+	"context"
+
+	"mini_pos/domain"
+)
+
+// CategoryRepository keep the categories in memory by id, with the count of products of each category
+type CategoryRepository struct {
+	domain.CategoryRepository
+	Categories map[int64]domain.Category
+	Products   map[int64]int64
+}
+
+func (m *CategoryRepository) GetByID(ctx context.Context, id int64) (domain.Category, error) {
+	res, ok := m.Categories[id]
+	if !ok {
+		return domain.Category{}, domain.ErrNotFound
+	}
+	return res, nil
+}
+
+// FetchChildren return the direct subcategories by id, the ids are given in sequence by Store
+func (m *CategoryRepository) FetchChildren(ctx context.Context, id int64) (res []domain.Category, err error) {
+	for i := int64(1); i <= int64(len(m.Categories)); i++ {
+		if m.Categories[i].ParentID == id {
+			res = append(res, m.Categories[i])
+		}
+	}
+	return
+}
+
+func (m *CategoryRepository) CountProducts(ctx context.Context, id int64) (int64, error) {
+	return m.Products[id], nil
+}
+
+func (m *CategoryRepository) Store(ctx context.Context, a *domain.Category) error {
+	if m.Categories == nil {
+		m.Categories = make(map[int64]domain.Category)
+	}
+	a.ID = int64(len(m.Categories) + 1)
+	m.Categories[a.ID] = *a
+	return nil
+}
+
+func (m *CategoryRepository) Update(ctx context.Context, a *domain.Category) error {
+	m.Categories[a.ID] = *a
+	return nil
+}
@@ -0,0 +1,3 @@
+// Package fakes hold in memory implementations of the domain repositories for the usecase tests.
+// Each fake embeds its interface, a method a test does not need is left unimplemented and panics
+package fakes
@@ -0,0 +1,27 @@
+package fakes
+
+import (
+	"context"
+
+	"mini_pos/domain"
+)
+
+// OrderRepository keep the orders in memory by id
+type OrderRepository struct {
+	domain.OrderRepository
+	Orders map[int64]domain.Order
+}
+
+func (m *OrderRepository) GetAnyByID(ctx context.Context, id int64) (domain.Order, error) {
+	res, ok := m.Orders[id]
+	if !ok {
+		return domain.Order{}, domain.ErrNotFound
+	}
+	return res, nil
+}
+
+// Lock has nothing to lock in memory, it only checks the order exists
+func (m *OrderRepository) Lock(ctx context.Context, id int64) error {
+	_, err := m.GetAnyByID(ctx, id)
+	return err
+}
@@ -0,0 +1,17 @@
+package fakes
+
+import (
+	"context"
+
+	"mini_pos/domain"
+)
+
+// OrderItemRepository keep the priced lines of the orders in memory, by order id
+type OrderItemRepository struct {
+	domain.OrderItemRepository
+	Lines map[int64][]domain.BillLine
+}
+
+func (m *OrderItemRepository) FetchBillLines(ctx context.Context, orderId int64, outletId int64) ([]domain.BillLine, error) {
+	return m.Lines[orderId], nil
+}
@@ -0,0 +1,36 @@
+package fakes
+
+import (
+	"context"
+
+	"mini_pos/domain"
+)
+
+// OutletRepository keep the outlets in memory by id
+type OutletRepository struct {
+	domain.OutletRepository
+	Outlets map[int64]domain.Outlet
+	Users   map[int64][]int // outlet id to the ids of its users
+}
+
+func (m *OutletRepository) GetByID(ctx context.Context, id int64) (domain.Outlet, error) {
+	res, ok := m.Outlets[id]
+	if !ok {
+		return domain.Outlet{}, domain.ErrNotFound
+	}
+	return res, nil
+}
+
+func (m *OutletRepository) SetStatus(ctx context.Context, id int64, status string) error {
+	res, ok := m.Outlets[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	res.Status = status
+	m.Outlets[id] = res
+	return nil
+}
+
+func (m *OutletRepository) FetchUserIDs(ctx context.Context, id int64) ([]int, error) {
+	return m.Users[id], nil
+}
@@ -0,0 +1,22 @@
+package fakes
+
+import (
+	"context"
+
+	"mini_pos/domain"
+)
+
+// PaymentRepository keep the tenders of the orders in memory, in the order they were taken
+type PaymentRepository struct {
+	domain.PaymentRepository
+	Payments []domain.Payment
+}
+
+func (m *PaymentRepository) FetchByOrderID(ctx context.Context, orderId int64) (res []domain.Payment, err error) {
+	for _, p := range m.Payments {
+		if p.Order == orderId {
+			res = append(res, p)
+		}
+	}
+	return
+}
@@ -0,0 +1,34 @@
+package fakes
+
+import (
+	"context"
+
+	"mini_pos/domain"
+)
+
+// ProductRepository keep the products in memory, the cursor and page size are ignored
+type ProductRepository struct {
+	domain.ProductRepository
+	Products []domain.Product
+}
+
+func (m *ProductRepository) GetByID(ctx context.Context, id int64) (domain.Product, error) {
+	for _, p := range m.Products {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return domain.Product{}, domain.ErrNotFound
+}
+
+func (m *ProductRepository) FetchByCategory(ctx context.Context, cursor string, num int64, categoryIds []int64) (res []domain.Product, nextCursor string, err error) {
+	for _, p := range m.Products {
+		for _, id := range categoryIds {
+			if p.Category.ID == id {
+				res = append(res, p)
+				break
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,41 @@
+package fakes
+
+import (
+	"context"
+
+	"mini_pos/domain"
+)
+
+// ProductOutletsRepository keep the outlet products in memory, the stock released is summed by product
+type ProductOutletsRepository struct {
+	domain.ProductOutletsRepository
+	Items    []domain.ProductOutlets
+	Released map[int64]float64
+}
+
+// GetByID return the outlet product, in any outlet when outlet is 0
+func (m *ProductOutletsRepository) GetByID(ctx context.Context, id int64, outlet int64) (domain.ProductOutlets, error) {
+	for _, item := range m.Items {
+		if item.ID == id && (outlet == 0 || item.Outlet.ID == outlet) {
+			return item, nil
+		}
+	}
+	return domain.ProductOutlets{}, domain.ErrNotFound
+}
+
+func (m *ProductOutletsRepository) GetBySku(ctx context.Context, sku string) (domain.ProductOutlets, error) {
+	for _, item := range m.Items {
+		if item.Sku == sku {
+			return item, nil
+		}
+	}
+	return domain.ProductOutlets{}, domain.ErrNotFound
+}
+
+func (m *ProductOutletsRepository) ReleaseStock(ctx context.Context, productId int64, outletId int64, qty float64) error {
+	if m.Released == nil {
+		m.Released = make(map[int64]float64)
+	}
+	m.Released[productId] += qty
+	return nil
+}
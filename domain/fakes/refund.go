@@ -0,0 +1,52 @@
+package fakes
+
+import (
+	"context"
+
+	"mini_pos/domain"
+)
+
+// RefundRepository keep the refunds, their lines and their tenders in memory
+type RefundRepository struct {
+	domain.RefundRepository
+	Refunds  []domain.Refund
+	Items    []domain.RefundItem
+	Payments []domain.RefundPayment
+}
+
+func (m *RefundRepository) Returned(ctx context.Context, orderItemId int64) (qty float64, amount float64, err error) {
+	for _, item := range m.Items {
+		if item.OrderItemID == orderItemId {
+			qty += item.Qty
+			amount += item.Amount
+		}
+	}
+	return
+}
+
+func (m *RefundRepository) RefundedAmount(ctx context.Context, paymentId int64) (amount float64, err error) {
+	for _, p := range m.Payments {
+		if p.PaymentID == paymentId {
+			amount += p.Amount
+		}
+	}
+	return
+}
+
+func (m *RefundRepository) Store(ctx context.Context, a *domain.Refund) error {
+	a.ID = int64(len(m.Refunds) + 1)
+	m.Refunds = append(m.Refunds, *a)
+	return nil
+}
+
+func (m *RefundRepository) StoreItem(ctx context.Context, a *domain.RefundItem) error {
+	a.ID = int64(len(m.Items) + 1)
+	m.Items = append(m.Items, *a)
+	return nil
+}
+
+func (m *RefundRepository) StorePayment(ctx context.Context, a *domain.RefundPayment) error {
+	a.ID = int64(len(m.Payments) + 1)
+	m.Payments = append(m.Payments, *a)
+	return nil
+}
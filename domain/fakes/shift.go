@@ -0,0 +1,22 @@
+package fakes
+
+import (
+	"context"
+
+	"mini_pos/domain"
+)
+
+// ShiftRepository keep the shifts in memory
+type ShiftRepository struct {
+	domain.ShiftRepository
+	Shifts []domain.Shift
+}
+
+func (m *ShiftRepository) GetOpen(ctx context.Context, userId int64, outletId int64) (domain.Shift, error) {
+	for _, s := range m.Shifts {
+		if s.UserID == userId && s.OutletID == outletId && s.Status == domain.ShiftOpen {
+			return s, nil
+		}
+	}
+	return domain.Shift{}, domain.ErrNotFound
+}
@@ -0,0 +1,10 @@
+package fakes
+
+import "context"
+
+// Transactor run fn as is, the fakes have nothing to roll back
+type Transactor struct{}
+
+func (Transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
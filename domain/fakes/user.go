@@ -0,0 +1,21 @@
+package fakes
+
+import (
+	"context"
+
+	"mini_pos/domain"
+)
+
+// UserRepository keep the user accounts in memory by id
+type UserRepository struct {
+	domain.UserRepository
+	Users map[int]domain.UserAccount
+}
+
+func (m *UserRepository) GetByID(ctx context.Context, id int) (domain.UserAccount, error) {
+	res, ok := m.Users[id]
+	if !ok {
+		return domain.UserAccount{}, domain.ErrNotFound
+	}
+	return res, nil
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+// Mailer send an email rendered from one of the mail templates, it is backed by SMTP and faked in tests
+type Mailer interface {
+	Send(ctx context.Context, to string, subject string, template string, data interface{}) error
+}
+
+// ResetPasswordMail is the data of the MailResetPasswordTemplate template
+type ResetPasswordMail struct {
+	Name            string
+	OTP             string
+	Link            string
+	LogoURL         string
+	ExpiresInMinute int
+	MaxAttempt      int
+}
@@ -9,11 +9,31 @@ import (
 type Order struct {
 	ID        int64     `json:"id"`
 	Customer   Customer    `json:"customer" `
+	UserID   int64    `json:"user_id" `
+	ActorID   int64    `json:"-"` // support staff who opened the order with an impersonation token
+	OutletID   int64    `json:"outlet_id" `
+	Label   string    `json:"label" `
+	Tax   float64    `json:"tax" `
 	IsCheckout   int    `json:"isCheckout" `
+	Status   string    `json:"status" `
+	VoidReason   VoidReason    `json:"void_reason,omitempty" `
+	VoidBy   int64    `json:"void_by,omitempty" `
+	VoidActorID   int64    `json:"-"` // support staff who voided the order with an impersonation token
+	VoidAt   *time.Time    `json:"void_at,omitempty" `
 	CreatedAt time.Time `json:"create_at"`
 	UpdatedAt time.Time `json:"update_at"`
 }
 
+// RequestCart is used to open a new (parked) cart for the logged in cashier
+type RequestCart struct {
+	OutletID   int64    `json:"outlet_id" `
+	CustomerID   int64    `json:"customer_id" `
+	Label   string    `json:"label" validate:"max=50"`
+	Tax   float64    `json:"tax" validate:"min=0,max=100"`
+	UserID   int64    `json:"-"`
+	ActorID   int64    `json:"-"`
+}
+
 
 // PurchaseUsecase represent the Purchase's usecases
 //type PurchaseUsecase interface {
@@ -28,8 +48,14 @@ type Order struct {
 // PurchaseRepository represent the Purchase's repository contract
 type OrderRepository interface {
 	Fetch(ctx context.Context, cursor string, num int64) (res []Order, nextCursor string, err error) // all access
+	FetchOpen(ctx context.Context, userId int64, outletId int64) (res []Order, err error) // all access
 	GetByID(ctx context.Context, id int64) (Order, error) // all access
-	GetStatus(ctx context.Context) (id int64, err error)
+	GetAnyByID(ctx context.Context, id int64) (Order, error) // all access
+	Lock(ctx context.Context, id int64) error // all access
+	GetStatus(ctx context.Context, userId int64, outletId int64) (id int64, err error)
 	Store(ctx context.Context, a *Order)  (id int64, err error)  // all access
 	Update(ctx context.Context, a *Order) error // all access
+	Checkout(ctx context.Context, a *Order) error // all access
+	Void(ctx context.Context, a *Order) error // all access
+	FetchVoids(ctx context.Context, filter VoidFilter) (res []VoidEntry, err error) // super admin
 }
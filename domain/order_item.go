@@ -12,6 +12,11 @@ type OrderItem struct {
 	Product   Product    `json:"product" `
 	Qty   float64    `json:"qty" `
 	Discount   *int64    `json:"discount" `
+	TaxApply   *int64    `json:"tax_apply" `
+	Status   string    `json:"status" `
+	VoidReason   VoidReason    `json:"void_reason,omitempty" `
+	VoidBy   int64    `json:"void_by,omitempty" `
+	VoidAt   *time.Time    `json:"void_at,omitempty" `
 	CreatedAt time.Time `json:"create_at"`
 	UpdatedAt time.Time `json:"update_at"`
 }
@@ -19,31 +24,43 @@ type OrderItem struct {
 type RequestOrderItem struct {
 	ID        int64     `json:"id"`
 	OrderID   int64    `json:"order_id" `
+	OutletID   int64    `json:"outlet_id" `
+	UserID   int64    `json:"-"`
+	ActorID   int64    `json:"-"` // support staff behind an impersonation token, kept on the cart it opens
 	ProductID   int64    `json:"product_id" `
 	Qty   float64    `json:"qty" `
-	Discount   *int64    `json:"discount" `
+	Discount   *int64    `json:"discount" validate:"omitempty,min=0"`
+	TaxApply   *int64    `json:"tax_apply" validate:"omitempty,min=0,max=100"`
 	CreatedAt time.Time `json:"create_at"`
 	UpdatedAt time.Time `json:"update_at"`
 }
 
 // PurchaseUsecase represent the Purchase's usecases
 type OrderItemUsecase interface {
-	Fetch(ctx context.Context, cursor string, num int64) ([]OrderItem, string, error) // all access
-	FetchByOrderID(ctx context.Context, id int64) (res []OrderItem, err error)
-	GetByID(ctx context.Context, id int64) (OrderItem, error)// all access
+	Fetch(ctx context.Context, cursor string, num int64, userId int64, outletId int64) ([]OrderItem, string, error) // all access
+	FetchByOrderID(ctx context.Context, id int64, userId int64) (res []OrderItem, err error)
+	FetchCarts(ctx context.Context, userId int64, outletId int64) (res []Order, err error) // all access
+	OpenCart(ctx context.Context, cart *RequestCart) (Order, error) // all access
+	UpdateCart(ctx context.Context, id int64, cart *RequestCart) (Order, error) // all access
+	GetByID(ctx context.Context, id int64, userId int64) (OrderItem, error)// all access
 	Update(ctx context.Context, ar *RequestOrderItem ) error // all access
 	Store(ctx context.Context,prod  *RequestOrderItem ) error//  all access
-	Delete(c context.Context, id int64) (err error) // all access
+	Void(ctx context.Context, m *RequestVoid) (err error) // all access
+	VoidCart(ctx context.Context, m *RequestVoid) (err error) // all access
+	FetchVoids(ctx context.Context, filter VoidFilter) (VoidReport, error) // super admin
 
 }
 
 // PurchaseRepository represent the Purchase's repository contract
 type OrderItemRepository interface {
-	Fetch(ctx context.Context, cursor string, num int64) (res []OrderItem, nextCursor string, err error) // all access
+	Fetch(ctx context.Context, cursor string, num int64, userId int64, outletId int64) (res []OrderItem, nextCursor string, err error) // all access
 	FetchByOrderID(ctx context.Context, id int64) (res []OrderItem, err error)
 	GetByID(ctx context.Context, id int64) (OrderItem, error) // all access
 	GetByOrderIDAndProdID(ctx context.Context, orderId int64, prodId int64) (res OrderItem, err error)
+	FetchBillLines(ctx context.Context, orderId int64, outletId int64) (res []BillLine, err error)
+	SetPrice(ctx context.Context, id int64, price float64) (err error)
 	Store(ctx context.Context, a *RequestOrderItem) error // all access
 	Update(ctx context.Context, a *RequestOrderItem) error // all access
-	Delete(c context.Context, id int64) (err error) // all access
+	Void(ctx context.Context, id int64, m *RequestVoid) (err error) // all access
+	FetchVoids(ctx context.Context, filter VoidFilter) (res []VoidEntry, err error) // super admin
 }
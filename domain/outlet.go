@@ -5,18 +5,47 @@ import (
 	"time"
 )
 
-// Product ...
+// Outlet status, an inactive outlet is left out of the tokens and of the outlets of its users
+const (
+	OutletActive   = "active"
+	OutletInactive = "inactive"
+)
+
+// Outlet is a store of the business, its receipt header and footer are printed when its receipt template has none
 type Outlet struct {
-	ID        int64     `json:"id"`
-	Name     string    `json:"name" validate:"required"`
-	Address   string    `json:"address" `
-	UpdatedAt time.Time `json:"update_at"`
-	CreatedAt time.Time `json:"create_at"`
+	ID            int64     `json:"id"`
+	Name          string    `json:"name" validate:"required,max=255"`
+	Address       string    `json:"address" validate:"required,max=255"`
+	Phone         string    `json:"phone" validate:"max=30"`
+	TaxID         string    `json:"tax_id" validate:"max=50"`
+	Timezone      string    `json:"timezone" validate:"max=64"`                // IANA time zone, Asia/Jakarta when empty
+	Currency      string    `json:"currency" validate:"omitempty,len=3,alpha"` // ISO 4217 code, IDR when empty
+	ReceiptHeader string    `json:"receipt_header" validate:"max=255"`
+	ReceiptFooter string    `json:"receipt_footer" validate:"max=255"`
+	Status        string    `json:"status"`
+	UpdatedAt     time.Time `json:"update_at"`
+	CreatedAt     time.Time `json:"create_at"`
+}
+
+// OutletUsecase represent the Outlet's usecases
+type OutletUsecase interface {
+	Fetch(ctx context.Context, cursor string, num int64, status string) ([]Outlet, string, error) // super admin
+	GetByID(ctx context.Context, id int64) (Outlet, error)                                        // super admin
+	Store(ctx context.Context, m *Outlet) error                                                   // super admin
+	Update(ctx context.Context, m *Outlet) error                                                  // super admin
+	SetStatus(ctx context.Context, id int64, status string) (Outlet, error)                       // super admin
+	FetchMine(ctx context.Context, userId int64, all bool) ([]Outlet, error)                      // all access
 }
 
-// CategoryRepository represent the Product's repository contract
+// OutletRepository represent the Outlet's repository contract
 type OutletRepository interface {
+	Fetch(ctx context.Context, cursor string, num int64, status string) (res []Outlet, nextCursor string, err error)
 	GetByUserID(ctx context.Context, id int64, userId int64) (Outlet, error)
 	GetByID(ctx context.Context, id int64) (Outlet, error)
-    GetByAll(ctx context.Context, userId int64) ([]Outlet, error)
+	GetByAll(ctx context.Context, userId int64) ([]Outlet, error)
+	FetchActive(ctx context.Context) ([]Outlet, error)
+	FetchUserIDs(ctx context.Context, id int64) ([]int, error) // the users assigned to the outlet in user_outlets
+	Store(ctx context.Context, m *Outlet) error
+	Update(ctx context.Context, m *Outlet) error
+	SetStatus(ctx context.Context, id int64, status string) error
 }
@@ -5,13 +5,39 @@ import (
 	"time"
 )
 
-// Purchase ...
+// PaymentMethod is the tender type of a payment
+type PaymentMethod string
+
+// Payment methods
+const (
+	PaymentMethodCash     PaymentMethod = "cash"
+	PaymentMethodCard     PaymentMethod = "card"
+	PaymentMethodEWallet  PaymentMethod = "e-wallet"
+	PaymentMethodTransfer PaymentMethod = "transfer"
+)
+
+// Valid return true if p is one of the supported payment methods
+func (p PaymentMethod) Valid() bool {
+	switch p {
+	case PaymentMethodCash, PaymentMethodCard, PaymentMethodEWallet, PaymentMethodTransfer:
+		return true
+	}
+	return false
+}
+
+// Payment is one tender of an order, an order may be settled by several tenders
 type Payment struct {
 	ID        int64     `json:"id"`
 	Order   int64    `json:"order_id" `
-	Tax   int64    `json:"tax" `
-	TypePayment   string    `json:"type_payment" `
+	Tax   float64    `json:"tax" `
+	TypePayment   PaymentMethod    `json:"type_payment" `
+	Reference   string    `json:"reference" `
 	TotalPayment   float64    `json:"total_payment" `
+	Paid   float64    `json:"paid" `
+	Change   float64    `json:"change" `
+	Amount   float64    `json:"amount" ` // part of the order total settled by this tender
+	ShiftID   int64    `json:"shift_id" ` // open shift of the cashier when the tender was taken
+	ActorID   int64    `json:"-"` // support staff who took the tender with an impersonation token
 	CreatedAt time.Time `json:"create_at"`
 	UpdatedAt time.Time `json:"update_at"`
 }
@@ -20,13 +46,31 @@ type RequestPayment struct {
 	ID        int64     `json:"id"`
 	CustomerID   int64    `json:"customer_id" `
 	Order   int64    `json:"order_id" `
-	Tax   int64    `json:"tax"`
-	TypePayment   string    `json:"type_payment" `
-	TotalPayment   float64    `json:"total_payment" `
+	OutletID   int64    `json:"outlet_id" `
+	UserID   int64    `json:"-"`
+	ActorID   int64    `json:"-"`
+	Tax   float64    `json:"tax"` // computed by the server
+	TypePayment   PaymentMethod    `json:"type_payment" validate:"required"`
+	Reference   string    `json:"reference" validate:"max=50"`
+	TotalPayment   float64    `json:"total_payment" ` // computed by the server
+	Paid   float64    `json:"paid" validate:"required,gt=0"` // amount handed over by the customer
+	Change   float64    `json:"change" `
+	Amount   float64    `json:"amount" ` // computed by the server
+	ShiftID   int64    `json:"-"`
 	CreatedAt time.Time `json:"create_at"`
 	UpdatedAt time.Time `json:"update_at"`
 }
 
+// PaymentResult is the computed bill of an order with the tenders taken so far
+type PaymentResult struct {
+	OrderBill
+	Tenders   []Payment    `json:"tenders"`
+	Paid   float64    `json:"paid"` // sum of the tender amounts
+	Due   float64    `json:"due"`
+	Change   float64    `json:"change"`
+	IsPaid   bool    `json:"is_paid"`
+}
+
 // Purchase ...
 type PaymentCustomer struct {
 	Customer        string     `json:"customer"`
@@ -41,7 +85,8 @@ type PaymentProduct struct {
 
 // PurchaseUsecase represent the Purchase's usecases
 type PaymentUsecase interface {
-	Payment(ctx context.Context, pay RequestPayment ) error // all access
+	Payment(ctx context.Context, pay RequestPayment ) (PaymentResult, error) // all access
+	GetByOrderID(ctx context.Context, orderId int64, userId int64) (PaymentResult, error) // all access
 	GetByCustomerID(c context.Context, id int64) ([]PaymentCustomer, error)
 	GetByProductID(c context.Context, id int64) ([]PaymentProduct, error)
 }
@@ -49,6 +94,7 @@ type PaymentUsecase interface {
 // PurchaseRepository represent the Purchase's repository contract
 type PaymentRepository interface {
 	Payment(c context.Context, payment RequestPayment) (err error) // all access
+	FetchByOrderID(ctx context.Context, orderId int64) (res []Payment, err error) // all access
 	PaymentByCustomer(ctx context.Context, id int64) (res []PaymentCustomer,err error)
 	PaymentByProduct(ctx context.Context, id int64) (res []PaymentProduct,err error)
 }
@@ -0,0 +1,32 @@
+package domain
+
+// RoleSuperAdmin is the role holding every permission, its users can be forced to use 2FA
+const RoleSuperAdmin = 1
+
+// Permission names, a role holds its permissions through the role_permissions table
+const (
+	PermProductRead        = "product.read"
+	PermProductWrite       = "product.write"
+	PermProductOutletRead  = "product_outlet.read"
+	PermProductOutletWrite = "product_outlet.write"
+	PermStockUpdate        = "stock.update"
+	PermCustomerRead       = "customer.read"
+	PermCustomerWrite      = "customer.write"
+	PermSupplierRead       = "supplier.read"
+	PermSupplierWrite      = "supplier.write"
+	PermPurchaseRead       = "purchase.read"
+	PermPurchaseWrite      = "purchase.write"
+	PermMediaUpload        = "media.upload"
+	PermSell               = "order.sell" // carts, payments, receipts and own shifts
+	PermReportRead         = "report.read"
+	PermRefundManage       = "refund.manage"
+	PermReceiptTemplate    = "receipt.template"
+	PermReceiptReprint     = "receipt.reprint"
+	PermShiftManage        = "shift.manage"
+	PermOutletAll          = "outlet.all" // work in every outlet without a user_outlets row
+	PermUserManage         = "user.manage"
+	PermTerminalManage     = "terminal.manage"
+	PermApiKeyManage       = "api_key.manage"
+	PermUserImpersonate    = "user.impersonate"
+	PermOutletManage       = "outlet.manage"
+)
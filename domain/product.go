@@ -15,6 +15,8 @@ type Product struct {
 	Unit   *string    `json:"unit" `
 	Image   string    `json:"image" `
 	Category    Category    `json:"category"`
+	Options   []ProductOption  `json:"options,omitempty"`  // the option axes, filled on a single product
+	Variants  []ProductVariant `json:"variants,omitempty"` // every combination of the options, filled on a single product
 	UpdatedAt time.Time `json:"update_at"`
 	CreatedAt time.Time `json:"create_at"`
 }
@@ -39,11 +41,13 @@ type ProductUsecase interface {
 	GetByName(ctx context.Context, name string) (Product, error)// all access
 	Store(ctx context.Context,prod  *RequestProduct ) error// super admin
 	Delete(c context.Context, id int64) (err error) // super admin
+	SetOptions(ctx context.Context, id int64, options []ProductOption) (Product, error) // super admin
 }
 
 // ProductRepository represent the Product's repository contract
 type ProductRepository interface {
 	Fetch(ctx context.Context, cursor string, num int64) (res []Product, nextCursor string, err error) // super admin dan merchant
+	FetchByCategory(ctx context.Context, cursor string, num int64, categoryIds []int64) (res []Product, nextCursor string, err error)
 	GetByID(ctx context.Context, id int64) (Product, error) // all access
 	GetByName(ctx context.Context, name string) (Product, error) // all acess
 	Update(ctx context.Context, ar *RequestProduct) error  // super admin
@@ -15,12 +15,15 @@ type ProductOutlets struct {
 	QuantityUse   *float64    `json:"quantity_use" `
 	Outlet		Outlet    `json:"outlet"`
 	Supplier    Supplier  `json:"supplier"`
+	VariantID   int64     `json:"variant_id"` // 0 when the row is the product itself
 	UpdatedAt time.Time `json:"update_at"`
 	CreatedAt time.Time `json:"create_at"`
 }
 
 type RequestProductOutlets struct {
 	ID        int64     `json:"id"`
+	ProductID   int64   `json:"product_id"`
+	VariantID   int64   `json:"variant_id"` // the variant of the product sold under this SKU, on store only
 	Sku     string    `json:"sku" validate:"required"`
 	Price   float64    `json:"price"  validate:"required"`
 	QuantityStock   float64    `json:"quantity"  validate:"required"`
@@ -54,6 +57,8 @@ type ProductOutletsRepository interface {
 	GetBySku(ctx context.Context, sku string) (ProductOutlets, error) // all acess
 	Update(ctx context.Context, ar *RequestProductOutlets) error  // super admin
 	UpdateStock(ctx context.Context, stock ProductOutletsStock) (err error) //all access
+	ReserveStock(ctx context.Context, productId int64, outletId int64, qty float64) (err error) //all access
+	ReleaseStock(ctx context.Context, productId int64, outletId int64, qty float64) (err error) //super admin
 	Store(ctx context.Context, a *RequestProductOutlets) error //super admin
 	Delete(ctx context.Context, id int64) error  // super admin
 }
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// MaxProductOptions is the number of option axes of a product, such as size, color and material
+const MaxProductOptions = 3
+
+// ProductOption is an axis of the variants of a product, the variants are every combination of the option values
+type ProductOption struct {
+	ID       int64    `json:"id"`
+	Position int      `json:"position"`
+	Name     string   `json:"name" validate:"required,max=50"`
+	Values   []string `json:"values" validate:"required,min=1,dive,required,max=50"`
+}
+
+// ProductVariant is a combination of one value of each option of the product, Options follow the order of the options
+type ProductVariant struct {
+	ID        int64          `json:"id"`
+	ProductID int64          `json:"product_id"`
+	Name      string         `json:"name"`
+	Options   []string       `json:"options"`
+	Stocks    []VariantStock `json:"stocks"`
+	UpdatedAt time.Time      `json:"update_at"`
+	CreatedAt time.Time      `json:"create_at"`
+}
+
+// VariantStock is the outlet_products row of a variant in an outlet, with its own SKU, price and stock
+type VariantStock struct {
+	ID            int64   `json:"id"`
+	VariantID     int64   `json:"variant_id"`
+	OutletID      int64   `json:"outlet_id"`
+	Sku           string  `json:"sku"`
+	Price         float64 `json:"price"`
+	QuantityStock float64 `json:"quantity"`
+	QuantityUse   float64 `json:"quantity_use"`
+}
+
+// RequestProductOptions replace the options of a product and generate its variants
+type RequestProductOptions struct {
+	Options []ProductOption `json:"options" validate:"max=3,dive"`
+}
+
+// ProductVariantRepository represent the ProductVariant's repository contract
+type ProductVariantRepository interface {
+	FetchOptions(ctx context.Context, productId int64) ([]ProductOption, error)
+	ReplaceOptions(ctx context.Context, productId int64, options []ProductOption) error
+	FetchVariants(ctx context.Context, productId int64) ([]ProductVariant, error)
+	GetVariantByID(ctx context.Context, id int64) (ProductVariant, error)
+	StoreVariant(ctx context.Context, m *ProductVariant) error
+	DeleteVariant(ctx context.Context, id int64) error
+	FetchStocks(ctx context.Context, productId int64) ([]VariantStock, error)
+}
@@ -49,7 +49,7 @@ type RequestPurchase struct {
 
 // PurchaseUsecase represent the Purchase's usecases
 type PurchaseUsecase interface {
-	Fetch(ctx context.Context, cursor string, num int64) ([]Purchase, string, error) // all access
+	Fetch(ctx context.Context, cursor string, num int64, outlet int64) ([]Purchase, string, error) // all access
 	GetByID(ctx context.Context, id int64) (Purchase, error)// all access
 	Update(ctx context.Context, ar *RequestPurchase ) error // super admin
 	GetBySupplierID(ctx context.Context, id int64) ([]PurchaseBySupplier, error) // super admin
@@ -60,7 +60,7 @@ type PurchaseUsecase interface {
 
 // PurchaseRepository represent the Purchase's repository contract
 type PurchaseRepository interface {
-	Fetch(ctx context.Context, cursor string, num int64) (res []Purchase, nextCursor string, err error) //  super admin
+	Fetch(ctx context.Context, cursor string, num int64, outlet int64) (res []Purchase, nextCursor string, err error) //  super admin
 	GetByID(ctx context.Context, id int64) (Purchase, error) //  super admin
 	GetBySupplierID(ctx context.Context, id int64) (res []PurchaseBySupplier, err error) // super admin
 	GetByProductID(ctx context.Context, id int64) (res []PurchaseByProduct, err error)
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ReceiptFormat is the output format of a receipt
+type ReceiptFormat string
+
+// Receipt formats
+const (
+	ReceiptFormatText   ReceiptFormat = "text"
+	ReceiptFormatEscPos ReceiptFormat = "escpos"
+	ReceiptFormatPDF    ReceiptFormat = "pdf"
+)
+
+// Valid return true if f is one of the supported receipt formats
+func (f ReceiptFormat) Valid() bool {
+	switch f {
+	case ReceiptFormatText, ReceiptFormatEscPos, ReceiptFormatPDF:
+		return true
+	}
+	return false
+}
+
+// ReceiptTemplate is the receipt layout of an outlet, Body is a text/template rendered with a Receipt,
+// the built in layout is used when it is empty
+type ReceiptTemplate struct {
+	ID        int64     `json:"id"`
+	OutletID   int64    `json:"outlet_id"`
+	Header   string    `json:"header" validate:"max=255"`
+	Footer   string    `json:"footer" validate:"max=255"`
+	Width   int    `json:"width" validate:"omitempty,min=24,max=80"` // characters per line
+	Body   string    `json:"body"`
+	CreatedAt time.Time `json:"create_at"`
+	UpdatedAt time.Time `json:"update_at"`
+}
+
+// Receipt is the data of a paid order given to the receipt template
+type Receipt struct {
+	PaymentResult
+	Outlet   Outlet    `json:"outlet"`
+	Order   Order    `json:"order"`
+	Header   string    `json:"header"`
+	Footer   string    `json:"footer"`
+	Width   int    `json:"width"`
+	Date   time.Time    `json:"date"`
+}
+
+// ReceiptUsecase represent the Receipt's usecases
+type ReceiptUsecase interface {
+	Render(ctx context.Context, orderId int64, userId int64, format ReceiptFormat) ([]byte, error) // all access
+	OrderOutlet(ctx context.Context, orderId int64) (int64, error) // all access
+	GetTemplate(ctx context.Context, outletId int64) (ReceiptTemplate, error) // super admin
+	UpdateTemplate(ctx context.Context, tpl *ReceiptTemplate) error // super admin
+}
+
+// ReceiptRepository represent the Receipt's repository contract
+type ReceiptRepository interface {
+	GetTemplate(ctx context.Context, outletId int64) (ReceiptTemplate, error) // all access
+	StoreTemplate(ctx context.Context, tpl *ReceiptTemplate) error // super admin
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Refund is a return against a paid order, its amount is given back on one or more tenders of the order
+type Refund struct {
+	ID        int64     `json:"id"`
+	OrderID   int64    `json:"order_id"`
+	UserID   int64    `json:"user_id"` // supervisor who made the refund
+	ActorID   int64    `json:"-"` // support staff who made it with an impersonation token
+	Reason   string    `json:"reason"`
+	Amount   float64    `json:"amount"`
+	Items   []RefundItem    `json:"items"`
+	Payments   []RefundPayment    `json:"payments"`
+	CreatedAt time.Time `json:"create_at"`
+	UpdatedAt time.Time `json:"update_at"`
+}
+
+// RefundPayment is the part of a refund given back on one payments row
+type RefundPayment struct {
+	ID        int64     `json:"id"`
+	RefundID   int64    `json:"refund_id"`
+	PaymentID   int64    `json:"payment_id"`
+	TypePayment   PaymentMethod    `json:"type_payment"`
+	Amount   float64    `json:"amount"`
+	ShiftID   int64    `json:"shift_id"` // open shift the cash was taken from, empty for the other tenders
+	CreatedAt time.Time `json:"create_at"`
+	UpdatedAt time.Time `json:"update_at"`
+}
+
+// RefundItem is the returned quantity of one order line
+type RefundItem struct {
+	ID        int64     `json:"id"`
+	RefundID   int64    `json:"refund_id"`
+	OrderItemID   int64    `json:"order_item_id"`
+	ProductID   int64    `json:"product_id"`
+	Qty   float64    `json:"qty"`
+	Amount   float64    `json:"amount"`
+	CreatedAt time.Time `json:"create_at"`
+	UpdatedAt time.Time `json:"update_at"`
+}
+
+type RequestRefund struct {
+	OrderID   int64    `json:"order_id" validate:"required"`
+	PaymentID   int64    `json:"payment_id"` // tender to give back on first, the rest goes on the cash tenders then the others
+	UserID   int64    `json:"-"`
+	ActorID   int64    `json:"-"`
+	Reason   string    `json:"reason" validate:"required,max=255"`
+	Items   []RequestRefundItem    `json:"items" validate:"dive"` // every remaining quantity when empty
+}
+
+type RequestRefundItem struct {
+	OrderItemID   int64    `json:"order_item_id" validate:"required"`
+	Qty   float64    `json:"qty" validate:"gt=0"`
+}
+
+// RefundUsecase represent the Refund's usecases
+type RefundUsecase interface {
+	Fetch(ctx context.Context, cursor string, num int64, outletId int64) ([]Refund, string, error) // super admin
+	GetByID(ctx context.Context, id int64) (Refund, error) // super admin
+	FetchByOrderID(ctx context.Context, orderId int64) ([]Refund, error) // super admin
+	Refund(ctx context.Context, m *RequestRefund) (Refund, error) // super admin
+	OrderOutlet(ctx context.Context, orderId int64) (int64, error) // super admin
+}
+
+// RefundRepository represent the Refund's repository contract
+type RefundRepository interface {
+	Fetch(ctx context.Context, cursor string, num int64, outletId int64) (res []Refund, nextCursor string, err error) // outlet 0 fetch the refunds of every outlet
+	GetByID(ctx context.Context, id int64) (Refund, error) // super admin
+	FetchByOrderID(ctx context.Context, orderId int64) (res []Refund, err error) // super admin
+	FetchItems(ctx context.Context, refundId int64) (res []RefundItem, err error) // super admin
+	FetchPayments(ctx context.Context, refundId int64) (res []RefundPayment, err error) // super admin
+	Returned(ctx context.Context, orderItemId int64) (qty float64, amount float64, err error) // super admin
+	RefundedAmount(ctx context.Context, paymentId int64) (amount float64, err error) // super admin
+	Store(ctx context.Context, a *Refund) error // super admin
+	StoreItem(ctx context.Context, a *RefundItem) error // super admin
+	StorePayment(ctx context.Context, a *RefundPayment) error // super admin
+}
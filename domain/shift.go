@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Shift status
+const (
+	ShiftOpen   = "open"
+	ShiftClosed = "closed"
+)
+
+// Cash drawer movement types
+const (
+	MovementIn  = "in"
+	MovementOut = "out"
+)
+
+// Shift is the cash drawer session of a cashier in an outlet
+type Shift struct {
+	ID        int64     `json:"id"`
+	UserID   int64    `json:"user_id"`
+	ActorID   int64    `json:"-"` // support staff who opened the shift with an impersonation token
+	OutletID   int64    `json:"outlet_id"`
+	Status   string    `json:"status"`
+	OpeningFloat   float64    `json:"opening_float"`
+	ExpectedCash   *float64    `json:"expected_cash"` // kept when the shift is closed
+	CountedCash   *float64    `json:"counted_cash"`
+	Note   string    `json:"note"`
+	OpenedAt   time.Time    `json:"opened_at"`
+	ClosedAt   *time.Time    `json:"closed_at"`
+	CreatedAt time.Time `json:"create_at"`
+	UpdatedAt time.Time `json:"update_at"`
+}
+
+// ShiftMovement is cash put in or taken out of the drawer outside of a sale
+type ShiftMovement struct {
+	ID        int64     `json:"id"`
+	ShiftID   int64    `json:"shift_id"`
+	UserID   int64    `json:"user_id"`
+	ActorID   int64    `json:"-"` // support staff who moved the cash with an impersonation token
+	Type   string    `json:"type" validate:"required,oneof=in out"`
+	Amount   float64    `json:"amount" validate:"required,gt=0"`
+	Reason   string    `json:"reason" validate:"required,max=255"`
+	CreatedAt time.Time `json:"create_at"`
+	UpdatedAt time.Time `json:"update_at"`
+}
+
+type RequestOpenShift struct {
+	OutletID   int64    `json:"outlet_id" validate:"required"`
+	OpeningFloat   float64    `json:"opening_float" validate:"min=0"`
+	UserID   int64    `json:"-"`
+	ActorID   int64    `json:"-"`
+}
+
+type RequestCloseShift struct {
+	ID   int64    `json:"-"`
+	CountedCash   float64    `json:"counted_cash" validate:"min=0"`
+	Note   string    `json:"note" validate:"max=255"`
+	UserID   int64    `json:"-"`
+}
+
+// ShiftTender sum the payments of one method taken during a shift
+type ShiftTender struct {
+	TypePayment   PaymentMethod    `json:"type_payment"`
+	Count   int    `json:"count"`
+	Amount   float64    `json:"amount"`
+}
+
+// ShiftReport reconcile the drawer of a shift, an X report is taken while the shift is open
+// and the Z report when it is closed
+type ShiftReport struct {
+	Type   string    `json:"type"`
+	Shift   Shift    `json:"shift"`
+	Orders   int    `json:"orders"`
+	Sales   float64    `json:"sales"`
+	Tenders   []ShiftTender    `json:"tenders"`
+	Movements   []ShiftMovement    `json:"movements"`
+	CashSales   float64    `json:"cash_sales"`
+	CashRefunds   float64    `json:"cash_refunds"` // cash given back from the drawer by refunds
+	CashIn   float64    `json:"cash_in"`
+	CashOut   float64    `json:"cash_out"`
+	ExpectedCash   float64    `json:"expected_cash"`
+	CountedCash   *float64    `json:"counted_cash"`
+	Difference   *float64    `json:"difference"` // counted minus expected, negative when cash is missing
+}
+
+// Reconcile fill the report totals from its tenders, cash refunds and movements
+func (r *ShiftReport) Reconcile() {
+	r.Sales, r.CashSales, r.CashIn, r.CashOut = 0, 0, 0, 0
+	for _, t := range r.Tenders {
+		r.Sales += t.Amount
+		if t.TypePayment == PaymentMethodCash {
+			r.CashSales += t.Amount
+		}
+	}
+	for _, m := range r.Movements {
+		if m.Type == MovementIn {
+			r.CashIn += m.Amount
+		} else {
+			r.CashOut += m.Amount
+		}
+	}
+	r.Sales = RoundMoney(r.Sales)
+	r.CashSales = RoundMoney(r.CashSales)
+	r.CashIn = RoundMoney(r.CashIn)
+	r.CashOut = RoundMoney(r.CashOut)
+	r.CashRefunds = RoundMoney(r.CashRefunds)
+	r.ExpectedCash = RoundMoney(r.Shift.OpeningFloat + r.CashSales - r.CashRefunds + r.CashIn - r.CashOut)
+
+	r.Type = "X"
+	r.CountedCash, r.Difference = nil, nil
+	if r.Shift.Status == ShiftClosed {
+		r.Type = "Z"
+		r.CountedCash = r.Shift.CountedCash
+		if r.CountedCash != nil {
+			diff := RoundMoney(*r.CountedCash - r.ExpectedCash)
+			r.Difference = &diff
+		}
+	}
+}
+
+// ShiftUsecase represent the Shift's usecases
+type ShiftUsecase interface {
+	Fetch(ctx context.Context, cursor string, num int64, outletId int64) ([]Shift, string, error) // super admin
+	Current(ctx context.Context, userId int64, outletId int64) (Shift, error) // all access
+	Open(ctx context.Context, m *RequestOpenShift) (Shift, error) // all access
+	AddMovement(ctx context.Context, m *ShiftMovement) error // all access
+	Report(ctx context.Context, id int64, userId int64) (ShiftReport, error) // all access
+	Close(ctx context.Context, m *RequestCloseShift) (ShiftReport, error) // all access
+}
+
+// ShiftRepository represent the Shift's repository contract
+type ShiftRepository interface {
+	Fetch(ctx context.Context, cursor string, num int64, outletId int64) (res []Shift, nextCursor string, err error) // super admin
+	GetByID(ctx context.Context, id int64) (Shift, error) // all access
+	GetOpen(ctx context.Context, userId int64, outletId int64) (Shift, error) // all access
+	Store(ctx context.Context, a *Shift) error // all access
+	Close(ctx context.Context, a *Shift) error // all access
+	StoreMovement(ctx context.Context, a *ShiftMovement) error // all access
+	FetchMovements(ctx context.Context, shiftId int64) (res []ShiftMovement, err error) // all access
+	FetchTenders(ctx context.Context, shiftId int64) (res []ShiftTender, orders int, err error) // all access
+	CashRefunded(ctx context.Context, shiftId int64) (amount float64, err error) // all access
+}
@@ -0,0 +1,45 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"mini_pos/domain"
+)
+
+func TestShiftReportReconcile(t *testing.T) {
+	report := domain.ShiftReport{
+		Shift: domain.Shift{Status: domain.ShiftOpen, OpeningFloat: 200000},
+		Tenders: []domain.ShiftTender{
+			{TypePayment: domain.PaymentMethodCash, Count: 3, Amount: 150000},
+			{TypePayment: domain.PaymentMethodCard, Count: 1, Amount: 75000.5},
+		},
+		Movements: []domain.ShiftMovement{
+			{Type: domain.MovementIn, Amount: 50000},
+			{Type: domain.MovementOut, Amount: 20000},
+		},
+	}
+	report.Reconcile()
+
+	assert.Equal(t, "X", report.Type)
+	assert.Equal(t, 225000.5, report.Sales)
+	assert.Equal(t, 150000.0, report.CashSales)
+	assert.Equal(t, 380000.0, report.ExpectedCash)
+	assert.Nil(t, report.Difference)
+
+	counted := 379000.0
+	report.Shift.Status = domain.ShiftClosed
+	report.Shift.CountedCash = &counted
+	report.Reconcile()
+
+	assert.Equal(t, "Z", report.Type)
+	assert.Equal(t, -1000.0, *report.Difference)
+
+	// the cash handed back by a refund leaves the drawer
+	report.CashRefunds = 25000
+	report.Reconcile()
+
+	assert.Equal(t, 355000.0, report.ExpectedCash)
+	assert.Equal(t, 24000.0, *report.Difference)
+}
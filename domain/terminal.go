@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Terminal status, a revoked terminal can no longer switch cashiers
+const (
+	TerminalActive  = "active"
+	TerminalRevoked = "revoked"
+)
+
+// Terminal is a device trusted for an outlet, the cashiers switch on it with their PIN
+type Terminal struct {
+	ID         int64      `json:"id"`
+	OutletID   int64      `json:"outlet_id" validate:"required"`
+	Name       string     `json:"name" validate:"required,max=100"`
+	Status     string     `json:"status"`
+	Token      string     `json:"token,omitempty"` // only answered on registration, the terminal keeps it
+	CreatedBy  int64      `json:"created_by"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+	UpdatedAt  time.Time  `json:"update_at"`
+	CreatedAt  time.Time  `json:"create_at"`
+}
+
+// RequestPinLogin switch the active cashier of a terminal
+type RequestPinLogin struct {
+	TerminalToken string `json:"terminal_token" validate:"required"`
+	UserName      string `json:"username" validate:"required"`
+	Pin           string `json:"pin" validate:"required"`
+}
+
+// RequestSetPin set the PIN of the token holder, its password is asked again
+type RequestSetPin struct {
+	Password string `json:"password" validate:"required"`
+	Pin      string `json:"pin" validate:"required"`
+}
+
+// TerminalUsecase represent the Terminal's usecases
+type TerminalUsecase interface {
+	Fetch(ctx context.Context, cursor string, num int64, outletId int64) ([]Terminal, string, error) // super admin
+	GetByID(ctx context.Context, id int64) (Terminal, error)                                          // super admin
+	Register(ctx context.Context, m *Terminal) error                                                  // super admin
+	Revoke(ctx context.Context, id int64) (Terminal, error)                                           // super admin
+}
+
+// TerminalRepository represent the Terminal's repository contract
+type TerminalRepository interface {
+	Fetch(ctx context.Context, cursor string, num int64, outletId int64) (res []Terminal, nextCursor string, err error)
+	GetByID(ctx context.Context, id int64) (Terminal, error)
+	GetByToken(ctx context.Context, tokenHash string) (Terminal, error)
+	Store(ctx context.Context, m *Terminal, tokenHash string) error
+	SetStatus(ctx context.Context, id int64, status string) error
+	Touch(ctx context.Context, id int64) error
+}
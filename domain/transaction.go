@@ -0,0 +1,10 @@
+package domain
+
+import (
+	"context"
+)
+
+// Transactor represent the contract to run several repository calls inside one database transaction
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TwoFactorChallenge answer a login whose password is right but which waits for the TOTP code of the user.
+// SetupRequired is set when the role of the user must use 2FA and the user has not enrolled yet
+type TwoFactorChallenge struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	SetupRequired     bool   `json:"setup_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresAt         int64  `json:"expires_at"`
+}
+
+// TwoFactorPending is the second login step kept for a challenge token
+type TwoFactorPending struct {
+	UserID    int       `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// TwoFactorSetup is the secret of a TOTP enrollment, the URI is shown as a QR code to the authenticator app
+type TwoFactorSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// RecoveryCodes are shown once, each of them stands in for a TOTP code a single time
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+// RequestTwoFactorLogin finish a login with the TOTP code or a recovery code. When the setup of the user is
+// required the code confirms the enrollment started with the same challenge token
+type RequestTwoFactorLogin struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+}
+
+// RequestTwoFactorSetup start the enrollment of a user who must use 2FA before the first login
+type RequestTwoFactorSetup struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+}
+
+// RequestTwoFactorCode carry the TOTP code confirming an action of the token holder
+type RequestTwoFactorCode struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// RequestDisableTwoFactor turn 2FA off, the password and a TOTP or recovery code are asked again
+type RequestDisableTwoFactor struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+// TwoFactorRepository keep the login challenges and the enrollments waiting for their first code
+type TwoFactorRepository interface {
+	StoreChallenge(ctx context.Context, token string, m TwoFactorPending) error
+	GetChallenge(ctx context.Context, token string) (TwoFactorPending, error) // ErrNotFound when missing or expired
+	DeleteChallenge(ctx context.Context, token string) error
+	StoreSetup(ctx context.Context, userId int, secret string, ttl time.Duration) error
+	GetSetup(ctx context.Context, userId int) (string, error) // ErrNotFound when missing or expired
+	DeleteSetup(ctx context.Context, userId int) error
+	UseStep(ctx context.Context, userId int, step int64, ttl time.Duration) (bool, error) // false when the step was used already
+}
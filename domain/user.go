@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// User status, a suspended user cannot login and its sessions are ended,
+// a pending user signed up itself and waits for a super admin to approve it
+const (
+	UserActive    = "active"
+	UserSuspended = "suspended"
+	UserPending   = "pending"
+)
+
+// UserAccount is a user as managed by the super admin, the password is never part of it
+type UserAccount struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	UserName  string     `json:"username"`
+	Email     string     `json:"email"`
+	RoleID    int        `json:"role_id"`
+	Role      string     `json:"role"`
+	Status    string     `json:"status"`
+	Outlets   []int64    `json:"outlets"`
+	UpdatedAt *time.Time `json:"update_at"`
+	CreatedAt time.Time  `json:"create_at"`
+}
+
+// UserFilter narrow the user list, an empty field does not filter
+type UserFilter struct {
+	Search string // matched against the name, username and email
+	RoleID int
+	Status string
+}
+
+// RequestInviteUser create an account, the user sets its own password from the mailed link
+type RequestInviteUser struct {
+	Name     string  `json:"name" validate:"required,max=150"`
+	UserName string  `json:"username" validate:"required,max=100"`
+	Email    string  `json:"email" validate:"required,email,max=75"`
+	RoleID   int     `json:"role_id" validate:"required"`
+	Outlets  []int64 `json:"outlets"`
+}
+
+type RequestUpdateUser struct {
+	ID       int    `json:"-"`
+	Name     string `json:"name" validate:"required,max=150"`
+	UserName string `json:"username" validate:"required,max=100"`
+	Email    string `json:"email" validate:"required,email,max=75"`
+}
+
+type RequestUserRole struct {
+	RoleID int `json:"role_id" validate:"required"`
+}
+
+// RequestApproveUser activate a pending user into a role and outlets
+type RequestApproveUser struct {
+	RoleID  int     `json:"role_id" validate:"required"`
+	Outlets []int64 `json:"outlets"`
+}
+
+type RequestUserOutlets struct {
+	Outlets []int64 `json:"outlets"`
+}
+
+// SetPasswordMail is the data of the MailSetPasswordTemplate template
+type SetPasswordMail struct {
+	Name          string
+	Intro         string
+	Link          string
+	Token         string
+	LogoURL       string
+	ExpiresInHour int
+}
+
+// UserUsecase represent the usecases of the user management
+type UserUsecase interface {
+	Fetch(ctx context.Context, cursor string, num int64, filter UserFilter) ([]UserAccount, string, error) // super admin
+	GetByID(ctx context.Context, id int) (UserAccount, error)                                               // super admin
+	Invite(ctx context.Context, m *RequestInviteUser) (UserAccount, error)                                  // super admin
+	Update(ctx context.Context, m *RequestUpdateUser) (UserAccount, error)                                  // super admin
+	SetRole(ctx context.Context, id int, roleId int) (UserAccount, error)                                   // super admin
+	SetStatus(ctx context.Context, id int, status string) (UserAccount, error)                              // super admin
+	ResetPassword(ctx context.Context, id int) error                                                        // super admin
+	SetOutlets(ctx context.Context, id int, outlets []int64) (UserAccount, error)                           // super admin
+	AssignOutlet(ctx context.Context, id int, outletId int64) (UserAccount, error)                          // super admin
+	UnassignOutlet(ctx context.Context, id int, outletId int64) (UserAccount, error)                        // super admin
+	Delete(ctx context.Context, id int) error                                                               // super admin
+	Approve(ctx context.Context, id int, m *RequestApproveUser) (UserAccount, error)                        // super admin
+	Reject(ctx context.Context, id int) error                                                               // super admin
+}
+
+// UserRepository represent the repository contract of the user management
+type UserRepository interface {
+	Fetch(ctx context.Context, cursor string, num int64, filter UserFilter) (res []UserAccount, nextCursor string, err error)
+	GetByID(ctx context.Context, id int) (UserAccount, error)
+	GetByUserNameOrEmail(ctx context.Context, username string, email string) ([]UserAccount, error)
+	Store(ctx context.Context, m *UserAccount, password string) error
+	Update(ctx context.Context, m *RequestUpdateUser) error
+	SetRole(ctx context.Context, id int, roleId int) error
+	SetStatus(ctx context.Context, id int, status string) error
+	SetPassword(ctx context.Context, id int, password string) error
+	RoleExists(ctx context.Context, roleId int) (bool, error)
+	FetchOutlets(ctx context.Context, id int) ([]int64, error)
+	AssignOutlet(ctx context.Context, id int, outletId int64) error
+	UnassignOutlet(ctx context.Context, id int, outletId int64) error
+	Delete(ctx context.Context, id int) error
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"time"
+)
+
+// Status of orders and order lines, a voided row is kept for the audit instead of being deleted
+const (
+	StatusActive = "active"
+	StatusVoid   = "void"
+)
+
+// VoidReason is the reason code given when an order or a line is voided
+type VoidReason string
+
+// Void reason codes
+const (
+	VoidReasonCustomerCancel VoidReason = "customer_cancel"
+	VoidReasonWrongItem      VoidReason = "wrong_item"
+	VoidReasonPriceChange    VoidReason = "price_change"
+	VoidReasonDamaged        VoidReason = "damaged"
+	VoidReasonOther          VoidReason = "other"
+)
+
+// Valid return true if r is one of the supported reason codes
+func (r VoidReason) Valid() bool {
+	switch r {
+	case VoidReasonCustomerCancel, VoidReasonWrongItem, VoidReasonPriceChange, VoidReasonDamaged, VoidReasonOther:
+		return true
+	}
+	return false
+}
+
+// RequestVoid is used to void an open order or one of its lines
+type RequestVoid struct {
+	ID   int64    `json:"-"`
+	Reason   VoidReason    `json:"reason" query:"reason" validate:"required"`
+	UserID   int64    `json:"-"`
+	ActorID   int64    `json:"-"` // support staff behind an impersonation token, kept in void_actor_id
+}
+
+// VoidEntry is one voided order or line, OrderItemID is empty when the whole order was voided
+type VoidEntry struct {
+	OrderID   int64    `json:"order_id"`
+	OrderItemID   int64    `json:"order_item_id"`
+	ProductID   int64    `json:"product_id"`
+	Qty   float64    `json:"qty"`
+	Amount   float64    `json:"amount"` // value of the voided goods at the outlet price
+	OutletID   int64    `json:"outlet_id"`
+	UserID   int64    `json:"user_id"` // cashier owning the order
+	Reason   VoidReason    `json:"reason"`
+	VoidBy   int64    `json:"void_by"`
+	VoidAt   time.Time    `json:"void_at"`
+}
+
+// VoidFilter select the voids of a report, empty ids mean every outlet or user
+type VoidFilter struct {
+	From   time.Time
+	To   time.Time
+	OutletID   int64
+	UserID   int64
+}
+
+// VoidTotal count the voids of one reason or one user
+type VoidTotal struct {
+	Count   int    `json:"count"`
+	Amount   float64    `json:"amount"`
+}
+
+// VoidReport list the voids of a period with totals per reason and per voiding user
+type VoidReport struct {
+	From   time.Time    `json:"from"`
+	To   time.Time    `json:"to"`
+	Entries   []VoidEntry    `json:"entries"`
+	Total   VoidTotal    `json:"total"`
+	ByReason   map[VoidReason]VoidTotal    `json:"by_reason"`
+	ByUser   map[int64]VoidTotal    `json:"by_user"`
+}
+
+// Add count e in the report totals
+func (r *VoidReport) Add(e VoidEntry) {
+	if r.ByReason == nil {
+		r.ByReason = make(map[VoidReason]VoidTotal)
+	}
+	if r.ByUser == nil {
+		r.ByUser = make(map[int64]VoidTotal)
+	}
+	r.Entries = append(r.Entries, e)
+	r.Total = r.Total.add(e.Amount)
+	r.ByReason[e.Reason] = r.ByReason[e.Reason].add(e.Amount)
+	r.ByUser[e.VoidBy] = r.ByUser[e.VoidBy].add(e.Amount)
+}
+
+func (t VoidTotal) add(amount float64) VoidTotal {
+	return VoidTotal{
+		Count:  t.Count + 1,
+		Amount: RoundMoney(t.Amount + amount),
+	}
+}
@@ -0,0 +1,26 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"mini_pos/domain"
+)
+
+func TestVoidReportAdd(t *testing.T) {
+	report := domain.VoidReport{}
+	report.Add(domain.VoidEntry{OrderID: 1, Reason: domain.VoidReasonWrongItem, VoidBy: 7, Amount: 50000})
+	report.Add(domain.VoidEntry{OrderID: 2, OrderItemID: 3, Reason: domain.VoidReasonWrongItem, VoidBy: 8, Amount: 65000.5})
+	report.Add(domain.VoidEntry{OrderID: 4, Reason: domain.VoidReasonCustomerCancel, VoidBy: 7, Amount: 1000})
+
+	assert.Len(t, report.Entries, 3)
+	assert.Equal(t, domain.VoidTotal{Count: 3, Amount: 116000.5}, report.Total)
+	assert.Equal(t, domain.VoidTotal{Count: 2, Amount: 115000.5}, report.ByReason[domain.VoidReasonWrongItem])
+	assert.Equal(t, domain.VoidTotal{Count: 2, Amount: 51000}, report.ByUser[7])
+}
+
+func TestVoidReasonValid(t *testing.T) {
+	assert.True(t, domain.VoidReasonDamaged.Valid())
+	assert.False(t, domain.VoidReason("lost").Valid())
+}
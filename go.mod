@@ -10,6 +10,7 @@ require (
 	github.com/go-playground/validator/v10 v10.9.0
 	github.com/go-sql-driver/mysql v1.3.0
 	github.com/golang-jwt/jwt v3.2.2+incompatible
+	github.com/gomodule/redigo v1.8.5
 	github.com/hashicorp/hcl v0.0.0-20180404174102-ef8a98b0bbce // indirect
 	github.com/labstack/echo/v4 v4.6.1
 	github.com/labstack/gommon v0.3.0
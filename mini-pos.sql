@@ -17,6 +17,95 @@
 SET NAMES utf8mb4;
 SET FOREIGN_KEY_CHECKS = 0;
 
+-- ----------------------------
+-- Table structure for api_key_outlets
+-- ----------------------------
+DROP TABLE IF EXISTS `api_key_outlets`;
+CREATE TABLE `api_key_outlets`  (
+  `api_key_id` int(11) NOT NULL,
+  `outlet_id` int(11) NOT NULL,
+  PRIMARY KEY (`api_key_id`, `outlet_id`) USING BTREE,
+  INDEX `api_key_outlets_fk1`(`outlet_id`) USING BTREE,
+  CONSTRAINT `api_key_outlets_fk0` FOREIGN KEY (`api_key_id`) REFERENCES `api_keys` (`id`) ON DELETE CASCADE ON UPDATE RESTRICT,
+  CONSTRAINT `api_key_outlets_fk1` FOREIGN KEY (`outlet_id`) REFERENCES `outlets` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE = InnoDB CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for api_key_permissions
+-- ----------------------------
+DROP TABLE IF EXISTS `api_key_permissions`;
+CREATE TABLE `api_key_permissions`  (
+  `api_key_id` int(11) NOT NULL,
+  `permission_id` int(11) NOT NULL,
+  PRIMARY KEY (`api_key_id`, `permission_id`) USING BTREE,
+  INDEX `api_key_permissions_fk1`(`permission_id`) USING BTREE,
+  CONSTRAINT `api_key_permissions_fk0` FOREIGN KEY (`api_key_id`) REFERENCES `api_keys` (`id`) ON DELETE CASCADE ON UPDATE RESTRICT,
+  CONSTRAINT `api_key_permissions_fk1` FOREIGN KEY (`permission_id`) REFERENCES `permissions` (`id`) ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE = InnoDB CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for api_keys
+-- ----------------------------
+DROP TABLE IF EXISTS `api_keys`;
+CREATE TABLE `api_keys`  (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `key_prefix` varchar(12) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `key_hash` char(64) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `status` enum('active','revoked') CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL DEFAULT 'active',
+  `created_by` int(11) NOT NULL,
+  `expires_at` datetime(0) NULL DEFAULT NULL,
+  `last_used_at` datetime(0) NULL DEFAULT NULL,
+  `last_used_ip` varchar(45) CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL DEFAULT NULL,
+  `create_at` datetime(0) NOT NULL,
+  `update_at` datetime(0) NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `api_keys_hash`(`key_hash`) USING BTREE,
+  INDEX `api_keys_fk0`(`created_by`) USING BTREE,
+  CONSTRAINT `api_keys_fk0` FOREIGN KEY (`created_by`) REFERENCES `users` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for audit_logs
+-- ----------------------------
+DROP TABLE IF EXISTS `audit_logs`;
+CREATE TABLE `audit_logs`  (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `actor_id` int(11) NOT NULL,
+  `user_id` int(11) NOT NULL,
+  `action` varchar(50) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `method` varchar(10) CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL DEFAULT NULL,
+  `path` varchar(255) CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL DEFAULT NULL,
+  `status` int(11) NULL DEFAULT NULL,
+  `ip` varchar(45) CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL DEFAULT NULL,
+  `detail` varchar(255) CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL DEFAULT NULL,
+  `create_at` datetime(0) NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `audit_logs_fk0`(`actor_id`) USING BTREE,
+  INDEX `audit_logs_fk1`(`user_id`) USING BTREE,
+  CONSTRAINT `audit_logs_fk0` FOREIGN KEY (`actor_id`) REFERENCES `users` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+  CONSTRAINT `audit_logs_fk1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for barcodes
+-- ----------------------------
+DROP TABLE IF EXISTS `barcodes`;
+CREATE TABLE `barcodes`  (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `outlet_product_id` int(11) NOT NULL,
+  `outlet_id` int(11) NOT NULL,
+  `code` varchar(48) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `type` enum('ean13','upca','code128') CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `create_at` datetime(0) NOT NULL,
+  `update_at` datetime(0) NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `barcodes_outlet_code`(`outlet_id`, `code`) USING BTREE,
+  INDEX `barcodes_fk0`(`outlet_product_id`) USING BTREE,
+  CONSTRAINT `barcodes_fk0` FOREIGN KEY (`outlet_product_id`) REFERENCES `outlet_products` (`id`) ON DELETE CASCADE ON UPDATE RESTRICT,
+  CONSTRAINT `barcodes_fk1` FOREIGN KEY (`outlet_id`) REFERENCES `outlets` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
+
 -- ----------------------------
 -- Table structure for categories
 -- ----------------------------
@@ -27,17 +116,20 @@ CREATE TABLE `categories`  (
   `description` varchar(255) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
   `create_at` datetime(0) NOT NULL,
   `update_at` datetime(0) NOT NULL,
-  `outlet_id` int(11) NOT NULL,
+  `outlet_id` int(11) NULL DEFAULT NULL,
+  `parent_id` int(11) NULL DEFAULT NULL,
   PRIMARY KEY (`id`) USING BTREE,
   INDEX `categories_fk0`(`outlet_id`) USING BTREE,
-  CONSTRAINT `categories_fk0` FOREIGN KEY (`outlet_id`) REFERENCES `outlets` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+  INDEX `categories_fk1`(`parent_id`) USING BTREE,
+  CONSTRAINT `categories_fk0` FOREIGN KEY (`outlet_id`) REFERENCES `outlets` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+  CONSTRAINT `categories_fk1` FOREIGN KEY (`parent_id`) REFERENCES `categories` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
 ) ENGINE = InnoDB AUTO_INCREMENT = 4 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
 
 -- ----------------------------
 -- Records of categories
 -- ----------------------------
-INSERT INTO `categories` VALUES (1, 'Elecronik 1', 'Elektronik Arus Lemah', '2021-11-13 18:30:48', '2021-11-13 18:30:52', 1);
-INSERT INTO `categories` VALUES (3, 'Elektronik 1', 'Elektronik Arus Lemah', '2021-11-13 18:33:16', '2021-11-13 18:33:20', 2);
+INSERT INTO `categories` VALUES (1, 'Elecronik 1', 'Elektronik Arus Lemah', '2021-11-13 18:30:48', '2021-11-13 18:30:52', 1, NULL);
+INSERT INTO `categories` VALUES (3, 'Elektronik 1', 'Elektronik Arus Lemah', '2021-11-13 18:33:16', '2021-11-13 18:33:20', 2, NULL);
 
 -- ----------------------------
 -- Table structure for customers
@@ -71,11 +163,18 @@ CREATE TABLE `orderItem`  (
   `qty` int(11) NOT NULL,
   `discount` int(11) NULL DEFAULT NULL,
   `tax_apply` int(11) NULL DEFAULT NULL,
+  `price` decimal(12, 2) NULL DEFAULT NULL,
+  `status` enum('active','void') CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL DEFAULT 'active',
+  `void_reason` enum('customer_cancel','wrong_item','price_change','damaged','other') CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL DEFAULT NULL,
+  `void_by` int(11) NULL DEFAULT NULL,
+  `void_actor_id` int(11) NULL DEFAULT NULL,
+  `void_at` datetime(0) NULL DEFAULT NULL,
   `create_at` datetime(0) NOT NULL,
   `update_at` datetime(0) NOT NULL,
   PRIMARY KEY (`id`) USING BTREE,
   INDEX `orderItem_fk0`(`order_id`) USING BTREE,
   INDEX `orderItem_fk1`(`product_id`) USING BTREE,
+  INDEX `orderItem_void`(`status`, `void_at`) USING BTREE,
   CONSTRAINT `orderItem_fk0` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
   CONSTRAINT `orderItem_fk1` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
 ) ENGINE = InnoDB AUTO_INCREMENT = 9 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
@@ -83,10 +182,10 @@ CREATE TABLE `orderItem`  (
 -- ----------------------------
 -- Records of orderItem
 -- ----------------------------
-INSERT INTO `orderItem` VALUES (1, 1, 1, 7, NULL, NULL, '2021-11-14 16:20:04', '2021-11-14 18:10:26');
-INSERT INTO `orderItem` VALUES (3, 13, 1, 2, NULL, NULL, '2021-11-14 18:59:28', '2021-11-14 19:00:41');
-INSERT INTO `orderItem` VALUES (5, 14, 1, 2, NULL, NULL, '2021-11-14 19:09:45', '2021-11-14 19:09:51');
-INSERT INTO `orderItem` VALUES (6, 14, 2, 1, NULL, NULL, '2021-11-14 19:09:58', '2021-11-14 19:09:58');
+INSERT INTO `orderItem` VALUES (1, 1, 1, 7, NULL, NULL, 50000.00, 'active', NULL, NULL, NULL, NULL, '2021-11-14 16:20:04', '2021-11-14 18:10:26');
+INSERT INTO `orderItem` VALUES (3, 13, 1, 2, NULL, NULL, 50000.00, 'active', NULL, NULL, NULL, NULL, '2021-11-14 18:59:28', '2021-11-14 19:00:41');
+INSERT INTO `orderItem` VALUES (5, 14, 1, 2, NULL, NULL, 50000.00, 'active', NULL, NULL, NULL, NULL, '2021-11-14 19:09:45', '2021-11-14 19:09:51');
+INSERT INTO `orderItem` VALUES (6, 14, 2, 1, NULL, NULL, 65000.00, 'active', NULL, NULL, NULL, NULL, '2021-11-14 19:09:58', '2021-11-14 19:09:58');
 
 -- ----------------------------
 -- Table structure for orders
@@ -95,20 +194,36 @@ DROP TABLE IF EXISTS `orders`;
 CREATE TABLE `orders`  (
   `id` int(11) NOT NULL AUTO_INCREMENT,
   `customer_id` int(11) NULL DEFAULT NULL,
+  `user_id` int(11) NULL DEFAULT NULL,
+  `actor_id` int(11) NULL DEFAULT NULL,
+  `outlet_id` int(11) NULL DEFAULT NULL,
+  `label` varchar(50) CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL DEFAULT NULL,
+  `tax` decimal(5, 2) NOT NULL DEFAULT 0.00,
   `isCheckout` tinyint(1) NULL DEFAULT 0,
+  `status` enum('active','void') CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL DEFAULT 'active',
+  `void_reason` enum('customer_cancel','wrong_item','price_change','damaged','other') CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL DEFAULT NULL,
+  `void_by` int(11) NULL DEFAULT NULL,
+  `void_actor_id` int(11) NULL DEFAULT NULL,
+  `void_at` datetime(0) NULL DEFAULT NULL,
   `create_at` datetime(0) NOT NULL,
   `update_at` datetime(0) NOT NULL,
   PRIMARY KEY (`id`) USING BTREE,
   INDEX `fk1_orders`(`customer_id`) USING BTREE,
-  CONSTRAINT `fk1_orders` FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`) ON DELETE SET NULL ON UPDATE SET NULL
+  INDEX `fk2_orders`(`user_id`) USING BTREE,
+  INDEX `fk3_orders`(`outlet_id`) USING BTREE,
+  INDEX `orders_open_cart`(`user_id`, `outlet_id`, `isCheckout`) USING BTREE,
+  INDEX `orders_void`(`status`, `void_at`) USING BTREE,
+  CONSTRAINT `fk1_orders` FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`) ON DELETE SET NULL ON UPDATE SET NULL,
+  CONSTRAINT `fk2_orders` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+  CONSTRAINT `fk3_orders` FOREIGN KEY (`outlet_id`) REFERENCES `outlets` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
 ) ENGINE = InnoDB AUTO_INCREMENT = 18 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
 
 -- ----------------------------
 -- Records of orders
 -- ----------------------------
-INSERT INTO `orders` VALUES (1, 1, 1, '2021-11-14 16:19:24', '2021-11-14 19:02:12');
-INSERT INTO `orders` VALUES (13, 1, 1, '2021-11-14 18:52:14', '2021-11-14 18:52:14');
-INSERT INTO `orders` VALUES (14, 1, 1, '2021-11-14 19:09:45', '2021-11-14 19:10:11');
+INSERT INTO `orders` VALUES (1, 1, 7, NULL, 1, NULL, 0.00, 1, 'active', NULL, NULL, NULL, NULL, '2021-11-14 16:19:24', '2021-11-14 19:02:12');
+INSERT INTO `orders` VALUES (13, 1, 7, NULL, 1, NULL, 0.00, 1, 'active', NULL, NULL, NULL, NULL, '2021-11-14 18:52:14', '2021-11-14 18:52:14');
+INSERT INTO `orders` VALUES (14, 1, 7, NULL, 1, NULL, 0.00, 1, 'active', NULL, NULL, NULL, NULL, '2021-11-14 19:09:45', '2021-11-14 19:10:11');
 
 -- ----------------------------
 -- Table structure for outlet_products
@@ -123,23 +238,26 @@ CREATE TABLE `outlet_products`  (
   `quantity_use` float(255, 0) NULL DEFAULT 0,
   `outlet_id` int(11) NOT NULL,
   `supplier_id` int(11) NOT NULL,
+  `variant_id` int(11) NULL DEFAULT NULL,
   `create_at` datetime(0) NULL DEFAULT NULL,
   `update_at` datetime(0) NULL DEFAULT NULL,
   PRIMARY KEY (`id`) USING BTREE,
   UNIQUE INDEX `sku_unique`(`sku`) USING BTREE,
+  UNIQUE INDEX `op_variant_outlet`(`variant_id`, `outlet_id`) USING BTREE,
   INDEX `op_fk1`(`product_id`) USING BTREE,
   INDEX `op_fk2`(`outlet_id`) USING BTREE,
   INDEX `op_fk3`(`supplier_id`) USING BTREE,
   CONSTRAINT `op_fk1` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
   CONSTRAINT `op_fk2` FOREIGN KEY (`outlet_id`) REFERENCES `outlets` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
-  CONSTRAINT `op_fk3` FOREIGN KEY (`supplier_id`) REFERENCES `suppliers` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+  CONSTRAINT `op_fk3` FOREIGN KEY (`supplier_id`) REFERENCES `suppliers` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+  CONSTRAINT `op_fk4` FOREIGN KEY (`variant_id`) REFERENCES `product_variants` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
 ) ENGINE = InnoDB AUTO_INCREMENT = 3 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
 
 -- ----------------------------
 -- Records of outlet_products
 -- ----------------------------
-INSERT INTO `outlet_products` VALUES (1, 1, 'AMB1DS12301', 50000.00, 5, 5, 1, 1, '2021-11-14 12:48:55', '2021-11-14 13:52:17');
-INSERT INTO `outlet_products` VALUES (2, 2, 'AMB1D12302', 65000.00, 5, 0, 1, 1, '2021-11-14 13:14:15', '2021-11-14 13:14:17');
+INSERT INTO `outlet_products` VALUES (1, 1, 'AMB1DS12301', 50000.00, 5, 5, 1, 1, NULL, '2021-11-14 12:48:55', '2021-11-14 13:52:17');
+INSERT INTO `outlet_products` VALUES (2, 2, 'AMB1D12302', 65000.00, 5, 0, 1, 1, NULL, '2021-11-14 13:14:15', '2021-11-14 13:14:17');
 
 -- ----------------------------
 -- Table structure for outlets
@@ -149,6 +267,13 @@ CREATE TABLE `outlets`  (
   `id` int(11) NOT NULL AUTO_INCREMENT,
   `name` varchar(150) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
   `address` varchar(255) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `phone` varchar(30) CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL DEFAULT NULL,
+  `tax_id` varchar(50) CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL DEFAULT NULL,
+  `timezone` varchar(64) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL DEFAULT 'Asia/Jakarta',
+  `currency` char(3) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL DEFAULT 'IDR',
+  `receipt_header` varchar(255) CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL DEFAULT NULL,
+  `receipt_footer` varchar(255) CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL DEFAULT NULL,
+  `status` enum('active','inactive') CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL DEFAULT 'active',
   `create_at` datetime(0) NOT NULL,
   `update_at` datetime(0) NOT NULL,
   PRIMARY KEY (`id`) USING BTREE,
@@ -158,8 +283,8 @@ CREATE TABLE `outlets`  (
 -- ----------------------------
 -- Records of outlets
 -- ----------------------------
-INSERT INTO `outlets` VALUES (1, 'Outlet 1', 'Jl. ABCD berarti', '2021-11-13 18:29:02', '2021-11-13 18:29:09');
-INSERT INTO `outlets` VALUES (2, 'Outlet 2', 'Jl. CSDSD kjdhdfsd', '2021-11-13 18:29:32', '2021-11-13 18:29:36');
+INSERT INTO `outlets` VALUES (1, 'Outlet 1', 'Jl. ABCD berarti', NULL, NULL, 'Asia/Jakarta', 'IDR', NULL, NULL, 'active', '2021-11-13 18:29:02', '2021-11-13 18:29:09');
+INSERT INTO `outlets` VALUES (2, 'Outlet 2', 'Jl. CSDSD kjdhdfsd', NULL, NULL, 'Asia/Jakarta', 'IDR', NULL, NULL, 'active', '2021-11-13 18:29:32', '2021-11-13 18:29:36');
 
 -- ----------------------------
 -- Table structure for payments
@@ -169,21 +294,103 @@ CREATE TABLE `payments`  (
   `id` int(11) NOT NULL AUTO_INCREMENT,
   `order_id` int(11) NOT NULL,
   `tax` float NULL DEFAULT NULL,
-  `type_payment` varchar(15) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `type_payment` enum('cash','card','e-wallet','transfer') CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `reference` varchar(50) CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL DEFAULT NULL,
   `total_payment` decimal(12, 2) NOT NULL,
+  `paid` decimal(12, 2) NOT NULL DEFAULT 0.00,
+  `change_due` decimal(12, 2) NOT NULL DEFAULT 0.00,
+  `amount` decimal(12, 2) NOT NULL DEFAULT 0.00,
+  `shift_id` int(11) NULL DEFAULT NULL,
+  `actor_id` int(11) NULL DEFAULT NULL,
   `create_at` datetime(0) NOT NULL,
   `update_at` datetime(0) NOT NULL,
   PRIMARY KEY (`id`) USING BTREE,
   INDEX `payments_fk0`(`order_id`) USING BTREE,
-  CONSTRAINT `payments_fk0` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+  INDEX `payments_fk1`(`shift_id`) USING BTREE,
+  CONSTRAINT `payments_fk0` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+  CONSTRAINT `payments_fk1` FOREIGN KEY (`shift_id`) REFERENCES `shifts` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
 ) ENGINE = InnoDB AUTO_INCREMENT = 4 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
 
 -- ----------------------------
 -- Records of payments
 -- ----------------------------
-INSERT INTO `payments` VALUES (1, 1, 0, 'cash', 50000.00, '2021-11-14 17:52:09', '2021-11-14 17:52:09');
-INSERT INTO `payments` VALUES (2, 1, 0, 'cash', 50000.00, '2021-11-14 19:02:12', '2021-11-14 19:02:12');
-INSERT INTO `payments` VALUES (3, 14, 0, 'cash', 50000.00, '2021-11-14 19:10:11', '2021-11-14 19:10:11');
+INSERT INTO `payments` VALUES (1, 1, 0, 'cash', NULL, 50000.00, 50000.00, 0.00, 50000.00, NULL, NULL, '2021-11-14 17:52:09', '2021-11-14 17:52:09');
+INSERT INTO `payments` VALUES (2, 1, 0, 'cash', NULL, 50000.00, 50000.00, 0.00, 50000.00, NULL, NULL, '2021-11-14 19:02:12', '2021-11-14 19:02:12');
+INSERT INTO `payments` VALUES (3, 14, 0, 'cash', NULL, 50000.00, 50000.00, 0.00, 50000.00, NULL, NULL, '2021-11-14 19:10:11', '2021-11-14 19:10:11');
+
+-- ----------------------------
+-- Table structure for permissions
+-- ----------------------------
+DROP TABLE IF EXISTS `permissions`;
+CREATE TABLE `permissions`  (
+  `id` int(11) NOT NULL,
+  `name` varchar(50) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `description` varchar(255) CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL DEFAULT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `permissions_name`(`name`) USING BTREE
+) ENGINE = InnoDB CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of permissions
+-- ----------------------------
+INSERT INTO `permissions` VALUES (1, 'product.read', 'read products');
+INSERT INTO `permissions` VALUES (2, 'product.write', 'create, update and delete products');
+INSERT INTO `permissions` VALUES (3, 'product_outlet.read', 'read the products sold in an outlet');
+INSERT INTO `permissions` VALUES (4, 'product_outlet.write', 'add, price and remove the products of an outlet');
+INSERT INTO `permissions` VALUES (5, 'stock.update', 'update the stock of an outlet');
+INSERT INTO `permissions` VALUES (6, 'customer.read', 'read customers');
+INSERT INTO `permissions` VALUES (7, 'customer.write', 'create, update and delete customers');
+INSERT INTO `permissions` VALUES (8, 'supplier.read', 'read suppliers');
+INSERT INTO `permissions` VALUES (9, 'supplier.write', 'create, update and delete suppliers');
+INSERT INTO `permissions` VALUES (10, 'purchase.read', 'read purchases and purchase reports');
+INSERT INTO `permissions` VALUES (11, 'purchase.write', 'create, update and delete purchases');
+INSERT INTO `permissions` VALUES (12, 'media.upload', 'upload media files');
+INSERT INTO `permissions` VALUES (13, 'order.sell', 'sell: carts, payments, receipts and own shifts');
+INSERT INTO `permissions` VALUES (14, 'report.read', 'read sales and void reports');
+INSERT INTO `permissions` VALUES (15, 'refund.manage', 'refund paid orders');
+INSERT INTO `permissions` VALUES (16, 'receipt.template', 'edit the receipt template of an outlet');
+INSERT INTO `permissions` VALUES (17, 'receipt.reprint', 'reprint the receipt of any cashier');
+INSERT INTO `permissions` VALUES (18, 'shift.manage', 'list shifts, report and close the shift of any cashier');
+INSERT INTO `permissions` VALUES (19, 'outlet.all', 'work in every outlet without being assigned to it');
+INSERT INTO `permissions` VALUES (20, 'user.manage', 'manage the users and revoke their sessions');
+INSERT INTO `permissions` VALUES (21, 'terminal.manage', 'register and revoke the terminals of an outlet');
+INSERT INTO `permissions` VALUES (22, 'api_key.manage', 'create and revoke the API keys of the machine integrations');
+INSERT INTO `permissions` VALUES (23, 'user.impersonate', 'act as another user to reproduce a problem, every write is audited');
+INSERT INTO `permissions` VALUES (24, 'outlet.manage', 'create, edit and deactivate the outlets');
+
+-- ----------------------------
+-- Table structure for product_options
+-- ----------------------------
+DROP TABLE IF EXISTS `product_options`;
+CREATE TABLE `product_options`  (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `product_id` int(11) NOT NULL,
+  `position` tinyint(1) NOT NULL,
+  `name` varchar(50) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `create_at` datetime(0) NOT NULL,
+  `update_at` datetime(0) NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `product_options_position`(`product_id`, `position`) USING BTREE,
+  CONSTRAINT `product_options_fk0` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for product_variants
+-- ----------------------------
+DROP TABLE IF EXISTS `product_variants`;
+CREATE TABLE `product_variants`  (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `product_id` int(11) NOT NULL,
+  `name` varchar(160) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `option1` varchar(50) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL DEFAULT '',
+  `option2` varchar(50) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL DEFAULT '',
+  `option3` varchar(50) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL DEFAULT '',
+  `create_at` datetime(0) NOT NULL,
+  `update_at` datetime(0) NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `product_variants_options`(`product_id`, `option1`, `option2`, `option3`) USING BTREE,
+  CONSTRAINT `product_variants_fk0` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
 
 -- ----------------------------
 -- Table structure for products
@@ -246,6 +453,132 @@ CREATE TABLE `purchases`  (
 INSERT INTO `purchases` VALUES (1, 1, 50000.00, 10, 505000.00, 0, 5, 1, 1, 1, '2021-11-14 10:51:00', '2021-11-14 11:08:02');
 INSERT INTO `purchases` VALUES (2, 1, 50000.00, 10, 505000.00, 0, 5, 1, 1, 1, '2021-11-14 11:01:14', '2021-11-14 11:01:14');
 
+-- ----------------------------
+-- Table structure for receipt_templates
+-- ----------------------------
+DROP TABLE IF EXISTS `receipt_templates`;
+CREATE TABLE `receipt_templates`  (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `outlet_id` int(11) NOT NULL,
+  `header` varchar(255) CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL DEFAULT NULL,
+  `footer` varchar(255) CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL DEFAULT NULL,
+  `width` int(11) NOT NULL DEFAULT 32,
+  `body` text CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL,
+  `create_at` datetime(0) NOT NULL,
+  `update_at` datetime(0) NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `receipt_templates_outlet`(`outlet_id`) USING BTREE,
+  CONSTRAINT `receipt_templates_fk0` FOREIGN KEY (`outlet_id`) REFERENCES `outlets` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for refundItem
+-- ----------------------------
+DROP TABLE IF EXISTS `refundItem`;
+CREATE TABLE `refundItem`  (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `refund_id` int(11) NOT NULL,
+  `order_item_id` int(11) NOT NULL,
+  `product_id` int(11) NOT NULL,
+  `qty` int(11) NOT NULL,
+  `amount` decimal(12, 2) NOT NULL,
+  `create_at` datetime(0) NOT NULL,
+  `update_at` datetime(0) NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `refundItem_fk0`(`refund_id`) USING BTREE,
+  INDEX `refundItem_fk1`(`order_item_id`) USING BTREE,
+  CONSTRAINT `refundItem_fk0` FOREIGN KEY (`refund_id`) REFERENCES `refunds` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+  CONSTRAINT `refundItem_fk1` FOREIGN KEY (`order_item_id`) REFERENCES `orderItem` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for refund_payments
+-- ----------------------------
+DROP TABLE IF EXISTS `refund_payments`;
+CREATE TABLE `refund_payments`  (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `refund_id` int(11) NOT NULL,
+  `payment_id` int(11) NOT NULL,
+  `amount` decimal(12, 2) NOT NULL,
+  `shift_id` int(11) NULL DEFAULT NULL,
+  `create_at` datetime(0) NOT NULL,
+  `update_at` datetime(0) NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `refund_payments_refund`(`refund_id`, `payment_id`) USING BTREE,
+  INDEX `refund_payments_fk1`(`payment_id`) USING BTREE,
+  INDEX `refund_payments_fk2`(`shift_id`) USING BTREE,
+  CONSTRAINT `refund_payments_fk0` FOREIGN KEY (`refund_id`) REFERENCES `refunds` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+  CONSTRAINT `refund_payments_fk1` FOREIGN KEY (`payment_id`) REFERENCES `payments` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+  CONSTRAINT `refund_payments_fk2` FOREIGN KEY (`shift_id`) REFERENCES `shifts` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for refunds
+-- ----------------------------
+DROP TABLE IF EXISTS `refunds`;
+CREATE TABLE `refunds`  (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `order_id` int(11) NOT NULL,
+  `user_id` int(11) NOT NULL,
+  `actor_id` int(11) NULL DEFAULT NULL,
+  `reason` varchar(255) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `amount` decimal(12, 2) NOT NULL,
+  `create_at` datetime(0) NOT NULL,
+  `update_at` datetime(0) NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `refunds_fk0`(`order_id`) USING BTREE,
+  INDEX `refunds_fk2`(`user_id`) USING BTREE,
+  CONSTRAINT `refunds_fk0` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+  CONSTRAINT `refunds_fk2` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for role_permissions
+-- ----------------------------
+DROP TABLE IF EXISTS `role_permissions`;
+CREATE TABLE `role_permissions`  (
+  `role_id` int(11) NOT NULL,
+  `permission_id` int(11) NOT NULL,
+  PRIMARY KEY (`role_id`, `permission_id`) USING BTREE,
+  INDEX `role_permissions_fk1`(`permission_id`) USING BTREE,
+  CONSTRAINT `role_permissions_fk0` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON DELETE CASCADE ON UPDATE RESTRICT,
+  CONSTRAINT `role_permissions_fk1` FOREIGN KEY (`permission_id`) REFERENCES `permissions` (`id`) ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE = InnoDB CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of role_permissions
+-- ----------------------------
+INSERT INTO `role_permissions` VALUES (1, 1);
+INSERT INTO `role_permissions` VALUES (1, 2);
+INSERT INTO `role_permissions` VALUES (1, 3);
+INSERT INTO `role_permissions` VALUES (1, 4);
+INSERT INTO `role_permissions` VALUES (1, 5);
+INSERT INTO `role_permissions` VALUES (1, 6);
+INSERT INTO `role_permissions` VALUES (1, 7);
+INSERT INTO `role_permissions` VALUES (1, 8);
+INSERT INTO `role_permissions` VALUES (1, 9);
+INSERT INTO `role_permissions` VALUES (1, 10);
+INSERT INTO `role_permissions` VALUES (1, 11);
+INSERT INTO `role_permissions` VALUES (1, 12);
+INSERT INTO `role_permissions` VALUES (1, 13);
+INSERT INTO `role_permissions` VALUES (1, 14);
+INSERT INTO `role_permissions` VALUES (1, 15);
+INSERT INTO `role_permissions` VALUES (1, 16);
+INSERT INTO `role_permissions` VALUES (1, 17);
+INSERT INTO `role_permissions` VALUES (1, 18);
+INSERT INTO `role_permissions` VALUES (1, 19);
+INSERT INTO `role_permissions` VALUES (1, 20);
+INSERT INTO `role_permissions` VALUES (1, 21);
+INSERT INTO `role_permissions` VALUES (1, 22);
+INSERT INTO `role_permissions` VALUES (1, 23);
+INSERT INTO `role_permissions` VALUES (1, 24);
+INSERT INTO `role_permissions` VALUES (2, 1);
+INSERT INTO `role_permissions` VALUES (2, 3);
+INSERT INTO `role_permissions` VALUES (2, 5);
+INSERT INTO `role_permissions` VALUES (2, 6);
+INSERT INTO `role_permissions` VALUES (2, 7);
+INSERT INTO `role_permissions` VALUES (2, 13);
+
 -- ----------------------------
 -- Table structure for roles
 -- ----------------------------
@@ -262,6 +595,54 @@ CREATE TABLE `roles`  (
 INSERT INTO `roles` VALUES (1, 'super admin');
 INSERT INTO `roles` VALUES (2, 'merchant');
 
+-- ----------------------------
+-- Table structure for shift_movements
+-- ----------------------------
+DROP TABLE IF EXISTS `shift_movements`;
+CREATE TABLE `shift_movements`  (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `shift_id` int(11) NOT NULL,
+  `user_id` int(11) NOT NULL,
+  `actor_id` int(11) NULL DEFAULT NULL,
+  `type` enum('in','out') CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `amount` decimal(12, 2) NOT NULL,
+  `reason` varchar(255) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `create_at` datetime(0) NOT NULL,
+  `update_at` datetime(0) NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `shift_movements_fk0`(`shift_id`) USING BTREE,
+  INDEX `shift_movements_fk1`(`user_id`) USING BTREE,
+  CONSTRAINT `shift_movements_fk0` FOREIGN KEY (`shift_id`) REFERENCES `shifts` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+  CONSTRAINT `shift_movements_fk1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Table structure for shifts
+-- ----------------------------
+DROP TABLE IF EXISTS `shifts`;
+CREATE TABLE `shifts`  (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `user_id` int(11) NOT NULL,
+  `actor_id` int(11) NULL DEFAULT NULL,
+  `outlet_id` int(11) NOT NULL,
+  `status` enum('open','closed') CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL DEFAULT 'open',
+  `opening_float` decimal(12, 2) NOT NULL DEFAULT 0.00,
+  `expected_cash` decimal(12, 2) NULL DEFAULT NULL,
+  `counted_cash` decimal(12, 2) NULL DEFAULT NULL,
+  `note` varchar(255) CHARACTER SET latin1 COLLATE latin1_swedish_ci NULL DEFAULT NULL,
+  `opened_at` datetime(0) NOT NULL,
+  `closed_at` datetime(0) NULL DEFAULT NULL,
+  `create_at` datetime(0) NOT NULL,
+  `update_at` datetime(0) NOT NULL,
+  `open_key` tinyint(1) GENERATED ALWAYS AS (if((`status` = 'open'),1,NULL)) VIRTUAL NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `shifts_open`(`user_id`, `outlet_id`, `status`) USING BTREE,
+  UNIQUE INDEX `shifts_one_open`(`user_id`, `outlet_id`, `open_key`) USING BTREE,
+  INDEX `shifts_fk1`(`outlet_id`) USING BTREE,
+  CONSTRAINT `shifts_fk0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+  CONSTRAINT `shifts_fk1` FOREIGN KEY (`outlet_id`) REFERENCES `outlets` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
+
 -- ----------------------------
 -- Table structure for suppliers
 -- ----------------------------
@@ -283,6 +664,28 @@ CREATE TABLE `suppliers`  (
 INSERT INTO `suppliers` VALUES (1, 'PD. Ambang Raya 1', 'Jl. Kopo Gg. Panineungan 1 No 207', 'AMB', '2021-11-13 23:53:15', '2021-11-14 07:54:48');
 INSERT INTO `suppliers` VALUES (2, 'PD. Ambang Raya 2', 'Jl asdsadas', 'AMB', '2021-11-13 23:53:15', '2021-11-13 23:53:19');
 
+-- ----------------------------
+-- Table structure for terminals
+-- ----------------------------
+DROP TABLE IF EXISTS `terminals`;
+CREATE TABLE `terminals`  (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `outlet_id` int(11) NOT NULL,
+  `name` varchar(100) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `token_hash` char(64) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `status` enum('active','revoked') CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL DEFAULT 'active',
+  `created_by` int(11) NOT NULL,
+  `last_seen_at` datetime(0) NULL DEFAULT NULL,
+  `create_at` datetime(0) NOT NULL,
+  `update_at` datetime(0) NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `terminals_token`(`token_hash`) USING BTREE,
+  INDEX `terminals_fk0`(`outlet_id`) USING BTREE,
+  INDEX `terminals_fk1`(`created_by`) USING BTREE,
+  CONSTRAINT `terminals_fk0` FOREIGN KEY (`outlet_id`) REFERENCES `outlets` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+  CONSTRAINT `terminals_fk1` FOREIGN KEY (`created_by`) REFERENCES `users` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
+
 -- ----------------------------
 -- Table structure for user_outlets
 -- ----------------------------
@@ -302,6 +705,21 @@ CREATE TABLE `user_outlets`  (
 INSERT INTO `user_outlets` VALUES (7, 1);
 INSERT INTO `user_outlets` VALUES (7, 2);
 
+-- ----------------------------
+-- Table structure for user_recovery_codes
+-- ----------------------------
+DROP TABLE IF EXISTS `user_recovery_codes`;
+CREATE TABLE `user_recovery_codes`  (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `user_id` int(11) NOT NULL,
+  `code_hash` char(64) CHARACTER SET latin1 COLLATE latin1_swedish_ci NOT NULL,
+  `used_at` datetime(0) NULL DEFAULT NULL,
+  `create_at` datetime(0) NOT NULL,
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `user_recovery_codes_fk0`(`user_id`) USING BTREE,
+  CONSTRAINT `user_recovery_codes_fk0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = latin1 COLLATE = latin1_swedish_ci ROW_FORMAT = Dynamic;
+
 -- ----------------------------
 -- Table structure for users
 -- ----------------------------
@@ -312,7 +730,10 @@ CREATE TABLE `users`  (
   `username` varchar(100) CHARACTER SET utf8 COLLATE utf8_unicode_ci NOT NULL,
   `email` varchar(75) CHARACTER SET utf8 COLLATE utf8_unicode_ci NOT NULL,
   `password` varchar(255) CHARACTER SET utf8 COLLATE utf8_unicode_ci NOT NULL,
+  `pin` varchar(255) CHARACTER SET utf8 COLLATE utf8_unicode_ci NULL DEFAULT NULL,
+  `totp_secret` varchar(64) CHARACTER SET utf8 COLLATE utf8_unicode_ci NULL DEFAULT NULL,
   `role_id` int(11) NOT NULL,
+  `status` enum('active','suspended','pending') CHARACTER SET utf8 COLLATE utf8_unicode_ci NOT NULL DEFAULT 'active',
   `updated_at` datetime(0) NULL DEFAULT NULL,
   `created_at` datetime(0) NULL DEFAULT NULL,
   PRIMARY KEY (`id`) USING BTREE,
@@ -323,6 +744,6 @@ CREATE TABLE `users`  (
 -- ----------------------------
 -- Records of users
 -- ----------------------------
-INSERT INTO `users` VALUES (7, 'Admin', 'fachry45', 'admin@admin.com', '$2a$10$uybUyIVafxKlzBLi/1juSe91sauKjUBjUzRkTt361CNusWJMzF1Xq', 1, NULL, NULL);
+INSERT INTO `users` VALUES (7, 'Admin', 'fachry45', 'admin@admin.com', '$2a$10$uybUyIVafxKlzBLi/1juSe91sauKjUBjUzRkTt361CNusWJMzF1Xq', NULL, NULL, 1, 'active', NULL, '2021-10-01 00:00:00');
 
 SET FOREIGN_KEY_CHECKS = 1;
@@ -1,6 +1,10 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
 	"regexp"
 	"sync"
 	"time"
@@ -39,6 +43,19 @@ func ValidatePassword(s string) bool {
 	return letters >= 8 && number && upper && special
 }
 
+// ValidatePin return true if the PIN holds 4 to 6 digits
+func ValidatePin(pin string) bool {
+	if len(pin) < 4 || len(pin) > 6 {
+		return false
+	}
+	for _, c := range pin {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 func HashPassword(password string) (string, error) {
 	lock.Lock()
 	defer lock.Unlock()
@@ -53,6 +70,40 @@ func CheckPasswordHash(password, hash string) bool {
 	return err == nil
 }
 
+// RandomToken return a random hex string of n bytes, used for opaque tokens
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// RandomDigits return a random numeric code of n digits, used for OTPs
+func RandomDigits(n int) (string, error) {
+	b := make([]byte, n)
+	for i := range b {
+		d, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		b[i] = '0' + byte(d.Int64())
+	}
+	return string(b), nil
+}
+
+// HashToken return the sha256 of an opaque token, only the hash of a token is stored
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// UnixMilli return the milliseconds elapsed since the epoch, the precision of the session revocations
+func UnixMilli(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
 // DateString return string representation of a date pointer
 func DateString(dateTime *time.Time) string {
 	lock.Lock()